@@ -8,6 +8,7 @@ import (
 	apierrors "github.com/wearedevx/keystone/api/internal/errors"
 	"github.com/wearedevx/keystone/api/internal/router"
 	"github.com/wearedevx/keystone/api/pkg/models"
+	"github.com/wearedevx/keystone/api/pkg/notification"
 	"github.com/wearedevx/keystone/api/pkg/repo"
 )
 
@@ -70,3 +71,56 @@ func DeleteDevice(
 
 	return result, status, log.SetError(err)
 }
+
+// RotateDeviceKey function replaces the public key of a device,
+// and lets the members of the user's projects know about it
+func RotateDeviceKey(
+	params router.Params,
+	body io.ReadCloser,
+	Repo repo.IRepo,
+	user models.User,
+) (_ router.Serde, status int, err error) {
+	status = http.StatusOK
+	log := models.ActivityLog{
+		UserID: &user.ID,
+		Action: "RotateDeviceKey",
+	}
+
+	payload := models.RotateDeviceKeyPayload{}
+	device := models.Device{UID: params.Get("uid")}
+	var memberProjectsMap map[string][]string
+
+	if err = payload.Deserialize(body); err != nil ||
+		len(payload.PublicKey) == 0 {
+		status = http.StatusBadRequest
+		err = apierrors.ErrorBadRequest(err)
+		goto done
+	}
+
+	if err = Repo.GetDeviceByUserID(user.ID, &device).Err(); err != nil {
+		if errors.Is(err, repo.ErrorNotFound) {
+			status = http.StatusNotFound
+			err = apierrors.ErrorNoDevice()
+		} else {
+			status = http.StatusInternalServerError
+			err = apierrors.ErrorFailedToGetResource(err)
+		}
+		goto done
+	}
+
+	if err = Repo.RotateDeviceKey(&device, payload.PublicKey).Err(); err != nil {
+		status = http.StatusInternalServerError
+		err = apierrors.ErrorFailedToUpdateResource(err)
+		goto done
+	}
+
+	// The key has changed already, failing to notify members
+	// must not fail the request
+	if Repo.GetMembersFromUserProjects(user.ID, &memberProjectsMap).
+		Err() == nil {
+		_ = notification.SendEmailForRotatedKey(device, memberProjectsMap, user)
+	}
+
+done:
+	return &device, status, log.SetError(err)
+}
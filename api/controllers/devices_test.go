@@ -3,9 +3,12 @@
 package controllers
 
 import (
+	"errors"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/bxcodec/faker/v3"
@@ -168,6 +171,113 @@ func TestDeleteDevice(t *testing.T) {
 
 }
 
+func TestRotateDeviceKey(t *testing.T) {
+	Repo := new(repo.Repo)
+	user, device := seedDevice(Repo)
+	defer teardownDevice(user, device)
+
+	type args struct {
+		params router.Params
+		body   io.ReadCloser
+		Repo   repo.IRepo
+		user   models.User
+	}
+	tests := []struct {
+		name       string
+		args       args
+		wantStatus int
+		wantErr    string
+	}{
+		{
+			name: "rotates the key",
+			args: args{
+				params: router.ParamsFrom(map[string]string{
+					"uid": device.UID,
+				}),
+				// base64 for "new public key"
+				body: ioutil.NopCloser(strings.NewReader(
+					`{"public_key":"bmV3IHB1YmxpYyBrZXk="}`,
+				)),
+				Repo: Repo,
+				user: user,
+			},
+			wantStatus: http.StatusOK,
+			wantErr:    "",
+		},
+		{
+			name: "bad request without a key",
+			args: args{
+				params: router.ParamsFrom(map[string]string{
+					"uid": device.UID,
+				}),
+				body: ioutil.NopCloser(strings.NewReader(`{}`)),
+				Repo: Repo,
+				user: user,
+			},
+			wantStatus: http.StatusBadRequest,
+			wantErr:    "bad request",
+		},
+		{
+			name: "returns not found",
+			args: args{
+				params: router.ParamsFrom(map[string]string{
+					"uid": "that is no uid",
+				}),
+				body: ioutil.NopCloser(strings.NewReader(
+					`{"public_key":"bmV3IHB1YmxpYyBrZXk="}`,
+				)),
+				Repo: Repo,
+				user: user,
+			},
+			wantStatus: http.StatusNotFound,
+			wantErr:    "no device",
+		},
+		{
+			name: "fails on repo error",
+			args: args{
+				params: router.ParamsFrom(map[string]string{
+					"uid": device.UID,
+				}),
+				body: ioutil.NopCloser(strings.NewReader(
+					`{"public_key":"bmV3IHB1YmxpYyBrZXk="}`,
+				)),
+				Repo: newFakeRepo(map[string]error{
+					"RotateDeviceKey": errors.New("unexpected error"),
+				}),
+				user: user,
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantErr:    "failed to update",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, gotStatus, err := RotateDeviceKey(
+				tt.args.params,
+				tt.args.body,
+				tt.args.Repo,
+				tt.args.user,
+			)
+			if !strings.HasPrefix(err.Error(), tt.wantErr) {
+				t.Errorf("RotateDeviceKey() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotStatus != tt.wantStatus {
+				t.Errorf("RotateDeviceKey() gotStatus = %v, want %v", gotStatus, tt.wantStatus)
+			}
+		})
+	}
+
+	got := models.Device{UID: device.UID}
+	new(repo.Repo).GetDevice(&got)
+
+	if string(got.PublicKey) != "new public key" ||
+		!reflect.DeepEqual(got.PreviousPublicKey, device.PublicKey) ||
+		got.KeyRotatedAt == nil {
+		t.Errorf("RotateDeviceKey() did not rotate the key: %v", got)
+	}
+}
+
 func seedDevice(Repo *repo.Repo) (user models.User, device models.Device) {
 	db := Repo.GetDb()
 
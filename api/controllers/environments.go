@@ -78,3 +78,218 @@ func GetEnvironmentPublicKeys(
 done:
 	return &result, status, log.SetError(err)
 }
+
+// PostProjectEnvironment creates a user defined environment in a project.
+// The new environment gets the rights of its environment type.
+func PostProjectEnvironment(
+	params router.Params,
+	body io.ReadCloser,
+	Repo repo.IRepo,
+	user models.User,
+) (_ router.Serde, status int, err error) {
+	status = http.StatusCreated
+	payload := &models.EnvironmentPayload{}
+	project := models.Project{}
+	environmentType := models.EnvironmentType{}
+	environment := models.Environment{}
+
+	projectID := params.Get("projectID")
+
+	log := models.ActivityLog{
+		UserID: &user.ID,
+		Action: "PostProjectEnvironment",
+	}
+
+	if err = payload.Deserialize(body); err != nil {
+		status = http.StatusBadRequest
+		err = apierrors.ErrorBadRequest(err)
+		goto done
+	}
+
+	environmentType.Name = payload.EnvironmentType
+	if environmentType.Name == "" {
+		environmentType.Name = "dev"
+	}
+
+	if err = Repo.GetProjectByUUID(projectID, &project).
+		GetEnvironmentType(&environmentType).
+		Err(); err != nil {
+		if errors.Is(err, repo.ErrorNotFound) {
+			status = http.StatusNotFound
+		} else {
+			status = http.StatusInternalServerError
+			err = apierrors.ErrorFailedToGetResource(err)
+		}
+
+		goto done
+	}
+
+	log.ProjectID = &project.ID
+
+	if !Repo.ProjectIsMemberAdmin(
+		&project,
+		&models.ProjectMember{UserID: user.ID},
+	) {
+		status = http.StatusForbidden
+		err = apierrors.ErrorPermissionDenied()
+		goto done
+	}
+
+	environment = models.Environment{
+		Name:              payload.Name,
+		EnvironmentTypeID: environmentType.ID,
+		ProjectID:         project.ID,
+	}
+
+	if err = Repo.AddProjectEnvironment(&environment).Err(); err != nil {
+		switch {
+		case errors.Is(err, repo.ErrorBadName):
+			status = http.StatusBadRequest
+			err = apierrors.ErrorBadEnvironmentName()
+		case errors.Is(err, repo.ErrorNameTaken):
+			status = http.StatusConflict
+			err = apierrors.ErrorEnvironmentAlreadyExists()
+		default:
+			status = http.StatusInternalServerError
+			err = apierrors.ErrorFailedToCreateResource(err)
+		}
+
+		goto done
+	}
+
+	environment.EnvironmentType = environmentType
+	log.EnvironmentID = &environment.ID
+
+done:
+	return &environment, status, log.SetError(err)
+}
+
+// PutEnvironment renames a user defined environment
+func PutEnvironment(
+	params router.Params,
+	body io.ReadCloser,
+	Repo repo.IRepo,
+	user models.User,
+) (_ router.Serde, status int, err error) {
+	status = http.StatusOK
+	payload := &models.EnvironmentPayload{}
+
+	envID := params.Get("envID")
+	environment := models.Environment{EnvironmentID: envID}
+
+	log := models.ActivityLog{
+		UserID: &user.ID,
+		Action: "PutEnvironment",
+	}
+
+	if err = payload.Deserialize(body); err != nil {
+		status = http.StatusBadRequest
+		err = apierrors.ErrorBadRequest(err)
+		goto done
+	}
+
+	if status, err = getChangeableEnvironment(
+		Repo,
+		user,
+		&environment,
+		&log,
+	); err != nil {
+		goto done
+	}
+
+	if err = Repo.RenameEnvironment(&environment, payload.Name).
+		Err(); err != nil {
+		switch {
+		case errors.Is(err, repo.ErrorBadName):
+			status = http.StatusBadRequest
+			err = apierrors.ErrorBadEnvironmentName()
+		case errors.Is(err, repo.ErrorNameTaken):
+			status = http.StatusConflict
+			err = apierrors.ErrorEnvironmentAlreadyExists()
+		default:
+			status = http.StatusInternalServerError
+			err = apierrors.ErrorFailedToUpdateResource(err)
+		}
+
+		goto done
+	}
+
+done:
+	return &environment, status, log.SetError(err)
+}
+
+// DeleteEnvironment removes a user defined environment, and the messages
+// sent for it
+func DeleteEnvironment(
+	params router.Params,
+	_ io.ReadCloser,
+	Repo repo.IRepo,
+	user models.User,
+) (_ router.Serde, status int, err error) {
+	envID := params.Get("envID")
+	environment := models.Environment{EnvironmentID: envID}
+
+	log := models.ActivityLog{
+		UserID: &user.ID,
+		Action: "DeleteEnvironment",
+	}
+
+	if status, err = getChangeableEnvironment(
+		Repo,
+		user,
+		&environment,
+		&log,
+	); err != nil {
+		goto done
+	}
+
+	if err = Repo.DeleteEnvironment(&environment).Err(); err != nil {
+		status = http.StatusInternalServerError
+		err = apierrors.ErrorFailedToDeleteResource(err)
+		goto done
+	}
+
+	status = http.StatusNoContent
+	log.EnvironmentID = nil
+
+done:
+	return nil, status, log.SetError(err)
+}
+
+// getChangeableEnvironment fetches an environment that `user` is allowed
+// to rename or remove: only project admins can, and only for user defined
+// environments
+func getChangeableEnvironment(
+	Repo repo.IRepo,
+	user models.User,
+	environment *models.Environment,
+	log *models.ActivityLog,
+) (status int, err error) {
+	status = http.StatusOK
+
+	if err = Repo.GetEnvironment(environment).Err(); err != nil {
+		if errors.Is(err, repo.ErrorNotFound) {
+			return http.StatusNotFound, err
+		}
+
+		return http.StatusInternalServerError,
+			apierrors.ErrorFailedToGetResource(err)
+	}
+
+	log.ProjectID = &environment.ProjectID
+	log.EnvironmentID = &environment.ID
+
+	if !Repo.ProjectIsMemberAdmin(
+		&environment.Project,
+		&models.ProjectMember{UserID: user.ID},
+	) {
+		return http.StatusForbidden, apierrors.ErrorPermissionDenied()
+	}
+
+	if environment.IsDefault() {
+		return http.StatusBadRequest,
+			apierrors.ErrorDefaultEnvironmentCannotChange()
+	}
+
+	return status, nil
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/bxcodec/faker/v3"
@@ -296,3 +297,210 @@ func teardownEnvironment(
 		devUser.ID,
 	)
 }
+
+func TestPostProjectEnvironment(t *testing.T) {
+	Repo := new(repo.Repo)
+	adminUser, devUser, environments := seedEnvironmentPublicKeys(Repo)
+	projectUUID := ""
+	project := models.Project{}
+	Repo.GetDb().First(&project, environments["dev"].ProjectID)
+	projectUUID = project.UUID
+
+	tests := []struct {
+		name       string
+		user       models.User
+		body       string
+		repo       repo.IRepo
+		wantStatus int
+		wantErr    string
+	}{
+		{
+			name:       "creates an environment",
+			user:       adminUser,
+			body:       `{"name":"qa","environment_type":"staging"}`,
+			repo:       newFakeRepo(noCrashers),
+			wantStatus: http.StatusCreated,
+			wantErr:    "",
+		},
+		{
+			name:       "name is already taken",
+			user:       adminUser,
+			body:       `{"name":"prod","environment_type":"prod"}`,
+			repo:       newFakeRepo(noCrashers),
+			wantStatus: http.StatusConflict,
+			wantErr:    "environment already exists",
+		},
+		{
+			name:       "bad environment name",
+			user:       adminUser,
+			body:       `{"name":"Not A Name"}`,
+			repo:       newFakeRepo(noCrashers),
+			wantStatus: http.StatusBadRequest,
+			wantErr:    "bad environment name",
+		},
+		{
+			name:       "unknown environment type",
+			user:       adminUser,
+			body:       `{"name":"sandbox","environment_type":"sandbox"}`,
+			repo:       newFakeRepo(noCrashers),
+			wantStatus: http.StatusNotFound,
+			wantErr:    "not found",
+		},
+		{
+			name:       "non admin cannot create environments",
+			user:       devUser,
+			body:       `{"name":"preview"}`,
+			repo:       newFakeRepo(noCrashers),
+			wantStatus: http.StatusForbidden,
+			wantErr:    "permission denied",
+		},
+		{
+			name: "fails to create the environment",
+			user: adminUser,
+			body: `{"name":"demo"}`,
+			repo: newFakeRepo(map[string]error{
+				"AddProjectEnvironment": errors.New("unexpected error"),
+			}),
+			wantStatus: http.StatusInternalServerError,
+			wantErr:    "failed to create resource: unexpected error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotStatus, err := PostProjectEnvironment(
+				router.ParamsFrom(map[string]string{
+					"projectID": projectUUID,
+				}),
+				io.NopCloser(strings.NewReader(tt.body)),
+				tt.repo,
+				tt.user,
+			)
+			if err.Error() != tt.wantErr {
+				t.Errorf(
+					"PostProjectEnvironment() error = %v, wantErr %v",
+					err,
+					tt.wantErr,
+				)
+				return
+			}
+
+			if gotStatus != tt.wantStatus {
+				t.Errorf(
+					"PostProjectEnvironment() gotStatus = %v, want %v",
+					gotStatus,
+					tt.wantStatus,
+				)
+				return
+			}
+
+			if tt.wantStatus == http.StatusCreated {
+				environment := got.(*models.Environment)
+				if environment.EnvironmentID == "" ||
+					environment.EnvironmentType.Name != "staging" {
+					t.Errorf(
+						"PostProjectEnvironment() got = %v",
+						environment,
+					)
+				}
+			}
+		})
+	}
+}
+
+func TestPutAndDeleteEnvironment(t *testing.T) {
+	Repo := new(repo.Repo)
+	adminUser, devUser, environments := seedEnvironmentPublicKeys(Repo)
+
+	custom := models.Environment{
+		Name:              "preview",
+		EnvironmentTypeID: environments["dev"].EnvironmentTypeID,
+		ProjectID:         environments["dev"].ProjectID,
+	}
+	if err := Repo.AddProjectEnvironment(&custom).Err(); err != nil {
+		t.Fatalf("failed to seed: %v", err)
+	}
+
+	params := func(env models.Environment) router.Params {
+		return router.ParamsFrom(map[string]string{
+			"envID": env.EnvironmentID,
+		})
+	}
+	body := func(s string) io.ReadCloser {
+		return io.NopCloser(strings.NewReader(s))
+	}
+
+	_, status, err := PutEnvironment(
+		params(environments["prod"]),
+		body(`{"name":"production"}`),
+		newFakeRepo(noCrashers),
+		adminUser,
+	)
+	if status != http.StatusBadRequest ||
+		err.Error() != "default environment cannot change" {
+		t.Errorf("PutEnvironment() on prod: status = %d, err = %v", status, err)
+	}
+
+	_, status, err = PutEnvironment(
+		params(custom),
+		body(`{"name":"review"}`),
+		newFakeRepo(noCrashers),
+		devUser,
+	)
+	if status != http.StatusForbidden || err.Error() != "permission denied" {
+		t.Errorf("PutEnvironment() as dev: status = %d, err = %v", status, err)
+	}
+
+	_, status, err = PutEnvironment(
+		params(custom),
+		body(`{"name":"staging"}`),
+		newFakeRepo(noCrashers),
+		adminUser,
+	)
+	if status != http.StatusConflict ||
+		err.Error() != "environment already exists" {
+		t.Errorf("PutEnvironment() to staging: status = %d, err = %v", status, err)
+	}
+
+	got, status, err := PutEnvironment(
+		params(custom),
+		body(`{"name":"review"}`),
+		newFakeRepo(noCrashers),
+		adminUser,
+	)
+	if status != http.StatusOK || err.Error() != "" {
+		t.Errorf("PutEnvironment(): status = %d, err = %v", status, err)
+	} else if got.(*models.Environment).Name != "review" {
+		t.Errorf("PutEnvironment() got = %v", got)
+	}
+
+	_, status, err = DeleteEnvironment(
+		params(environments["dev"]),
+		nil,
+		newFakeRepo(noCrashers),
+		adminUser,
+	)
+	if status != http.StatusBadRequest ||
+		err.Error() != "default environment cannot change" {
+		t.Errorf("DeleteEnvironment() on dev: status = %d, err = %v", status, err)
+	}
+
+	_, status, err = DeleteEnvironment(
+		params(custom),
+		nil,
+		newFakeRepo(noCrashers),
+		adminUser,
+	)
+	if status != http.StatusNoContent || err.Error() != "" {
+		t.Errorf("DeleteEnvironment(): status = %d, err = %v", status, err)
+	}
+
+	_, status, _ = DeleteEnvironment(
+		params(custom),
+		nil,
+		newFakeRepo(noCrashers),
+		adminUser,
+	)
+	if status != http.StatusNotFound {
+		t.Errorf("DeleteEnvironment() twice: status = %d", status)
+	}
+}
@@ -214,6 +214,19 @@ func (f *fakeRepo) SetNewVersionID(environment *models.Environment) error {
 	return f.Repo.SetNewVersionID(environment)
 }
 
+func (f *fakeRepo) AddProjectEnvironment(environment *models.Environment) repo.IRepo {
+	if f.err != nil {
+		return f
+	}
+	f.called = append(f.called, "AddProjectEnvironment")
+	if e, ok := f.crashers["AddProjectEnvironment"]; ok {
+		f.err = e
+		return f
+	}
+	f.Repo.AddProjectEnvironment(environment)
+	return f
+}
+
 func (f *fakeRepo) CreateEnvironment(environment *models.Environment) repo.IRepo {
 	if f.err != nil {
 		return f
@@ -355,6 +368,19 @@ func (f *fakeRepo) DeleteProject(project *models.Project) repo.IRepo {
 	return f
 }
 
+func (f *fakeRepo) DeleteEnvironment(environment *models.Environment) repo.IRepo {
+	if f.err != nil {
+		return f
+	}
+	f.called = append(f.called, "DeleteEnvironment")
+	if e, ok := f.crashers["DeleteEnvironment"]; ok {
+		f.err = e
+		return f
+	}
+	f.Repo.DeleteEnvironment(environment)
+	return f
+}
+
 func (f *fakeRepo) DeleteProjectsEnvironments(project *models.Project) repo.IRepo {
 	if f.err != nil {
 		return f
@@ -493,6 +519,19 @@ func (f *fakeRepo) GetMessage(message *models.Message) repo.IRepo {
 	return f
 }
 
+func (f *fakeRepo) RenameEnvironment(environment *models.Environment, name string) repo.IRepo {
+	if f.err != nil {
+		return f
+	}
+	f.called = append(f.called, "RenameEnvironment")
+	if e, ok := f.crashers["RenameEnvironment"]; ok {
+		f.err = e
+		return f
+	}
+	f.Repo.RenameEnvironment(environment, name)
+	return f
+}
+
 func (f *fakeRepo) GetOrCreateEnvironment(environment *models.Environment) repo.IRepo {
 	if f.err != nil {
 		return f
@@ -931,6 +970,19 @@ func (f *fakeRepo) RevokeDevice(userID uint, deviceUID string) repo.IRepo {
 	return f
 }
 
+func (f *fakeRepo) RotateDeviceKey(device *models.Device, publicKey []byte) repo.IRepo {
+	if f.err != nil {
+		return f
+	}
+	f.called = append(f.called, "RotateDeviceKey")
+	if e, ok := f.crashers["RotateDeviceKey"]; ok {
+		f.err = e
+		return f
+	}
+	f.Repo.RotateDeviceKey(device, publicKey)
+	return f
+}
+
 func (f *fakeRepo) GetAdminsFromUserProjects(userID uint, adminProjectsMap *map[string][]string) repo.IRepo {
 	if f.err != nil {
 		return f
@@ -944,6 +996,19 @@ func (f *fakeRepo) GetAdminsFromUserProjects(userID uint, adminProjectsMap *map[
 	return f
 }
 
+func (f *fakeRepo) GetMembersFromUserProjects(userID uint, memberProjectsMap *map[string][]string) repo.IRepo {
+	if f.err != nil {
+		return f
+	}
+	f.called = append(f.called, "GetMembersFromUserProjects")
+	if e, ok := f.crashers["GetMembersFromUserProjects"]; ok {
+		f.err = e
+		return f
+	}
+	f.Repo.GetMembersFromUserProjects(userID, memberProjectsMap)
+	return f
+}
+
 func (f *fakeRepo) CreateOrganization(orga *models.Organization) repo.IRepo {
 	if f.err != nil {
 		return f
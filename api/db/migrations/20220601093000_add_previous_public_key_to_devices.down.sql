@@ -0,0 +1,3 @@
+ALTER TABLE public.devices
+  DROP COLUMN IF EXISTS previous_public_key,
+  DROP COLUMN IF EXISTS key_rotated_at;
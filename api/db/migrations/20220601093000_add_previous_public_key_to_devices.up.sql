@@ -0,0 +1,3 @@
+ALTER TABLE public.devices
+  ADD COLUMN IF NOT EXISTS previous_public_key bytea,
+  ADD COLUMN IF NOT EXISTS key_rotated_at timestamptz;
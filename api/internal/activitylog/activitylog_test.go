@@ -204,6 +204,10 @@ func newFakeRepo() *FakeRepo {
 	return f
 }
 
+func (f *FakeRepo) AddProjectEnvironment(_ *models.Environment) repo.IRepo {
+	panic("not implemented")
+}
+
 func (f *FakeRepo) CreateEnvironment(_ *models.Environment) repo.IRepo {
 	panic("not implemented")
 }
@@ -347,6 +351,14 @@ func (f *FakeRepo) GetMessagesForUserOnEnvironment(
 	panic("not implemented")
 }
 
+func (f *FakeRepo) DeleteEnvironment(_ *models.Environment) repo.IRepo {
+	panic("not implemented")
+}
+
+func (f *FakeRepo) RenameEnvironment(_ *models.Environment, _ string) repo.IRepo {
+	panic("not implemented")
+}
+
 func (f *FakeRepo) GetOrCreateEnvironment(_ *models.Environment) repo.IRepo {
 	panic("not implemented")
 }
@@ -572,6 +584,20 @@ func (f *FakeRepo) RevokeDevice(userID uint, deviceUID string) repo.IRepo {
 	panic("not implemented")
 }
 
+func (f *FakeRepo) RotateDeviceKey(
+	device *models.Device,
+	publicKey []byte,
+) repo.IRepo {
+	panic("not implemented")
+}
+
+func (f *FakeRepo) GetMembersFromUserProjects(
+	userID uint,
+	memberProjectsMap *map[string][]string,
+) repo.IRepo {
+	panic("not implemented")
+}
+
 func (f *FakeRepo) GetAdminsFromUserProjects(
 	userID uint,
 	adminProjectsMap *map[string][]string,
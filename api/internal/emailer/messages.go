@@ -133,6 +133,39 @@ You should also change your access to the identity provider you chose to connect
 Have a nice day!
 </p>
 
+<p>
+The Keystone team
+</p>
+`),
+	)
+
+	templates["key_rotated/html"] = template.Must(
+		template.New("key_rotated/html").Parse(`
+<p>
+	Hello!
+</p>
+<p>
+{{.UserID}} has replaced the key of one of its devices.
+</p>
+<p>
+You are a member of some of its project(s): {{.Projects}}
+</p>
+<p>
+The device name is: {{.DeviceName}}
+</p>
+<p>
+Secrets and files sent to this device from now on are encrypted with its new key.
+Environments you sent it before may no longer be readable once the old key expires,
+so consider sending them again:
+</p>
+<p style=" background-color:#f6f8fa ; border-radius: 6px; font-size: 95%; line-height: 1.45; overflow: auto; padding: 16px; "> $ ks env send </p>
+<p>
+If you think this change is suspicious, feel free to contact {{.UserID}}.
+</p>
+<p>
+Have a nice day!
+</p>
+
 <p>
 The Keystone team
 </p>
@@ -195,6 +228,12 @@ type newDeviceData struct {
 	UserID     string
 }
 
+type keyRotatedData struct {
+	Projects   string
+	UserID     string
+	DeviceName string
+}
+
 type GroupedMessageProject struct {
 	Project      models.Project
 	Environments map[string]models.Environment
@@ -335,6 +374,26 @@ func renderNewDevice(
 	return html, text, nil
 }
 
+func renderKeyRotated(
+	userID string,
+	projects []string,
+	deviceName string,
+) (html string, text string, err error) {
+	html, text, err = renderTemplate(
+		"key_rotated/html",
+		keyRotatedData{
+			UserID:     userID,
+			Projects:   strings.Join(projects, ", "),
+			DeviceName: deviceName,
+		},
+	)
+	if err != nil {
+		return "", "", err
+	}
+
+	return html, text, nil
+}
+
 func renderMessageWillExpire(
 	nbDays int,
 	groupedProjects map[uint]GroupedMessageProject,
@@ -431,6 +490,27 @@ func NewDeviceMail(deviceName string, userID string) (email *Email, err error) {
 	return email, nil
 }
 
+func KeyRotatedMail(
+	userID string,
+	projects []string,
+	deviceName string,
+) (email *Email, err error) {
+	html, text, err := renderKeyRotated(userID, projects, deviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	email = &Email{
+		FromEmail: KEYSTONE_MAIL,
+		FromName:  "Keystone",
+		Subject:   fmt.Sprintf("%s has replaced the key of a device", userID),
+		HtmlBody:  html,
+		TextBody:  text,
+	}
+
+	return email, nil
+}
+
 func MessageWillExpireMail(
 	nbDays int,
 	groupedProjects map[uint]GroupedMessageProject,
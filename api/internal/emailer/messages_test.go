@@ -385,6 +385,105 @@ DevX, 2 av Président Pierre Angot, 64000 Pau, Nouvelle-Aquitaine, France`,
 	}
 }
 
+func TestKeyRotatedMail(t *testing.T) {
+	type args struct {
+		userID     string
+		projects   []string
+		deviceName string
+	}
+	tests := []struct {
+		name      string
+		args      args
+		wantEmail *Email
+		wantErr   bool
+	}{
+		{
+			name: "key-rotated-ok",
+			args: args{
+				userID: "memberx@github",
+				projects: []string{
+					"this-one",
+					"this-other-one",
+				},
+				deviceName: "The-Computer",
+			},
+			wantEmail: &Email{
+				FromEmail: KEYSTONE_MAIL,
+				FromName:  "Keystone",
+				To:        []string{},
+				Subject:   "memberx@github has replaced the key of a device",
+				HtmlBody:  "",
+				TextBody: `Hello!
+
+memberx@github has replaced the key of one of its devices.
+
+You are a member of some of its project(s): this-one, this-other-one
+
+The device name is: The-Computer
+
+Secrets and files sent to this device from now on are encrypted with its new key. Environments you sent it before may no longer be readable once the old key expires, so consider sending them again:
+
+$ ks env send
+
+If you think this change is suspicious, feel free to contact memberx@github.
+
+Have a nice day!
+
+The Keystone team
+
+DevX, 2 av Président Pierre Angot, 64000 Pau, Nouvelle-Aquitaine, France`,
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotEmail, err := KeyRotatedMail(
+				tt.args.userID,
+				tt.args.projects,
+				tt.args.deviceName,
+			)
+			if (err != nil) != tt.wantErr {
+				t.Errorf(
+					"KeyRotatedMail() error = %v, wantErr %v",
+					err,
+					tt.wantErr,
+				)
+				return
+			}
+
+			if gotEmail.FromName != tt.wantEmail.FromName {
+				t.Errorf(
+					"KeyRotatedMail() FromName = %v, want %v",
+					gotEmail.FromName,
+					tt.wantEmail.FromName,
+				)
+			}
+			if gotEmail.FromEmail != tt.wantEmail.FromEmail {
+				t.Errorf(
+					"KeyRotatedMail() FromEmail = %v, want %v",
+					gotEmail.FromEmail,
+					tt.wantEmail.FromEmail,
+				)
+			}
+			if gotEmail.Subject != tt.wantEmail.Subject {
+				t.Errorf(
+					"KeyRotatedMail() Subject = %v, want %v",
+					gotEmail.Subject,
+					tt.wantEmail.Subject,
+				)
+			}
+
+			got := trimNonBreaking(gotEmail.TextBody)
+			want := emailText(tt.wantEmail.TextBody)
+
+			if !charCmp(got, want) {
+				t.Errorf("KeyRotatedMail() TextBody = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
 func TestMessageWillExpireMail(t *testing.T) {
 	type args struct {
 		nbDays          int
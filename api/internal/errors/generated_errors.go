@@ -127,3 +127,15 @@ func ErrorMemberAlreadyInProject() error {
 func ErrorNotAMember() error {
 	return newError("not a member", nil)
 }
+
+func ErrorBadEnvironmentName() error {
+	return newError("bad environment name", nil)
+}
+
+func ErrorEnvironmentAlreadyExists() error {
+	return newError("environment already exists", nil)
+}
+
+func ErrorDefaultEnvironmentCannotChange() error {
+	return newError("default environment cannot change", nil)
+}
@@ -164,6 +164,11 @@ func newFakeRepo() *FakeRepo {
 	return f
 }
 
+func (f *FakeRepo) AddProjectEnvironment(_ *Environment) IRepo {
+	f.called = append(f.called, "AddProjectEnvironment")
+	return f
+}
+
 func (f *FakeRepo) CreateEnvironment(_ *Environment) IRepo {
 	f.called = append(f.called, "CreateEnvironment")
 	return f
@@ -271,6 +276,16 @@ func (f *FakeRepo) GetMessagesForUserOnEnvironment(
 	return f
 }
 
+func (f *FakeRepo) DeleteEnvironment(_ *Environment) IRepo {
+	f.called = append(f.called, "DeleteEnvironment")
+	return f
+}
+
+func (f *FakeRepo) RenameEnvironment(_ *Environment, _ string) IRepo {
+	f.called = append(f.called, "RenameEnvironment")
+	return f
+}
+
 func (f *FakeRepo) GetOrCreateEnvironment(_ *Environment) IRepo {
 	f.called = append(f.called, "GetOrCreateEnvironment")
 	return f
@@ -554,6 +569,22 @@ func (f *FakeRepo) RevokeDevice(userID uint, deviceUID string) IRepo {
 	return f
 }
 
+func (f *FakeRepo) RotateDeviceKey(
+	device *models.Device,
+	publicKey []byte,
+) IRepo {
+	f.called = append(f.called, "RotateDeviceKey")
+	return f
+}
+
+func (f *FakeRepo) GetMembersFromUserProjects(
+	userID uint,
+	memberProjectsMap *map[string][]string,
+) IRepo {
+	f.called = append(f.called, "GetMembersFromUserProjects")
+	return f
+}
+
 func (f *FakeRepo) GetAdminsFromUserProjects(
 	userID uint,
 	adminProjectsMap *map[string][]string,
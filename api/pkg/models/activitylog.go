@@ -159,6 +159,7 @@ const (
 	ActionFilterGetProjectsOrganization      ActionFilter = "GetProjectsOrganization"
 	ActionFilterGetDevices                   ActionFilter = "GetDevices"
 	ActionFilterDeleteDevice                 ActionFilter = "DeleteDevice"
+	ActionFilterRotateDeviceKey              ActionFilter = "RotateDeviceKey"
 	ActionFilterGetOrganizations             ActionFilter = "GetOrganizations"
 	ActionFilterPostOrganization             ActionFilter = "PostOrganization"
 	ActionFilterGetEnvironmentPublicKeys     ActionFilter = "GetEnvironmentPublicKeys"
@@ -191,6 +192,7 @@ func (af ActionFilter) Validate() (ok bool) {
 		ActionFilterGetProjectsOrganization,
 		ActionFilterGetDevices,
 		ActionFilterDeleteDevice,
+		ActionFilterRotateDeviceKey,
 		ActionFilterGetOrganizations,
 		ActionFilterPostOrganization,
 		ActionFilterGetEnvironmentPublicKeys,
@@ -220,15 +222,10 @@ const (
 	EnvironmentFilterProd    EnvironmentFilter = "prod"
 )
 
+// Validate returns true if the filter is a valid environment name,
+// user-defined environments included
 func (ef EnvironmentFilter) Validate() (ok bool) {
-	switch ef {
-	case EnvironmentFilterDev,
-		EnvironmentFilterStaging,
-		EnvironmentFilterProd:
-		return true
-	default:
-		return false
-	}
+	return IsValidEnvironmentName(string(ef))
 }
 
 type GetLogsOptions struct {
@@ -280,7 +277,7 @@ func (o *GetLogsOptions) SetEnvironments(environments string) *GetLogsOptions {
 
 	for _, environment := range strings.Split(environments, ",") {
 		if e := EnvironmentFilter(strings.TrimSpace(environment)); e.Validate() {
-			o.Environments = append(o.Environments, EnvironmentFilter(environment))
+			o.Environments = append(o.Environments, e)
 		}
 	}
 
@@ -10,15 +10,17 @@ import (
 )
 
 type Device struct {
-	ID         uint           `json:"id"           gorm:"primaryKey" faker:"-"`
-	PublicKey  []byte         `json:"public_key"   gorm:"type:bytea"`
-	Name       string         `json:"name"`
-	UID        string         `json:"uid" faker:"uuid_hyphenated"`
-	Users      []User         `json:"users"        gorm:"many2many:user_devices;" faker:"-"`
-	LastUsedAt time.Time      `json:"last_used_at"`
-	CreatedAt  time.Time      `json:"created_at"`
-	UpdatedAt  time.Time      `json:"updated_at"`
-	DeletedAt  gorm.DeletedAt `json:"-" faker:"-"`
+	ID                uint           `json:"id"           gorm:"primaryKey" faker:"-"`
+	PublicKey         []byte         `json:"public_key"   gorm:"type:bytea"`
+	PreviousPublicKey []byte         `json:"previous_public_key" gorm:"type:bytea" faker:"-"`
+	KeyRotatedAt      *time.Time     `json:"key_rotated_at" faker:"-"`
+	Name              string         `json:"name"`
+	UID               string         `json:"uid" faker:"uuid_hyphenated"`
+	Users             []User         `json:"users"        gorm:"many2many:user_devices;" faker:"-"`
+	LastUsedAt        time.Time      `json:"last_used_at"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+	DeletedAt         gorm.DeletedAt `json:"-" faker:"-"`
 }
 
 func (pm *Device) BeforeCreate(tx *gorm.DB) (err error) {
@@ -85,3 +87,21 @@ func (pm *RemoveDeviceResponse) Serialize(out *string) (err error) {
 
 	return err
 }
+
+type RotateDeviceKeyPayload struct {
+	PublicKey []byte `json:"public_key"`
+}
+
+func (pm *RotateDeviceKeyPayload) Deserialize(in io.Reader) error {
+	return json.NewDecoder(in).Decode(pm)
+}
+
+func (pm *RotateDeviceKeyPayload) Serialize(out *string) (err error) {
+	var sb strings.Builder
+
+	err = json.NewEncoder(&sb).Encode(pm)
+
+	*out = sb.String()
+
+	return err
+}
@@ -3,6 +3,7 @@ package models
 import (
 	"encoding/json"
 	"io"
+	"regexp"
 	"strings"
 	"time"
 
@@ -18,12 +19,20 @@ type Environment struct {
 	ProjectID         uint            `json:"project_id"`
 	Project           Project         `json:"project"                               faker:"-"`
 	VersionID         string          `json:"version_id"                            faker:"word,unique"`
-	EnvironmentID     string          `json:"environment_id"                        faker:"word,unique"`
+	EnvironmentID     string          `json:"environment_id"                        gorm:"uniqueIndex:idx_environments_environment_id" faker:"word,unique"`
 
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+var environmentNameRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9\-\_]{0,62}$`)
+
+// IsValidEnvironmentName returns true if `name` can be used
+// as an environment name
+func IsValidEnvironmentName(name string) bool {
+	return environmentNameRegex.MatchString(name)
+}
+
 func (e *Environment) BeforeCreate(tx *gorm.DB) (err error) {
 	e.CreatedAt = time.Now()
 	e.UpdatedAt = time.Now()
@@ -39,6 +48,12 @@ func (e *Environment) BeforeUpdate(tx *gorm.DB) (err error) {
 	return nil
 }
 
+// IsDefault method returns true for the environments created with the
+// project (dev, staging and prod), which cannot be renamed or removed
+func (e *Environment) IsDefault() bool {
+	return e.Name == e.EnvironmentType.Name
+}
+
 func (e *Environment) Deserialize(in io.Reader) error {
 	return json.NewDecoder(in).Decode(e)
 }
@@ -71,6 +86,27 @@ func (u *GetEnvironmentsResponse) Serialize(out *string) (err error) {
 	return err
 }
 
+// EnvironmentPayload is the body of requests creating or renaming
+// an environment
+type EnvironmentPayload struct {
+	Name            string `json:"name"`
+	EnvironmentType string `json:"environment_type,omitempty"`
+}
+
+func (e *EnvironmentPayload) Deserialize(in io.Reader) error {
+	return json.NewDecoder(in).Decode(e)
+}
+
+func (e *EnvironmentPayload) Serialize(out *string) (err error) {
+	var sb strings.Builder
+
+	err = json.NewEncoder(&sb).Encode(e)
+
+	*out = sb.String()
+
+	return err
+}
+
 type EnvironmentUserSecret struct {
 	EnvironmentID uint      `json:"environmentID" gorm:"primaryKey"`
 	UserID        uint      `json:"userID"        gorm:"primaryKey"`
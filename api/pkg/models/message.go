@@ -57,6 +57,8 @@ type File struct {
 	Path string `json:"path"`
 	// TODO: maybe have consistent names for struct fields and json fields
 	Value string `json:"content"`
+	// Permission bits of the file, 0 when unknown
+	Mode uint32 `json:"mode,omitempty"`
 }
 
 type SecretVal struct {
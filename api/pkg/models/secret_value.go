@@ -0,0 +1,70 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"unicode/utf8"
+)
+
+// SecretEncodingBase64 marks secret values encoded in base64.
+// JSON strings must be valid UTF-8, binary values would not survive it.
+const SecretEncodingBase64 = "base64"
+
+// EncodeSecretValue function returns `value` as it should be written in
+// JSON, and its encoding, which is empty for text values
+func EncodeSecretValue(value string) (encoded string, encoding string) {
+	if utf8.ValidString(value) {
+		return value, ""
+	}
+
+	return base64.StdEncoding.EncodeToString([]byte(value)), SecretEncodingBase64
+}
+
+// DecodeSecretValue function returns the original value of a secret
+// encoded with EncodeSecretValue
+func DecodeSecretValue(encoded string, encoding string) (string, error) {
+	switch encoding {
+	case "":
+		return encoded, nil
+
+	case SecretEncodingBase64:
+		value, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return "", err
+		}
+
+		return string(value), nil
+	}
+
+	return "", fmt.Errorf("unknown secret encoding %q", encoding)
+}
+
+type secretValJSON struct {
+	Label    string `json:"label"`
+	Value    string `json:"value"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+func (s SecretVal) MarshalJSON() ([]byte, error) {
+	value, encoding := EncodeSecretValue(s.Value)
+
+	return json.Marshal(secretValJSON{
+		Label:    s.Label,
+		Value:    value,
+		Encoding: encoding,
+	})
+}
+
+func (s *SecretVal) UnmarshalJSON(data []byte) (err error) {
+	var raw secretValJSON
+
+	if err = json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	s.Label = raw.Label
+	s.Value, err = DecodeSecretValue(raw.Value, raw.Encoding)
+
+	return err
+}
@@ -90,3 +90,29 @@ func SendAddedMemberEmail(memberRoles []models.MemberRole, project models.Projec
 	}
 	return nil
 }
+
+// SendEmailForRotatedKey sends an email to the members of the projects of
+// `user`, telling them the key of `device` has changed
+func SendEmailForRotatedKey(
+	device models.Device,
+	memberProjectsMap map[string][]string,
+	user models.User,
+) error {
+	for memberEmail, projectList := range memberProjectsMap {
+		e, err := emailer.KeyRotatedMail(
+			user.UserID,
+			projectList,
+			device.Name,
+		)
+		if err != nil {
+			return err
+		}
+
+		if err = e.Send([]string{memberEmail}); err != nil {
+			fmt.Printf("Key Rotated Mail err: %+v\n", err)
+			return err
+		}
+	}
+
+	return nil
+}
@@ -125,6 +125,28 @@ func (r *Repo) RevokeDevice(userID uint, deviceUID string) IRepo {
 	return r
 }
 
+// RotateDeviceKey method replaces the public key of `device` with
+// `publicKey`, keeping the current one as its previous public key
+func (r *Repo) RotateDeviceKey(device *models.Device, publicKey []byte) IRepo {
+	if r.Err() != nil {
+		return r
+	}
+
+	now := time.Now()
+
+	device.PreviousPublicKey = device.PublicKey
+	device.PublicKey = publicKey
+	device.KeyRotatedAt = &now
+
+	r.err = r.GetDb().
+		Model(device).
+		Select("PublicKey", "PreviousPublicKey", "KeyRotatedAt").
+		Updates(device).
+		Error
+
+	return r
+}
+
 func (r *Repo) AddNewDevice(
 	device models.Device,
 	user models.User,
@@ -5,6 +5,7 @@ import (
 
 	uuid "github.com/satori/go.uuid"
 	"github.com/wearedevx/keystone/api/pkg/models"
+	"gorm.io/gorm"
 )
 
 func (repo *Repo) CreateEnvironment(environment *models.Environment) IRepo {
@@ -17,6 +18,123 @@ func (repo *Repo) CreateEnvironment(environment *models.Environment) IRepo {
 	return repo
 }
 
+func matchEnvironmentName(name string) error {
+	if !models.IsValidEnvironmentName(name) {
+		return ErrorBadName
+	}
+	return nil
+}
+
+// environmentNameIsTaken checks if another environment of the project
+// is already named `name`
+func (repo *Repo) environmentNameIsTaken(projectID uint, name string) error {
+	var count int64
+
+	if err := repo.GetDb().
+		Model(&models.Environment{}).
+		Where("project_id = ? AND name = ?", projectID, name).
+		Count(&count).
+		Error; err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return ErrorNameTaken
+	}
+
+	return nil
+}
+
+// AddProjectEnvironment method creates a user defined environment
+// in a project. The environment name must be unique in the project.
+func (repo *Repo) AddProjectEnvironment(environment *models.Environment) IRepo {
+	if repo.err != nil {
+		return repo
+	}
+
+	if repo.err = matchEnvironmentName(environment.Name); repo.err != nil {
+		return repo
+	}
+
+	if repo.err = repo.environmentNameIsTaken(
+		environment.ProjectID,
+		environment.Name,
+	); repo.err != nil {
+		return repo
+	}
+
+	repo.err = repo.GetDb().
+		Omit("Project").
+		Omit("EnvironmentType").
+		Create(environment).
+		Error
+
+	return repo
+}
+
+// RenameEnvironment method changes the name of an environment.
+// The new name must be unique in the project.
+func (repo *Repo) RenameEnvironment(
+	environment *models.Environment,
+	name string,
+) IRepo {
+	if repo.err != nil {
+		return repo
+	}
+
+	if repo.err = matchEnvironmentName(name); repo.err != nil {
+		return repo
+	}
+
+	if repo.err = repo.environmentNameIsTaken(
+		environment.ProjectID,
+		name,
+	); repo.err != nil {
+		return repo
+	}
+
+	repo.err = repo.GetDb().
+		Model(&models.Environment{}).
+		Where("id = ?", environment.ID).
+		Update("name", name).
+		Error
+
+	if repo.err == nil {
+		environment.Name = name
+	}
+
+	return repo
+}
+
+// DeleteEnvironment method deletes an environment and the messages
+// that were sent for it.
+// Activity logs are kept, without their reference to the environment.
+func (repo *Repo) DeleteEnvironment(environment *models.Environment) IRepo {
+	if repo.err != nil {
+		return repo
+	}
+
+	repo.err = repo.GetDb().Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Delete(models.Message{}, "environment_id = ?", environment.EnvironmentID).
+			Error; err != nil {
+			return err
+		}
+
+		if err := tx.
+			Model(&models.ActivityLog{}).
+			Where("environment_id = ?", environment.ID).
+			Update("environment_id", nil).
+			Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(models.Environment{}, "id = ?", environment.ID).Error
+	})
+
+	return repo
+}
+
 func (repo *Repo) GetEnvironment(environment *models.Environment) IRepo {
 	if repo.err != nil {
 		return repo
@@ -0,0 +1,95 @@
+// +build test
+
+package repo
+
+import (
+	"testing"
+
+	. "github.com/wearedevx/keystone/api/pkg/models"
+	"gorm.io/gorm/clause"
+)
+
+// withForeignKeys runs `fn` with the foreign keys enforced, as they are
+// in production.
+// SQLite enables them per connection, so the pool is reduced to one.
+func withForeignKeys(t *testing.T, fn func()) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sqlDB.SetMaxOpenConns(1)
+	defer sqlDB.SetMaxOpenConns(0)
+
+	if err = db.Exec("PRAGMA foreign_keys = ON").Error; err != nil {
+		t.Fatal(err)
+	}
+	defer db.Exec("PRAGMA foreign_keys = OFF")
+
+	fn()
+}
+
+func TestDeleteEnvironment(t *testing.T) {
+	Repo := NewRepo()
+
+	project := Project{Name: "delete-environment"}
+	if err := db.Omit(clause.Associations).Create(&project).Error; err != nil {
+		t.Fatalf("failed to seed: %v", err)
+	}
+
+	var environmentType EnvironmentType
+	if err := db.First(&environmentType, "name = ?", "dev").Error; err != nil {
+		t.Fatalf("failed to seed: %v", err)
+	}
+
+	environment := Environment{
+		Name:              "preview",
+		EnvironmentTypeID: environmentType.ID,
+		ProjectID:         project.ID,
+	}
+	if err := Repo.AddProjectEnvironment(&environment).Err(); err != nil {
+		t.Fatalf("failed to seed: %v", err)
+	}
+
+	// Creating an environment is logged
+	log := ActivityLog{
+		ProjectID:     &project.ID,
+		EnvironmentID: &environment.ID,
+		Action:        "PostProjectEnvironment",
+		Success:       true,
+	}
+	if err := Repo.SaveActivityLog(&log).Err(); err != nil {
+		t.Fatalf("failed to seed: %v", err)
+	}
+
+	message := Message{EnvironmentID: environment.EnvironmentID}
+	if err := db.Omit(clause.Associations).Create(&message).Error; err != nil {
+		t.Fatalf("failed to seed: %v", err)
+	}
+
+	withForeignKeys(t, func() {
+		if err := Repo.DeleteEnvironment(&environment).Err(); err != nil {
+			t.Fatalf("DeleteEnvironment() error = %v", err)
+		}
+	})
+
+	var count int64
+	db.Model(&Environment{}).Where("id = ?", environment.ID).Count(&count)
+	if count != 0 {
+		t.Errorf("the environment should be deleted")
+	}
+
+	db.Model(&Message{}).Where("id = ?", message.ID).Count(&count)
+	if count != 0 {
+		t.Errorf("the messages of the environment should be deleted")
+	}
+
+	var savedLog ActivityLog
+	if err := db.First(&savedLog, log.ID).Error; err != nil {
+		t.Fatalf("the activity log should be kept: %v", err)
+	}
+
+	if savedLog.EnvironmentID != nil {
+		t.Errorf("the activity log should no longer reference the environment")
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 // IRepo The Repo interface
 type IRepo interface {
+	AddProjectEnvironment(*models.Environment) IRepo
 	CreateEnvironment(*models.Environment) IRepo
 	CreateEnvironmentType(*models.EnvironmentType) IRepo
 	CreateLoginRequest() models.LoginRequest
@@ -21,6 +22,7 @@ type IRepo interface {
 	GetGroupedMessagesWillExpireByUser(groupedMessageUser *map[uint]emailer.GroupedMessagesUser) IRepo
 	DeleteMessage(messageID uint, userID uint) IRepo
 	DeleteProject(project *models.Project) IRepo
+	DeleteEnvironment(*models.Environment) IRepo
 	DeleteProjectsEnvironments(project *models.Project) IRepo
 	Err() error
 	ClearErr() IRepo
@@ -67,6 +69,7 @@ type IRepo interface {
 	ProjectRemoveMembers(models.Project, []string) IRepo
 	ProjectSetRoleForUser(models.Project, models.User, models.Role) IRepo
 	CheckMembersAreInProject(models.Project, []string) ([]string, error)
+	RenameEnvironment(environment *models.Environment, name string) IRepo
 	RemoveOldMessageForRecipient(userID uint, environmentID string) IRepo
 	SaveActivityLog(al *models.ActivityLog) IRepo
 	SetLoginRequestCode(string, string) models.LoginRequest
@@ -78,7 +81,9 @@ type IRepo interface {
 	GetDeviceByUserID(userID uint, device *models.Device) IRepo
 	UpdateDeviceLastUsedAt(deviceUID string) IRepo
 	RevokeDevice(userID uint, deviceUID string) IRepo
+	RotateDeviceKey(device *models.Device, publicKey []byte) IRepo
 	GetAdminsFromUserProjects(userID uint, adminProjectsMap *map[string][]string) IRepo
+	GetMembersFromUserProjects(userID uint, memberProjectsMap *map[string][]string) IRepo
 	CreateOrganization(orga *models.Organization) IRepo
 	UpdateOrganization(orga *models.Organization) IRepo
 	OrganizationSetCustomer(organization *models.Organization, customer string) IRepo
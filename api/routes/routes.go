@@ -43,6 +43,10 @@ func CreateRoutes(w http.ResponseWriter, r *http.Request) {
 		"/projects/:projectID/environments",
 		AuthedHandler(GetAccessibleEnvironments),
 	)
+	router.POST(
+		"/projects/:projectID/environments",
+		AuthedHandler(PostProjectEnvironment),
+	)
 	router.GET(
 		"/projects/:projectID/organization",
 		AuthedHandler(GetProjectsOrganization),
@@ -57,6 +61,8 @@ func CreateRoutes(w http.ResponseWriter, r *http.Request) {
 		"/environments/:envID/public-keys",
 		AuthedHandler(GetEnvironmentPublicKeys),
 	)
+	router.PUT("/environments/:envID", AuthedHandler(PutEnvironment))
+	router.DELETE("/environments/:envID", AuthedHandler(DeleteEnvironment))
 	router.DELETE("/messages-expired", DeleteExpiredMessages)
 	router.GET("/messages-will-expire", AlertMessagesWillExpire)
 	router.POST("/messages", AuthedHandler(WriteMessages))
@@ -65,6 +71,10 @@ func CreateRoutes(w http.ResponseWriter, r *http.Request) {
 
 	router.GET("/devices", AuthedHandler(GetDevices))
 	router.DELETE("/devices/:uid", AuthedHandler(DeleteDevice))
+	router.PUT(
+		"/devices/:uid/public-key",
+		AuthedHandler(RotateDeviceKey),
+	)
 
 	router.POST("/login-request", RegularHandler(PostLoginRequest))
 	router.GET("/login-request", RegularHandler(GetLoginRequest))
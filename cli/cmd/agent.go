@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wearedevx/keystone/cli/internal/agent"
+	"github.com/wearedevx/keystone/cli/internal/config"
+	kserrors "github.com/wearedevx/keystone/cli/internal/errors"
+	"github.com/wearedevx/keystone/cli/internal/watcher"
+	"github.com/wearedevx/keystone/cli/pkg/core"
+)
+
+var (
+	agentSocket     string
+	agentForeground bool
+	agentRefresh    time.Duration
+)
+
+// agentCmd represents the agent command
+var agentCmd = &cobra.Command{
+	Use:   "agent",
+	Short: "Serves secrets from memory to other commands",
+	Long: `Serves secrets from memory to other commands.
+
+The agent keeps the secrets of your projects in memory, and serves them
+over a Unix socket only you can access. While it runs, ` + "`" + `ks source` + "`" + ` and
+` + "`" + `ks run` + "`" + ` get secrets from it instead of reading the cache.
+Secrets are reloaded when the cache changes, for instance after
+` + "`" + `ks secret set` + "`" + ` or when ` + "`" + `ks watch` + "`" + ` applies a message.
+
+The agent starts in the background, and prints the command setting
+` + "`" + `KS_AGENT_SOCK` + "`" + ` to the path of its socket. Other tools can use it to talk
+to the agent. The protocol is made of JSON lines:
+
+  {"op": "list", "project": "/path/to/project", "environment": "dev"}
+  {"op": "get", "project": "/path/to/project", "name": "PORT"}
+  {"op": "subscribe", "project": "/path/to/project"}
+`,
+	Example: `eval "$(ks agent)"
+
+# As a service
+ks agent --foreground --socket /run/user/1000/keystone.sock`,
+	Args: cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		socketPath := agentSocket
+		if socketPath == "" {
+			configDir, err := config.ConfigDir()
+			if err != nil {
+				exit(kserrors.UnkownError(err))
+			}
+
+			socketPath = agent.SocketPath(configDir)
+		}
+
+		if !agentForeground {
+			detachAgent(socketPath)
+			return
+		}
+
+		listener, err := agent.Listen(socketPath)
+		if err != nil {
+			exit(kserrors.CannotStartAgent(socketPath, err))
+		}
+		defer listener.Close()
+
+		server := agent.NewServer(agentSource{})
+
+		stop := make(chan struct{})
+		go server.RefreshEvery(agentRefresh, stop)
+
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			<-signals
+			close(stop)
+			listener.Close()
+		}()
+
+		fmt.Printf("%s=%s; export %s;\n", agent.SocketEnvVar, socketPath, agent.SocketEnvVar)
+
+		_ = server.Serve(listener)
+	},
+}
+
+// detachAgent function starts `ks agent --foreground` in the background,
+// and prints the shell command to find it
+func detachAgent(socketPath string) {
+	// The background process cannot prompt for the passphrase,
+	// it finds the unlocked key in the keyring
+	mustUnlockPrivateKey()
+
+	logFile := filepath.Join(filepath.Dir(socketPath), "agent.log")
+	args := append(os.Args[1:], "--foreground", "--socket", socketPath)
+
+	pid, err := watcher.Detach(args, logFile)
+	if err != nil {
+		exit(kserrors.CannotStartAgent(socketPath, err))
+	}
+
+	fmt.Printf("%s=%s; export %s;\n", agent.SocketEnvVar, socketPath, agent.SocketEnvVar)
+	fmt.Printf("echo Agent pid %d;\n", pid)
+}
+
+// agentSource gives the agent the secrets of the projects, as `ks source`
+// would output them
+type agentSource struct{}
+
+func (agentSource) Secrets(
+	project, environment string,
+) (string, []agent.Secret, error) {
+	projectCtx := core.NewForProject(project)
+	if err := projectCtx.Err(); err != nil {
+		return "", nil, err
+	}
+
+	if environment == "" {
+		environment = projectCtx.CurrentEnvironment()
+	}
+
+	if !projectCtx.HasEnvironment(environment) {
+		return "", nil, fmt.Errorf("no environment named %s", environment)
+	}
+
+	secrets := projectCtx.ListExpandedSecrets(environment)
+	if err := projectCtx.Err(); err != nil {
+		return "", nil, err
+	}
+
+	if missing, ok := projectCtx.MissingSecretsForEnvironment(environment); ok {
+		return "", nil, fmt.Errorf(
+			"required secrets are missing: %s",
+			strings.Join(missing, ", "),
+		)
+	}
+
+	if err := projectCtx.ValidateSecretsForEnvironment(environment).Err(); err != nil {
+		return "", nil, err
+	}
+
+	result := make([]agent.Secret, 0, len(secrets))
+	for _, secret := range secrets {
+		result = append(result, agent.Secret{
+			Name:  secret.Name,
+			Value: string(secret.Values[core.EnvironmentName(environment)]),
+		})
+	}
+
+	return environment, result, nil
+}
+
+// Version changes whenever a file the secrets are read from changes
+func (agentSource) Version(project string) string {
+	files := []string{
+		filepath.Join(project, "keystone.yaml"),
+		filepath.Join(project, ".keystone", "environments.yaml"),
+	}
+
+	dotEnvs, _ := filepath.Glob(
+		filepath.Join(project, ".keystone", "cache", "*", ".env"),
+	)
+	files = append(files, dotEnvs...)
+
+	var sb strings.Builder
+	for _, file := range files {
+		if info, err := os.Stat(file); err == nil {
+			fmt.Fprintf(&sb, "%s:%d:%d;", file, info.ModTime().UnixNano(), info.Size())
+		}
+	}
+
+	return sb.String()
+}
+
+// secretsFromAgent function gets the secrets of the current environment
+// from the agent, when one is running.
+// It returns false when there is no agent: the caller then reads them
+// from the cache. It exits when the agent cannot serve them.
+func secretsFromAgent() ([]agent.Secret, bool) {
+	configDir, err := config.ConfigDir()
+	if err != nil {
+		return nil, false
+	}
+
+	socketPath := agent.SocketPath(configDir)
+
+	client, err := agent.Dial(socketPath)
+	if err != nil {
+		return nil, false
+	}
+	defer client.Close()
+
+	secrets, err := client.List(ctx.Wd, currentEnvironment)
+	if err != nil {
+		exit(kserrors.CannotGetSecretsFromAgent(socketPath, err))
+	}
+
+	return secrets, true
+}
+
+func init() {
+	RootCmd.AddCommand(agentCmd)
+
+	agentCmd.Flags().StringVar(
+		&agentSocket,
+		"socket",
+		"",
+		"path of the socket (default is $KS_AGENT_SOCK, or agent.sock in the config directory)",
+	)
+	agentCmd.Flags().BoolVar(
+		&agentForeground,
+		"foreground",
+		false,
+		"do not start in the background, for service managers",
+	)
+	agentCmd.Flags().DurationVar(
+		&agentRefresh,
+		"refresh",
+		time.Second,
+		"how often to check for changes in the cache",
+	)
+}
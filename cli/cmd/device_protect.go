@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wearedevx/keystone/cli/internal/config"
+	kserrors "github.com/wearedevx/keystone/cli/internal/errors"
+	"github.com/wearedevx/keystone/cli/ui/display"
+	"github.com/wearedevx/keystone/cli/ui/prompts"
+)
+
+var keyCacheDuration time.Duration
+
+// deviceProtectCmd represents the device protect command
+var deviceProtectCmd = &cobra.Command{
+	Use:   "protect",
+	Short: "Protects the private key of this device with a passphrase",
+	Long: `Protects the private key of this device with a passphrase.
+
+The private key is stored in the configuration directory of keystone.
+Anyone who copies it can impersonate this device.
+Once protected, it is sealed with your passphrase, and the passphrase is
+asked for whenever the key is needed.
+
+The unlocked key is then kept for a short time in a keyring of your
+session, so that you do not have to type the passphrase for every command.
+Use --cache-for to change how long (0 to disable the keyring).
+
+In scripts, set the passphrase in the KS_KEY_PASSPHRASE environment
+variable.`,
+	Example: `ks device protect
+ks device protect --cache-for 1h
+KS_KEY_PASSPHRASE=… ks device protect`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		if cmd.Flags().Changed("cache-for") {
+			config.SetKeyCacheDuration(keyCacheDuration)
+		}
+
+		if config.IsPrivateKeyProtected() {
+			config.Write()
+			display.PrivateKeyAlreadyProtected(config.GetKeyCacheDuration())
+			exit(nil)
+		}
+
+		protectPrivateKey()
+	},
+}
+
+// protectPrivateKey seals the private key of the device with a passphrase
+// taken from the environment, or asked to the user
+func protectPrivateKey() {
+	passphrase, err := newKeyPassphrase()
+	if err != nil {
+		exit(kserrors.CannotProtectPrivateKey(err))
+	}
+
+	if err = config.ProtectUserPrivateKey(passphrase); err != nil {
+		exit(kserrors.CannotProtectPrivateKey(err))
+	}
+
+	config.Write()
+
+	display.PrivateKeyProtected(config.GetKeyCacheDuration())
+}
+
+// newKeyPassphrase returns the passphrase to protect the private key
+// with, taken from the environment, or asked to the user
+func newKeyPassphrase() (string, error) {
+	if passphrase := os.Getenv(config.KeyPassphraseEnvVar); passphrase != "" {
+		return passphrase, nil
+	}
+
+	if skipPrompts {
+		return "", config.ErrorPassphraseRequired
+	}
+
+	return prompts.NewKeyPassphrase(), nil
+}
+
+func init() {
+	deviceCmd.AddCommand(deviceProtectCmd)
+
+	deviceProtectCmd.Flags().DurationVar(
+		&keyCacheDuration,
+		"cache-for",
+		config.DefaultKeyCacheDuration,
+		"how long the unlocked key is kept",
+	)
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/cossacklabs/themis/gothemis/keys"
+	"github.com/spf13/cobra"
+	"github.com/wearedevx/keystone/cli/internal/config"
+	kserrors "github.com/wearedevx/keystone/cli/internal/errors"
+	"github.com/wearedevx/keystone/cli/pkg/client"
+	"github.com/wearedevx/keystone/cli/pkg/core"
+	"github.com/wearedevx/keystone/cli/ui/display"
+)
+
+var keyGracePeriod time.Duration
+
+// deviceRotateKeyCmd represents the device rotate-key command
+var deviceRotateKeyCmd = &cobra.Command{
+	Use:   "rotate-key",
+	Short: "Replaces the keys of this device",
+	Long: `Replaces the keys of this device.
+
+A new pair of keys is generated, and the public one is sent to Keystone.
+Members of your projects are notified by email, so that they can send
+you the environments again, encrypted with the new key.
+
+The new keys are saved before the public one is sent. If Keystone
+cannot be reached, the previous keys are restored.
+
+The previous private key is kept for a grace period (see --grace-period),
+so that messages sent before the rotation can still be read.
+The local cache of your projects is encrypted again with the new key,
+or the next time you use them, if it is within the grace period.
+
+If the private key is protected by a passphrase, the new one is protected
+too. In scripts, set the passphrase in the KS_KEY_PASSPHRASE environment
+variable.`,
+	Example: `ks device rotate-key
+ks device rotate-key --grace-period 72h`,
+	Args: cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		c, kcErr := client.NewKeystoneClient()
+		exitIfErr(kcErr)
+
+		wasProtected := config.IsPrivateKeyProtected()
+		mustUnlockPrivateKey()
+
+		passphrase := ""
+		if wasProtected {
+			var err error
+			if passphrase, err = newKeyPassphrase(); err != nil {
+				exit(kserrors.CannotRotateKey(err))
+			}
+		}
+
+		keyPair, err := keys.New(keys.TypeEC)
+		if err != nil {
+			exit(kserrors.CannotRotateKey(err))
+		}
+
+		// The new keys are saved before the server gets the public one,
+		// so that the private one cannot be lost.
+		// The previous key is kept, and restored if the rotation fails.
+		// The new one is then kept as a previous key: the server may
+		// have stored its public key before the error.
+		savedKeys := config.SaveUserKeys()
+
+		oldPrivateKey, err := config.RotateUserKeys(
+			keyPair.Private.Value,
+			keyPair.Public.Value,
+			keyGracePeriod,
+		)
+		if err != nil {
+			exit(kserrors.CannotRotateKey(err))
+		}
+
+		if wasProtected {
+			if err = config.ProtectUserPrivateKey(passphrase); err != nil {
+				exit(kserrors.CannotRotateKey(err))
+			}
+		}
+
+		config.Write()
+
+		if _, err = c.Devices().RotateKey(
+			config.GetDeviceUID(),
+			keyPair.Public.Value,
+		); err != nil {
+			if restoreErr := config.RestoreUserKeys(
+				savedKeys,
+				oldPrivateKey,
+				keyPair.Private.Value,
+				keyGracePeriod,
+			); restoreErr != nil {
+				exit(kserrors.CannotRotateKey(restoreErr))
+			}
+			config.Write()
+
+			handleClientError(err)
+			exit(kserrors.CannotRotateKey(err))
+		}
+
+		rekeyProjectCaches(keyPair.Private.Value, oldPrivateKey)
+
+		display.DeviceKeyRotated(keyGracePeriod)
+	},
+}
+
+// rekeyProjectCaches function encrypts the cache of every known project
+// with the new private key.
+// Other projects are encrypted again the next time they are used.
+// Failures are reported, but do not stop the other projects.
+func rekeyProjectCaches(newPrivateKey, oldPrivateKey []byte) {
+	for _, dir := range config.GetProjects() {
+		projectCtx := core.NewForProject(dir)
+		if projectCtx.Err() != nil {
+			// The project was moved or removed
+			continue
+		}
+
+		if err := projectCtx.
+			RekeyCache(newPrivateKey, [][]byte{oldPrivateKey}).
+			Err(); err != nil {
+			err.Print()
+		}
+	}
+}
+
+func init() {
+	deviceCmd.AddCommand(deviceRotateKeyCmd)
+
+	deviceRotateKeyCmd.Flags().DurationVar(
+		&keyGracePeriod,
+		"grace-period",
+		config.DefaultKeyGracePeriod,
+		"how long the previous key can still read messages",
+	)
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/wearedevx/keystone/cli/ui/display"
+)
+
+// docCmd represents the doc command
+var docCmd = &cobra.Command{
+	Use:   "doc",
+	Short: "Generates an inventory of the project’s secrets",
+	Long: `Generates an inventory of the project’s secrets.
+
+Prints a markdown document listing every secret declared in keystone.yaml,
+with its description, owner and documentation link.
+Values are never included, so the document can be committed alongside
+your project’s README.
+
+Use ` + "`" + `ks secret describe` + "`" + ` to document secrets.
+`,
+	Example: `ks doc > SECRETS.md`,
+	Args:    cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		projectName := ctx.GetProjectName()
+		secrets := ctx.ListSecrets()
+		exitIfErr(ctx.Err())
+
+		display.SecretsInventory(projectName, secrets)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(docCmd)
+}
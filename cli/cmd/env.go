@@ -38,6 +38,9 @@ With an argument name, activates the environment:
 ` + "```" + `
 $ ks env staging
 ` + "```" + `
+
+Environments other than dev, staging and prod can be managed with
+` + "`" + `ks env add` + "`" + `, ` + "`" + `ks env rename` + "`" + ` and ` + "`" + `ks env rm` + "`" + `.
 `,
 	Args: cobra.NoArgs,
 	Run: func(_ *cobra.Command, _ []string) {
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/wearedevx/keystone/cli/internal/environments"
+	kserrors "github.com/wearedevx/keystone/cli/internal/errors"
+	"github.com/wearedevx/keystone/cli/pkg/constants"
+	"github.com/wearedevx/keystone/cli/pkg/core"
+	"github.com/wearedevx/keystone/cli/ui/display"
+)
+
+var envAddType string
+
+// envAddCmd represents the env add command
+var envAddCmd = &cobra.Command{
+	Use:   "add <environment>",
+	Short: "Creates a new environment",
+	Long: `Creates a new environment in the project.
+
+Besides dev, staging and prod, projects can have as many environments
+as needed (e.g. qa, preview, demo).
+Names may only contain lowercase letters, digits, dashes and underscores.
+
+Every environment has a type: dev, staging or prod.
+Members have the same access to the new environment as to the environment
+of that type. The default type is dev.
+
+Only project administrators can create environments.
+The new environment has no values: set them with ` + "`" + `ks secret set` + "`" + `
+and ` + "`" + `ks file set` + "`" + `.
+`,
+	Example: `ks env add qa --type staging`,
+	Args:    cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		environmentName := args[0]
+
+		if !core.IsValidEnvironmentName(environmentName) {
+			exit(kserrors.BadEnvironmentName(environmentName, nil))
+		}
+
+		if !core.Contains(
+			[]string{
+				string(constants.DEV),
+				string(constants.STAGING),
+				string(constants.PROD),
+			},
+			envAddType,
+		) {
+			exit(kserrors.BadEnvironmentType(envAddType, nil))
+		}
+
+		es := environments.NewEnvironmentService(ctx)
+		environment := es.AddEnvironment(environmentName, envAddType)
+		exitIfErr(es.Err())
+
+		exitIfErr(ctx.AddLocalEnvironment(environment).Err())
+
+		display.EnvironmentAdded(environment)
+	},
+}
+
+func init() {
+	envCmd.AddCommand(envAddCmd)
+
+	envAddCmd.Flags().StringVarP(
+		&envAddType,
+		"type",
+		"t",
+		string(constants.DEV),
+		"type of the environment, decides who has access to it (dev, staging, prod)",
+	)
+}
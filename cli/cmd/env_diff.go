@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wearedevx/keystone/cli/internal/config"
+	"github.com/wearedevx/keystone/cli/internal/messages"
+	"github.com/wearedevx/keystone/cli/ui"
+	"github.com/wearedevx/keystone/cli/ui/display"
+)
+
+var diffShowValues bool
+
+// envDiffCmd represents the diff command
+var envDiffCmd = &cobra.Command{
+	Use:   "diff <environment> <other environment>",
+	Short: "Compares the secrets and files of two environments",
+	Long: `Compares the secrets and files of two environments.
+
+Lists the secrets that are missing, empty, or that have a different value
+in one of the environments, and the files whose content differ.
+
+Secret values are masked, unless ` + "`" + `--show-values` + "`" + ` is used.
+With ` + "`" + `--quiet` + "`" + `, the result is printed as JSON.
+
+The command exits with a non-zero status code when the environments differ,
+so it can be used in a CI pipeline.
+`,
+	Example: `ks env diff staging prod
+
+# In a CI pipeline:
+ks env diff staging prod --quiet > drift.json
+`,
+	Args: cobra.ExactArgs(2),
+	Run: func(_ *cobra.Command, args []string) {
+		environmentA := args[0]
+		environmentB := args[1]
+
+		ctx.MustHaveEnvironment(environmentA)
+		ctx.MustHaveEnvironment(environmentB)
+
+		if quietOutput {
+			// The changes would mix with the JSON on stdout,
+			// fetch without displaying them
+			ms := messages.NewMessageService(ctx)
+			ms.GetMessages()
+
+			if err := ms.Err(); err != nil {
+				config.CheckExpiredTokenError(err)
+				ui.PrintStdErr(
+					"WARNING: Could not get messages (%s)",
+					err.Name(),
+				)
+				ui.PrintStdErr(err.Help())
+			}
+		} else {
+			shouldFetchMessages()
+		}
+
+		diff := ctx.DiffEnvironments(environmentA, environmentB)
+		exitIfErr(ctx.Err())
+
+		if quietOutput {
+			display.EnvironmentsDiffJSON(diff, diffShowValues)
+		} else {
+			display.EnvironmentsDiff(diff, diffShowValues)
+		}
+
+		if diff.HasDrift() {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	envCmd.AddCommand(envDiffCmd)
+
+	envDiffCmd.Flags().
+		BoolVar(&diffShowValues, "show-values", false, "display secret values")
+}
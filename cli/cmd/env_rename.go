@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/wearedevx/keystone/cli/internal/environments"
+	kserrors "github.com/wearedevx/keystone/cli/internal/errors"
+	"github.com/wearedevx/keystone/cli/pkg/core"
+	"github.com/wearedevx/keystone/cli/ui/display"
+)
+
+// envRenameCmd represents the env rename command
+var envRenameCmd = &cobra.Command{
+	Use:   "rename <environment> <new name>",
+	Short: "Renames an environment",
+	Long: `Renames an environment created with ` + "`" + `ks env add` + "`" + `.
+
+The dev, staging and prod environments cannot be renamed.
+Only project administrators can rename environments.
+Other members will see the new name the next time they use keystone.
+`,
+	Example: `ks env rename qa preprod`,
+	Args:    cobra.ExactArgs(2),
+	Run: func(_ *cobra.Command, args []string) {
+		environmentName := args[0]
+		newName := args[1]
+
+		if !core.IsValidEnvironmentName(newName) {
+			exit(kserrors.BadEnvironmentName(newName, nil))
+		}
+
+		ctx.MustHaveEnvironment(environmentName)
+
+		es := environments.NewEnvironmentService(ctx)
+		es.RenameEnvironment(environmentName, newName)
+		exitIfErr(es.Err())
+
+		exitIfErr(ctx.RenameLocalEnvironment(environmentName, newName).Err())
+
+		display.EnvironmentRenamed(environmentName, newName)
+	},
+}
+
+func init() {
+	envCmd.AddCommand(envRenameCmd)
+}
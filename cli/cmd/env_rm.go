@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/wearedevx/keystone/cli/internal/environments"
+	"github.com/wearedevx/keystone/cli/ui/display"
+	"github.com/wearedevx/keystone/cli/ui/prompts"
+)
+
+// envRmCmd represents the env rm command
+var envRmCmd = &cobra.Command{
+	Use:   "rm <environment>",
+	Short: "Removes an environment",
+	Long: `Removes an environment created with ` + "`" + `ks env add` + "`" + `.
+
+Its secrets and files are lost for all members.
+The dev, staging and prod environments cannot be removed.
+Only project administrators can remove environments.
+
+If it is the current environment, dev becomes the current environment.
+It cannot be removed while other environments inherit from it.
+`,
+	Example: `ks env rm qa`,
+	Args:    cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		environmentName := args[0]
+
+		ctx.MustHaveEnvironment(environmentName)
+		exitIfErr(ctx.CheckEnvironmentRemovable(environmentName).Err())
+
+		if !prompts.ConfirmEnvironmentRemoval(environmentName, skipPrompts) {
+			return
+		}
+
+		es := environments.NewEnvironmentService(ctx)
+		es.RemoveEnvironment(environmentName)
+		exitIfErr(es.Err())
+
+		exitIfErr(ctx.RemoveLocalEnvironment(environmentName).Err())
+
+		display.EnvironmentRemoved(environmentName)
+	},
+}
+
+func init() {
+	envCmd.AddCommand(envRmCmd)
+}
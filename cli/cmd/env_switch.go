@@ -41,7 +41,11 @@ var switchCmd = &cobra.Command{
 Next time ` + "`" + `ks source` + "+" + ` is executed, it will use values
 from <environment>.
 
-Valid values for environment are: "dev", "staging", and "prod"`,
+Valid values for environment are "dev", "staging", "prod",
+and the environments created with ` + "`" + `ks env add` + "`" + `.
+
+Templates listed in the ` + "`" + `renders` + "`" + ` section of keystone.yaml are rendered
+again with the values of <environment> (see ` + "`" + `ks render` + "`" + `).`,
 	Example: `ks env switch prod`,
 	Args:    cobra.ExactArgs(1),
 	Run: func(_ *cobra.Command, args []string) {
@@ -66,6 +70,15 @@ Valid values for environment are: "dev", "staging", and "prod"`,
 			Err())
 
 		display.EnvironmentUsing(environmentName)
+
+		renders := ctx.ListRenders()
+		exitIfErr(ctx.Err())
+
+		if len(renders) > 0 {
+			exitIfErr(ctx.RenderAll(environmentName).Err())
+
+			display.TemplatesRendered(len(renders), environmentName)
+		}
 	},
 }
 
@@ -59,6 +59,11 @@ $ ks file -qf available
 			fileDisplayFilter,
 			quietOutput,
 		)
+
+		worldReadable := ctx.WorldReadableFiles(currentEnvironment)
+		if !quietOutput && len(worldReadable) > 0 {
+			display.WorldReadableFiles(worldReadable)
+		}
 	},
 }
 
@@ -32,9 +32,14 @@ import (
 	"github.com/wearedevx/keystone/cli/ui/display"
 )
 
+var (
+	fileAddInclude []string
+	fileAddExclude []string
+)
+
 // filesAddCmd represents the push command
 var filesAddCmd = &cobra.Command{
-	Use:   "add <path to a file>",
+	Use:   "add <path to a file or a directory>",
 	Short: "Adds a file to secrets",
 	Long: `Adds a file to secrets.
 
@@ -44,13 +49,22 @@ certificates and so on.
 
 When adding a file, you will be asked for a version of its content
 for all known environments – the current content will be used as default.
+The permissions of the file are kept, so that private keys stay private.
+
+When adding a directory, every file it contains is added, without prompts.
+Use --include and --exclude to only add the files matching glob patterns.
+Files removed from the directory are removed for every member
+on the next ks file set.
 `,
 	Example: `ks file add ./config/config.exs
 ks file add ./wp-config.php
 ks file add ./certs/my-website.cert
 
 # Skip the prompts
-ks file add -s ./credentials.json`,
+ks file add -s ./credentials.json
+
+# Add the certificates of a directory, except the old ones
+ks file add ./certs --include '*.pem' --exclude old`,
 	Args: cobra.ExactArgs(1),
 	Run: func(_ *cobra.Command, args []string) {
 		var err error
@@ -61,6 +75,11 @@ ks file add -s ./credentials.json`,
 		filePath, err := cleanPathArgument(args[0], ctx.Wd)
 		exitIfErr(err)
 
+		if utils.DirExists(filepath.Join(ctx.Wd, filePath)) {
+			addDirectory(filePath)
+			return
+		}
+
 		fileservice := files.NewFileService(ctx)
 
 		environments := ctx.AccessibleEnvironments
@@ -150,7 +169,11 @@ ks file add -s ./credentials.json`,
 				destination = path.Join(ctx.Wd, filePath)
 
 				exitIfErr(
-					utils.CopyFile(cachedFilePath, destination),
+					ctx.CopyFromCacheWithMode(
+						cachedFilePath,
+						destination,
+						ctx.FileMode(filePath, currentEnvironment),
+					),
 				)
 			}
 
@@ -160,6 +183,64 @@ ks file add -s ./credentials.json`,
 	},
 }
 
+// addDirectory adds a directory entry, and the files it contains for
+// every environment
+func addDirectory(directoryPath string) {
+	if directoryPath == "" {
+		exit(kserrors.CannotAddFile(
+			directoryPath,
+			errors.New("cannot add the whole project"),
+		))
+	}
+
+	for _, patterns := range [][]string{fileAddInclude, fileAddExclude} {
+		if err := keystonefile.ValidatePatterns(patterns); err != nil {
+			exit(kserrors.CannotAddFile(directoryPath, err))
+		}
+	}
+
+	file := keystonefile.FileKey{
+		Path:      directoryPath,
+		Strict:    addOptional,
+		Directory: true,
+		Include:   fileAddInclude,
+		Exclude:   fileAddExclude,
+	}
+
+	changes, messageService := mustFetchMessages()
+
+	exitIfErr(
+		ctx.CompareNewFileWhithChanges(directoryPath, changes).
+			AddFile(file, nil).
+			Err(),
+	)
+
+	exitIfErr(gitignorehelper.GitIgnore(ctx.Wd, directoryPath))
+
+	exitIfErr(
+		messageService.SendEnvironments(ctx.AccessibleEnvironments).Err(),
+	)
+
+	display.DirectoryAddSuccess(
+		directoryPath,
+		len(ctx.ExpandFileKey(file, currentEnvironment)),
+		len(ctx.AccessibleEnvironments),
+	)
+}
+
 func init() {
 	filesCmd.AddCommand(filesAddCmd)
+
+	filesAddCmd.Flags().StringSliceVar(
+		&fileAddInclude,
+		"include",
+		nil,
+		"only add the files of the directory matching these glob patterns",
+	)
+	filesAddCmd.Flags().StringSliceVar(
+		&fileAddExclude,
+		"exclude",
+		nil,
+		"do not add the files of the directory matching these glob patterns",
+	)
 }
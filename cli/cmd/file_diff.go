@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"errors"
+	"path"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	kserrors "github.com/wearedevx/keystone/cli/internal/errors"
+	"github.com/wearedevx/keystone/cli/internal/utils"
+	"github.com/wearedevx/keystone/cli/pkg/core"
+	"github.com/wearedevx/keystone/cli/ui/display"
+	"github.com/wearedevx/keystone/cli/ui/prompts"
+)
+
+// fileDiffCmd represents the file diff command
+var fileDiffCmd = &cobra.Command{
+	Use:   "diff [path to a file or a directory]",
+	Short: "Shows the local changes to files",
+	Long: `Shows the local changes to files.
+
+Prints a unified diff between the content of the files for the current
+environment, and their local version, for every locally modified file,
+or only the given one.
+Binary files, and files with thousands of changed lines, are only
+reported as different.
+
+You are then asked to set the local versions as the new content for the
+current environment, as ` + "`" + `ks file set` + "`" + ` does.
+To discard the local changes instead, use ` + "`" + `ks file reset` + "`" + `.
+`,
+	Example: `ks file diff
+
+ks file diff ./config/config.exs
+
+# Compare with the content for the staging environment
+ks --env staging file diff ./config/config.exs
+
+# Only print the changes
+ks file diff -s
+`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		ctx.MustHaveEnvironment(currentEnvironment)
+		shouldFetchMessages()
+
+		filePath := ""
+		if len(args) == 1 {
+			var err error
+
+			filePath, err = cleanPathArgument(args[0], ctx.Wd)
+			exitIfErr(err)
+
+			if !ctx.TracksFile(filePath) {
+				exit(kserrors.CannotDiffFile(
+					filePath,
+					errors.New("file not added to project"),
+				))
+			}
+		}
+
+		diffs := make([]core.LocalFileDiff, 0)
+		modified := make([]string, 0)
+
+		for _, file := range ctx.ListExpandedFiles(currentEnvironment) {
+			if filePath != "" &&
+				file.Path != filePath &&
+				!strings.HasPrefix(file.Path, filePath+"/") {
+				continue
+			}
+
+			diff := ctx.DiffLocalFile(file.Path, currentEnvironment)
+			if !diff.Modified {
+				continue
+			}
+
+			diffs = append(diffs, diff)
+
+			// Files removed locally cannot be set
+			if utils.FileExists(path.Join(ctx.Wd, file.Path)) {
+				modified = append(modified, file.Path)
+			}
+		}
+		exitIfErr(ctx.Err())
+
+		if len(diffs) == 0 {
+			display.NoLocalFileChanges(currentEnvironment)
+			return
+		}
+
+		display.LocalFileDiffs(diffs)
+
+		// Files are only set for the environment in use
+		if !skipPrompts &&
+			len(modified) > 0 &&
+			currentEnvironment == ctx.CurrentEnvironment() &&
+			prompts.ConfirmFileChangesPublication(currentEnvironment) {
+			ctx.MustHaveAccessToEnvironment(currentEnvironment)
+			setFiles(modified)
+		}
+	},
+}
+
+func init() {
+	filesCmd.AddCommand(fileDiffCmd)
+}
@@ -29,8 +29,13 @@ var fileOptionalCmd = &cobra.Command{
 
 When a file is marked as optional, its absence or emptiness won’t cause
 ` + "`" + `ks source` + "`" + ` or ` + "`" + `ks ci send` + "`" + ` to fail.
+
+With ` + "`" + `--env` + "`" + `, the file is only made optional in that environment.
 `,
-	Example: `ks file optional ./config.json`,
+	Example: `ks file optional ./config.json
+
+# Optional in dev, required elsewhere
+ks file optional ./certs/prod.pem --env dev`,
 	Run: func(_ *cobra.Command, args []string) {
 		fileName := args[0]
 
@@ -39,6 +44,16 @@ When a file is marked as optional, its absence or emptiness won’t cause
 			return
 		}
 
+		if environmentFlagIsSet() {
+			ctx.MustHaveEnvironment(currentEnvironment)
+			exitIfErr(
+				ctx.MarkFileRequiredIn(fileName, currentEnvironment, false).Err(),
+			)
+
+			display.FileIsNowIn(fileName, display.OPTIONAL, currentEnvironment)
+			return
+		}
+
 		exitIfErr(
 			ctx.MarkFileRequired(fileName, false).Err(),
 		)
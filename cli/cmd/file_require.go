@@ -31,9 +31,14 @@ Files marked as required must exist and have content.
 If they don’t, ` + "`" + `ks source` + "`" + ` will exit with a non-zero exit code.
 
 Additionally, ` + "`" + `ks ci send` + "`" + ` will fail if a required file is empty or missing.
+
+With ` + "`" + `--env` + "`" + `, the file is only required in that environment.
 `,
-	Example: "ks file require ./config.json",
-	Args:    cobra.ExactArgs(1),
+	Example: `ks file require ./config.json
+
+# Only required in prod
+ks file require ./certs/prod.pem --env prod`,
+	Args: cobra.ExactArgs(1),
 	Run: func(_ *cobra.Command, args []string) {
 		fileName := args[0]
 
@@ -41,6 +46,16 @@ Additionally, ` + "`" + `ks ci send` + "`" + ` will fail if a required file is e
 			exit(kserrors.FileDoesNotExist(fileName, nil))
 		}
 
+		if environmentFlagIsSet() {
+			ctx.MustHaveEnvironment(currentEnvironment)
+			exitIfErr(
+				ctx.MarkFileRequiredIn(fileName, currentEnvironment, true).Err(),
+			)
+
+			display.FileIsNowIn(fileName, display.REQUIRED, currentEnvironment)
+			return
+		}
+
 		exitIfErr(
 			ctx.MarkFileRequired(fileName, true).Err(),
 		)
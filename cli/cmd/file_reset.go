@@ -18,7 +18,6 @@ package cmd
 import (
 	"path"
 
-	"github.com/wearedevx/keystone/cli/internal/utils"
 	"github.com/wearedevx/keystone/cli/ui/display"
 	"github.com/wearedevx/keystone/cli/ui/prompts"
 
@@ -48,14 +47,14 @@ ks file reset
 
 		filesToReset := args
 		if len(filesToReset) == 0 {
-			for _, file := range ctx.ListFiles() {
+			for _, file := range ctx.ListExpandedFiles(currentEnvironment) {
 				filesToReset = append(filesToReset, file.Path)
 			}
 		}
 
 		if prompts.ConfirmFileReset(fileResetYes) {
 			for _, file := range filesToReset {
-				if !ctx.HasFile(file) {
+				if !ctx.TracksFile(file) {
 					display.FileNotManaged(file)
 					continue
 				}
@@ -66,7 +65,11 @@ ks file reset
 				)
 				filePath := path.Join(ctx.Wd, file)
 
-				err := utils.CopyFile(cachedFilePath, filePath)
+				err := ctx.CopyFromCacheWithMode(
+					cachedFilePath,
+					filePath,
+					ctx.FileMode(file, currentEnvironment),
+				)
 				exitIfErr(err)
 			}
 		}
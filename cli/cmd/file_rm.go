@@ -32,7 +32,7 @@ var (
 
 // filesRmCmd represents the rm command
 var filesRmCmd = &cobra.Command{
-	Use:   "rm [path to a file]",
+	Use:   "rm [path to a file or a directory]",
 	Short: "Removes a file from secrets",
 	Long: `Removes a file from secrets.
 
@@ -47,7 +47,7 @@ Files can be used again using "file add" command.
 	Run: func(_ *cobra.Command, args []string) {
 		filePath := args[0]
 
-		if !utils.FileExists(filePath) {
+		if !utils.FileExists(filePath) && !utils.DirExists(filePath) {
 			exit(errors.
 				CannotRemoveFile(filePath, fmt.Errorf("file not found")))
 		}
@@ -28,12 +28,15 @@ import (
 
 // setCmd represents the set command
 var setCmd = &cobra.Command{
-	Use:   "set <path to a file>",
+	Use:   "set <path to a file or a directory>",
 	Short: "Updates a file’s content for the current environment",
 	Long: `Updates a file’s content for the current environment.
 
 Changes the content of a file without altering other environments.
 The local version of the file will be used.
+
+For a directory, the local versions of all its files are used,
+and the files that have been removed locally are removed for everyone.
 `,
 	Example: `ks file set ./config.php
 
@@ -50,12 +53,17 @@ ks --env staging file set ./config.php
 		filePath, err := cleanPathArgument(args[0], ctx.Wd)
 		exitIfErr(err)
 
+		if utils.DirExists(path.Join(ctx.Wd, filePath)) {
+			setDirectory(filePath)
+			return
+		}
+
 		if !utils.FileExists(path.Join(ctx.Wd, filePath)) {
 			exit(kserrors.
 				CannotSetFile(filePath, errors.New("file not found")))
 		}
 
-		if !ctx.HasFile(filePath) {
+		if !ctx.TracksFile(filePath) {
 			exit(kserrors.
 				CannotSetFile(
 					filePath,
@@ -63,32 +71,61 @@ ks --env staging file set ./config.php
 				))
 		}
 
+		setFiles([]string{filePath})
+	},
+}
+
+// setFiles shares the local versions of the files at `filePaths`
+// for the current environment
+func setFiles(filePaths []string) {
+	changes, messageService := mustFetchMessages()
+
+	for _, filePath := range filePaths {
 		content, err := ctx.GetLocalFileContents(filePath)
 		if err != nil {
-			if err.Error() != "No contents" {
-				exit(kserrors.CannotSetFile(filePath, err))
-			}
+			exit(kserrors.CannotSetFile(filePath, err))
 		}
 
-		changes, messageService := mustFetchMessages()
-
-		err = ctx.
-			CompareNewFileWhithChanges(filePath, changes).
-			SetFile(filePath, content).
-			// Local files should be kept during a file set
-			FilesUseEnvironment(
-				currentEnvironment,
-				currentEnvironment,
-				core.CTX_KEEP_LOCAL_FILES,
-			).
-			Err()
-		exitIfErr(err)
+		exitIfErr(
+			ctx.
+				CompareNewFileWhithChanges(filePath, changes).
+				SetFile(filePath, content).
+				Err(),
+		)
+	}
+
+	// Local files should be kept during a file set
+	exitIfErr(
+		ctx.FilesUseEnvironment(
+			currentEnvironment,
+			currentEnvironment,
+			core.CTX_KEEP_LOCAL_FILES,
+		).Err(),
+	)
+
+	err := messageService.SendEnvironments(ctx.AccessibleEnvironments).Err()
+	exitIfErr(err)
+
+	for _, filePath := range filePaths {
+		display.FileSetSuccess(filePath)
+	}
+}
 
-		err = messageService.SendEnvironments(ctx.AccessibleEnvironments).Err()
-		exitIfErr(err)
+// setDirectory shares the local files of a directory entry
+func setDirectory(directoryPath string) {
+	changes, messageService := mustFetchMessages()
 
-		display.FileSetSuccess(filePath)
-	},
+	exitIfErr(
+		ctx.CompareNewFileWhithChanges(directoryPath, changes).
+			SetDirectory(directoryPath).
+			Err(),
+	)
+
+	exitIfErr(
+		messageService.SendEnvironments(ctx.AccessibleEnvironments).Err(),
+	)
+
+	display.FileSetSuccess(directoryPath)
 }
 
 func init() {
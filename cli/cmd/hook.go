@@ -35,7 +35,10 @@ It receives the project UUID and the path to the .keystone folder as parameters.
 
 The hook is global, meaning that once you've set it up, it will run for every
 project.
-It is also unique (there can only be one hook).`,
+It is also unique (there can only be one hook).
+
+Git hooks that use Keystone can be installed in the repository of the
+project with ` + "`" + `ks hook install` + "`" + `.`,
 	Example: "ks hook",
 	Run: func(cmd *cobra.Command, args []string) {
 		if hook, ok := ctx.GetHook(); ok {
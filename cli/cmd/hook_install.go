@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/wearedevx/keystone/cli/internal/utils"
+	"github.com/wearedevx/keystone/cli/pkg/core"
+	"github.com/wearedevx/keystone/cli/ui/display"
+)
+
+// hookInstallCmd represents the hook install command
+var hookInstallCmd = &cobra.Command{
+	Use:   "install <hook>",
+	Short: "Installs a git hook",
+	Long: `Installs a git hook in the repository of the project.
+
+The git-pre-commit hook runs ` + "`" + `ks scan --staged` + "`" + ` before each commit,
+and stops it when the staged files contain secret values.
+An existing hook that was not installed by Keystone is renamed to
+pre-commit.local, and runs before the scan.`,
+	Example:   "ks hook install git-pre-commit",
+	ValidArgs: []string{"git-pre-commit"},
+	Args:      cobra.ExactValidArgs(1),
+	Run: func(_ *cobra.Command, _ []string) {
+		hookPath := ctx.GitPreCommitHookPath()
+		exitIfErr(ctx.Err())
+
+		if utils.FileExists(hookPath) && !core.IsKeystoneGitHook(hookPath) {
+			localHookPath := ctx.KeepLocalGitHook(hookPath)
+			exitIfErr(ctx.Err())
+
+			display.GitHookKept(hookPath, localHookPath)
+		}
+
+		ctx.InstallGitPreCommitHook(hookPath)
+		exitIfErr(ctx.Err())
+
+		display.GitHookInstalled(hookPath)
+	},
+}
+
+func init() {
+	hookCmd.AddCommand(hookInstallCmd)
+}
@@ -23,7 +23,10 @@ import (
 	"github.com/wearedevx/keystone/cli/ui/display"
 )
 
-var serviceName string
+var (
+	serviceName string
+	protectKey  bool
+)
 
 // loginCmd represents the login command
 var loginCmd = &cobra.Command{
@@ -34,10 +37,14 @@ var loginCmd = &cobra.Command{
 When singing up, you will be asked to log into either your GitHub or Gitlab
 account, to verify your identity.
 We do not use any information other than your email address and your username.
+
+With --protect-key, the private key of this device is sealed with a
+passphrase (see ks device protect).
 	`,
 	Example: `ks login
 ks login --with=gitlab
-ks login ––with=github`,
+ks login ––with=github
+ks login --protect-key`,
 	Args: cobra.NoArgs,
 	Run: func(_ *cobra.Command, _ []string) {
 		currentAccount, accountIndex := config.GetCurrentAccount()
@@ -79,6 +86,10 @@ ks login ––with=github`,
 		} else {
 			display.LoginSucces()
 		}
+
+		if protectKey && !config.IsPrivateKeyProtected() {
+			protectPrivateKey()
+		}
 	},
 }
 
@@ -97,4 +108,7 @@ func init() {
 
 	loginCmd.Flags().
 		StringVar(&serviceName, "with", "", "identity provider. Either github or gitlab")
+
+	loginCmd.Flags().
+		BoolVar(&protectKey, "protect-key", false, "protect the private key of this device with a passphrase")
 }
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/wearedevx/keystone/cli/internal/config"
+	"github.com/wearedevx/keystone/cli/ui/display"
+)
+
+var renderOutput string
+
+// renderCmd represents the render command
+var renderCmd = &cobra.Command{
+	Use:   "render [template]",
+	Short: "Renders a template with the secrets",
+	Long: `Renders a template with the secrets.
+
+Templates use the Go text/template syntax, and are rendered with the
+secrets and files of the current environment, to produce configuration
+files that embed them (nginx, application.yml, JSON…).
+
+In templates:
+  - {{ .Secrets.PORT }} or {{ secret "PORT" }}: the value of a secret
+  - {{ file "certs/ca.pem" }}: the contents of a file
+  - {{ env }} or {{ .Environment }}: the name of the environment
+  - {{ required "API_KEY" .Secrets.API_KEY }}: fails if the value is empty
+  - {{ default "info" .Secrets.LOG_LEVEL }}: a default for empty values
+  - {{ base64 .Secrets.KEY }}: the value, encoded in base64
+  - {{ quote .Secrets.KEY }}: the value, double quoted and escaped as in JSON
+
+Using a secret or a file that does not exist is an error.
+
+The template is written to the standard output, or to --output.
+Templates listed in keystone.yaml are rendered again by ` + "`" + `ks env switch` + "`" + `,
+and by ` + "`" + `ks render` + "`" + ` without arguments. Their outputs are added to
+.gitignore:
+
+  renders:
+    - template: config/nginx.conf.tmpl
+      output: config/nginx.conf
+`,
+	Example: `ks render config/app.yml.tmpl -o config/app.yml
+
+# Render every template listed in keystone.yaml:
+ks render
+
+# With the values of another environment:
+ks render config/app.yml.tmpl --env prod`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		ctx.MustHaveEnvironment(currentEnvironment)
+
+		if config.IsLoggedIn() {
+			shouldFetchMessages()
+		}
+
+		if len(args) == 0 {
+			renders := ctx.ListRenders()
+			exitIfErr(ctx.RenderAll(currentEnvironment).Err())
+
+			display.TemplatesRendered(len(renders), currentEnvironment)
+			return
+		}
+
+		templatePath := args[0]
+
+		if renderOutput == "" {
+			out := ctx.RenderTemplate(templatePath, currentEnvironment)
+			exitIfErr(ctx.Err())
+
+			fmt.Print(out)
+			return
+		}
+
+		exitIfErr(ctx.
+			RenderTemplateTo(templatePath, renderOutput, currentEnvironment).
+			Err())
+
+		display.TemplateRendered(renderOutput)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(renderCmd)
+
+	renderCmd.Flags().
+		StringVarP(&renderOutput, "output", "o", "", "file to write the result to")
+}
@@ -9,6 +9,7 @@ import (
 	kserrors "github.com/wearedevx/keystone/cli/internal/errors"
 	"github.com/wearedevx/keystone/cli/internal/messages"
 	"github.com/wearedevx/keystone/cli/internal/utils"
+	"github.com/wearedevx/keystone/cli/pkg/core"
 	"github.com/wearedevx/keystone/cli/ui/display"
 	"github.com/wearedevx/keystone/cli/ui/prompts"
 )
@@ -67,6 +68,15 @@ This will override all the data you have stored locally.`,
 			),
 		)
 
+		// Restored files get their content and permissions back
+		exitIfErr(
+			ctx.FilesUseEnvironment(
+				currentEnvironment,
+				currentEnvironment,
+				core.CTX_OVERWRITE_LOCAL_FILES,
+			).Err(),
+		)
+
 		ms := messages.NewMessageService(ctx)
 		exitIfErr(
 			ms.SendEnvironments(ctx.AccessibleEnvironments).Err(),
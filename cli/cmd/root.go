@@ -41,6 +41,7 @@ var (
 var (
 	cfgFile            string = ""
 	currentEnvironment string
+	currentProject     string
 	quietOutput        bool
 	skipPrompts        bool
 	debug              bool
@@ -80,8 +81,15 @@ var (
 	noLoginCommands       []string
 )
 
+// Global flags whose value is the next argument
+var flagsWithValue = []string{"--env", "--project", "--config", "-c"}
+
 func findCurrentCommand(args []string) string {
-	for _, candidate := range args[1:] {
+	for index, candidate := range args[1:] {
+		if isIn(flagsWithValue, args[index]) {
+			continue
+		}
+
 		if !strings.HasPrefix(candidate, "-") &&
 			!strings.HasPrefix(candidate, "/") &&
 			candidate != "ks" {
@@ -119,10 +127,32 @@ func Initialize() {
 		if command == "init" {
 			ctx = core.New(core.CTX_INIT)
 		} else {
-			ctx = core.New(core.CTX_RESOLVE)
+			ctx = core.NewForProject(currentProject)
 		}
+
+		// Every project of the workspace is initialized by the command
+		if sourceAll {
+			return
+		}
+	}
+
+	initializeProject(checkProject, checkEnvironment)
+
+	if checkLogin && !config.IsLoggedIn() {
+		exit(kserrors.MustBeLoggedIn(nil))
+	}
+
+	// Project commands read secrets, ask for the passphrase before
+	// anything gets displayed
+	if checkLogin && checkProject {
+		mustUnlockPrivateKey()
 	}
+}
 
+// initializeProject function prepares the project of `ctx`:
+// it synchronizes its environments with the ones the user can access,
+// and sets `currentEnvironment`.
+func initializeProject(checkProject, checkEnvironment bool) {
 	if checkProject {
 		exitIfErr(ctx.Err())
 	}
@@ -132,6 +162,7 @@ func Initialize() {
 	current := ctx.CurrentEnvironment()
 	ctx.SetError(nil)
 
+	currentFromFile := currentEnvironment == ""
 	if currentEnvironment == "" {
 		currentEnvironment = current
 	}
@@ -140,6 +171,11 @@ func Initialize() {
 		exit(kserrors.NotAKeystoneProject(".", nil))
 	}
 
+	// Remember the project, for ks watch
+	if checkProject && config.AddProject(ctx.Wd) {
+		config.Write()
+	}
+
 	if checkProject && config.IsLoggedIn() {
 		es := environments.NewEnvironmentService(ctx)
 		exitIfErr(es.Err())
@@ -168,10 +204,11 @@ func Initialize() {
 			ctx.Init(models.Project{
 				Environments: environmentsToSave,
 			})
+			exitIfErr(ctx.SyncEnvironments(ctx.AccessibleEnvironments).Err())
 		}
-		ctx.RemoveForbiddenEnvironments(ctx.AccessibleEnvironments)
 
-		if currentEnvironment == "" {
+		// The current environment may have been renamed or removed
+		if currentFromFile || currentEnvironment == "" {
 			currentEnvironment = ctx.CurrentEnvironment()
 		}
 	}
@@ -182,10 +219,6 @@ func Initialize() {
 			ctx.SetCurrent(string(constants.DEV))
 		}
 	}
-
-	if checkLogin && !config.IsLoggedIn() {
-		exit(kserrors.MustBeLoggedIn(nil))
-	}
 }
 
 func init() {
@@ -217,10 +250,14 @@ func init() {
 	RootCmd.PersistentFlags().
 		StringVarP(&currentEnvironment, "env", "", "", "environment to use instead of the current one")
 
+	RootCmd.PersistentFlags().
+		StringVarP(&currentProject, "project", "", "", "path to the project to use, in a workspace")
+
 	cobra.OnInitialize(func() {
 		// Call directly initConfig. cobra doesn't call initConfig func.
 		err := config.InitConfig(cfgFile)
 		exitIfErr(err)
+		config.AskPassphrase = askKeyPassphrase
 
 		Initialize()
 	})
@@ -241,9 +278,12 @@ func init() {
 		"orga",
 		"project",
 		"hook",
+		"workspace",
+		"watch",
+		"agent",
 	}
 
 	noProjectCommands = noEnvironmentCommands
 
-	noLoginCommands = []string{"login", "source", "documentation", "completion", "__complete", "version", "backup"}
+	noLoginCommands = []string{"login", "source", "render", "run", "doc", "documentation", "completion", "__complete", "version", "backup", "workspace", "agent"}
 }
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wearedevx/keystone/cli/internal/config"
+	kserrors "github.com/wearedevx/keystone/cli/internal/errors"
+	"github.com/wearedevx/keystone/cli/internal/runner"
+	"github.com/wearedevx/keystone/cli/internal/utils"
+	"github.com/wearedevx/keystone/cli/pkg/core"
+)
+
+// runCmd represents the run command
+var runCmd = &cobra.Command{
+	Use:   "run -- <command> [arguments]...",
+	Short: "Runs a command with the secrets loaded in its environment",
+	Long: `Runs a command with the secrets loaded in its environment.
+
+Secrets of the current environment (or the one given with ` + "`" + `--env` + "`" + `)
+are added to the environment of the command, files are written, and the
+command is executed.
+Unlike ` + "`" + `eval "$(ks source)"` + "`" + `, nothing is leaked to your shell.
+
+Signals are forwarded to the command, and ` + "`" + `ks run` + "`" + ` exits with
+the same exit code.
+
+The command will not be started if required secrets or files are missing.
+
+When ` + "`" + `ks agent` + "`" + ` is running, secrets are taken from it, and messages are
+not fetched: run ` + "`" + `ks watch` + "`" + ` to keep them up to date.
+`,
+	Example: `ks run -- npm start
+
+# Run the command with the secrets of the 'staging' environment:
+ks run --env staging -- ./manage.py migrate
+`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		ctx.MustHaveEnvironment(currentEnvironment)
+
+		if secrets, ok := secretsFromAgent(); ok {
+			exitIfErr(ctx.
+				FilesUseEnvironment(
+					currentEnvironment,
+					currentEnvironment,
+					core.CTX_KEEP_LOCAL_FILES,
+				).
+				Err())
+
+			mustNotHaveAnyRequiredFileMissing(ctx)
+
+			environ := os.Environ()
+			for _, secret := range secrets {
+				exitIfErr(utils.CheckSecretContent(secret.Name))
+
+				environ = append(
+					environ,
+					fmt.Sprintf("%s=%s", secret.Name, secret.Value),
+				)
+			}
+
+			runWithEnvironment(args, environ)
+		}
+
+		if config.IsLoggedIn() {
+			shouldFetchMessages()
+		}
+
+		secrets := ctx.ListExpandedSecrets(currentEnvironment)
+		exitIfErr(ctx.Err())
+
+		exitIfErr(ctx.
+			FilesUseEnvironment(
+				currentEnvironment,
+				currentEnvironment,
+				core.CTX_KEEP_LOCAL_FILES,
+			).
+			Err())
+
+		mustNotHaveAnyRequiredThingMissing(ctx)
+		exitIfErr(ctx.ValidateSecretsForEnvironment(currentEnvironment).Err())
+
+		environ := os.Environ()
+
+		for _, secret := range secrets {
+			exitIfErr(utils.CheckSecretContent(secret.Name))
+
+			value := secret.Values[core.EnvironmentName(currentEnvironment)]
+
+			environ = append(
+				environ,
+				fmt.Sprintf("%s=%s", secret.Name, value),
+			)
+		}
+
+		runWithEnvironment(args, environ)
+	},
+}
+
+// runWithEnvironment function runs the command, and exits with its
+// exit code
+func runWithEnvironment(args []string, environ []string) {
+	exitCode, err := runner.Run(args, environ)
+	if err != nil {
+		exit(kserrors.CannotRunCommand(strings.Join(args, " "), err))
+	}
+
+	os.Exit(exitCode)
+}
+
+func init() {
+	RootCmd.AddCommand(runCmd)
+
+	// Everything after the command name belongs to the command
+	runCmd.Flags().SetInterspersed(false)
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/wearedevx/keystone/cli/internal/scanner"
+	"github.com/wearedevx/keystone/cli/ui/display"
+)
+
+var (
+	scanStaged  bool
+	scanEntropy float64
+)
+
+// scanCmd represents the scan command
+var scanCmd = &cobra.Command{
+	Use:   "scan [paths]",
+	Short: "Searches files for secret values",
+	Long: `Searches files for secret values.
+
+Looks for the values of the secrets, and the contents of the files, of
+every environment you can read, in the files of the project, or under the
+given paths.
+In a git repository, only the files that could be committed are searched:
+ignored files are not. With ` + "`" + `--staged` + "`" + `, the content staged for the
+next commit is searched instead.
+Secret files themselves are not searched.
+
+Short values, and values with an entropy under ` + "`" + `--entropy` + "`" + `, in bits per
+character, are too common to be looked for. Contents of files are looked
+for line by line.
+
+Every line containing a value is reported, with the name of the secret
+or of the file it belongs to. Values are never printed.
+With ` + "`" + `--quiet` + "`" + `, the result is printed as JSON.
+
+The command exits with a non-zero status code when a value is found.
+To run it before each commit, use:
+  $ ks hook install git-pre-commit
+`,
+	Example: `ks scan
+
+ks scan ./src ./config
+
+# Search what is about to be committed
+ks scan --staged
+`,
+	Run: func(_ *cobra.Command, args []string) {
+		paths := make([]string, 0, len(args))
+		for _, arg := range args {
+			p, err := cleanPathArgument(arg, ctx.Wd)
+			exitIfErr(err)
+
+			if p == "" {
+				p = "."
+			}
+
+			paths = append(paths, p)
+		}
+
+		leaks := ctx.ScanFiles(paths, scanStaged, scanEntropy)
+		exitIfErr(ctx.Err())
+
+		if quietOutput {
+			display.LeaksJSON(leaks)
+		} else {
+			display.Leaks(leaks)
+		}
+
+		if len(leaks) > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(scanCmd)
+
+	scanCmd.Flags().BoolVar(
+		&scanStaged,
+		"staged",
+		false,
+		"search the content staged for the next commit",
+	)
+	scanCmd.Flags().Float64Var(
+		&scanEntropy,
+		"entropy",
+		scanner.DefaultEntropyThreshold,
+		"minimum entropy of the values to look for, in bits per character",
+	)
+}
@@ -21,13 +21,60 @@ import (
 	"github.com/wearedevx/keystone/cli/ui/display"
 )
 
+// Flags of `ks secret add` and `ks secret set` to read the value from
+// a file or the standard input, rather than from the arguments
+var (
+	secretValueFile  string
+	secretValueStdin bool
+)
+
 // secretsCmd represents the secrets command
 var secretsCmd = &cobra.Command{
 	Use:   "secret",
 	Short: "Manages secrets",
 	Long: `Manages secrets.
 
-Used without arguments, displays a table of secrets.`,
+Used without arguments, displays a table of secrets.
+
+Secret values can be constrained in keystone.yaml:
+
+  env:
+    - key: DATABASE_URL
+      strict: true
+      type: url          # url, int, bool, base64, json or pem
+      regex: ^postgres://
+      min_length: 12
+      max_length: 255
+    - key: LOG_LEVEL
+      strict: false
+      enum: [debug, info, error]
+
+Values that do not satisfy those rules are refused by ` + "`" + `ks secret add` + "`" + `,
+` + "`" + `ks secret set` + "`" + `, ` + "`" + `ks source` + "`" + ` and ` + "`" + `ks run` + "`" + `, and when receiving them
+from other members.
+
+A secret can be required in some environments only:
+
+  env:
+    - key: SENTRY_DSN
+      strict: false
+      required_in: [staging, prod]
+
+Use ` + "`" + `ks secret require SENTRY_DSN --env prod` + "`" + ` to set it from the command line.
+In the table, such secrets are marked with ` + "`" + `* (staging, prod)` + "`" + `.
+
+An environment can inherit the values of another one:
+
+  environments:
+    staging:
+      inherits: dev
+    prod:
+      inherits: staging
+
+Secrets and files that are empty in the child environment then take the
+value of its parent. In the table, inherited values are marked with
+` + "`" + `↑ <parent>` + "`" + `; other values override the parent's.
+` + "`" + `ks source` + "`" + `, ` + "`" + `ks run` + "`" + ` and ` + "`" + `ks ci send` + "`" + ` use the inherited values.`,
 	Run: func(_ *cobra.Command, _ []string) {
 		ctx.MustHaveEnvironment(currentEnvironment)
 		environments := ctx.ListEnvironments()
@@ -28,6 +28,7 @@ import (
 )
 
 var addOptional bool = false
+var addDescription string
 
 // secretAddCmd represents the set command
 var secretAddCmd = &cobra.Command{
@@ -40,25 +41,30 @@ across environments, such as 'staging', 'prod',
 and 'dev' environments.
 
 The variable name will be added to all such environments,
-you will be asked its value for each environment
+you will be asked its value for each environment.
+
+With --from-file or --stdin, the value is read as is, and used for every
+environment without asking. Use them for multi-line or binary values,
+such as certificates or keys.
 `,
 	Example: `# Add a secret ` + "`" + `PORT` + "`" + ` to all environments
 # and set its value to 3000 for the current one.
 ks secret add PORT 3000
 
 # Add a secret ` + "`" + `PORT` + "`" + `without setting a default:
-ks secret add PORT`,
+ks secret add PORT
+
+# Add a private key, from a file or from the standard input:
+ks secret add TLS_KEY --from-file ./server.key
+vault read -field=key secret/tls | ks secret add TLS_KEY --stdin`,
 	Args: cobra.RangeArgs(1, 2),
 	Run: func(_ *cobra.Command, args []string) {
 		var err error
 		var useCache bool
 
 		secretName := args[0]
-		secretValue := ""
-
-		if len(args) == 2 {
-			secretValue = args[1]
-		}
+		secretValue, _ := readSecretValue(args, 1)
+		verbatim := secretValueIsVerbatim()
 
 		// Fetch messages first, because, if something have changed,
 		// and we get the messages after, we throw an error and the user
@@ -74,17 +80,28 @@ ks secret add PORT`,
 		if yes, values := secretService.IsSecretInCache(secretName); yes {
 			display.SecretAlreadyExitsts(values)
 
-			useCache = !prompts.ConfirmOverrideSecretValue(skipPrompts)
+			useCache = !prompts.ConfirmOverrideSecretValue(
+				skipPrompts || verbatim,
+			)
 		}
 
 		if !useCache {
-			environmentValueMap, err := secretService.SetValuesForEnvironments(
-				secretName,
-				secretValue,
-				ctx.AccessibleEnvironments,
-				skipPrompts,
-			)
-			exitIfErr(err)
+			var environmentValueMap map[string]string
+
+			if verbatim {
+				environmentValueMap = make(map[string]string)
+				for _, environment := range ctx.AccessibleEnvironments {
+					environmentValueMap[environment.Name] = secretValue
+				}
+			} else {
+				environmentValueMap, err = secretService.SetValuesForEnvironments(
+					secretName,
+					secretValue,
+					ctx.AccessibleEnvironments,
+					skipPrompts,
+				)
+				exitIfErr(err)
+			}
 
 			changes, messageService := mustFetchMessages()
 			flag := core.S_REQUIRED
@@ -102,6 +119,10 @@ ks secret add PORT`,
 				AddSecret(secretName, environmentValueMap, flag).
 				Err())
 
+			if verbatim {
+				exitIfErr(ctx.MarkSecretVerbatim(secretName).Err())
+			}
+
 			exitIfErr(messageService.
 				SendEnvironments(ctx.AccessibleEnvironments).
 				Err())
@@ -117,9 +138,14 @@ ks secret add PORT`,
 				err = kserrors.FailedToUpdateKeystoneFile(err)
 			}
 
-			exit(err)
+			exitIfErr(err)
+			mustDescribeAddedSecret(secretName)
+
+			exit(nil)
 		}
 
+		mustDescribeAddedSecret(secretName)
+
 		display.SecretIsSetForEnvironment(
 			secretName,
 			len(ctx.AccessibleEnvironments),
@@ -127,6 +153,18 @@ ks secret add PORT`,
 	},
 }
 
+// mustDescribeAddedSecret sets the description given with `--description`
+func mustDescribeAddedSecret(secretName string) {
+	if addDescription == "" {
+		return
+	}
+
+	documentation := ctx.GetSecret(secretName).Documentation
+	documentation.Description = addDescription
+
+	exitIfErr(ctx.DescribeSecret(secretName, documentation).Err())
+}
+
 func init() {
 	secretsCmd.AddCommand(secretAddCmd)
 
@@ -141,4 +179,10 @@ func init() {
 	// setCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 	secretAddCmd.Flags().
 		BoolVarP(&addOptional, "optional", "o", false, "mark the secret as optional")
+	secretAddCmd.Flags().
+		StringVarP(&addDescription, "description", "d", "", "what the secret is for")
+	secretAddCmd.Flags().
+		StringVar(&secretValueFile, "from-file", "", "read the value from a file")
+	secretAddCmd.Flags().
+		BoolVar(&secretValueStdin, "stdin", false, "read the value from the standard input")
 }
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	kserrors "github.com/wearedevx/keystone/cli/internal/errors"
+	"github.com/wearedevx/keystone/cli/ui/display"
+)
+
+var describeDescription string
+var describeOwner string
+var describeDocsURL string
+
+// secretDescribeCmd represents the describe command
+var secretDescribeCmd = &cobra.Command{
+	Use:   "describe <secret name>",
+	Short: "Documents a secret",
+	Long: `Documents a secret.
+
+Sets the description, the owner, and a link to the documentation of a
+secret, so that newcomers know what it is for and who to ask about it.
+Only the given flags are changed, the others are kept.
+
+The documentation is stored in keystone.yaml.
+`,
+	Example: `ks secret describe DATABASE_URL \
+  --description "Connection string to the main database" \
+  --owner "ops@example.com" \
+  --docs-url "https://wiki.example.com/database"
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		secretName := args[0]
+
+		if !ctx.HasSecret(secretName) {
+			exit(kserrors.SecretDoesNotExist(secretName, nil))
+		}
+
+		documentation := ctx.GetSecret(secretName).Documentation
+		flags := cmd.Flags()
+
+		if flags.Changed("description") {
+			documentation.Description = describeDescription
+		}
+		if flags.Changed("owner") {
+			documentation.Owner = describeOwner
+		}
+		if flags.Changed("docs-url") {
+			documentation.DocsURL = describeDocsURL
+		}
+
+		exitIfErr(ctx.DescribeSecret(secretName, documentation).Err())
+
+		display.SecretDescribed(secretName)
+	},
+}
+
+func init() {
+	secretsCmd.AddCommand(secretDescribeCmd)
+
+	secretDescribeCmd.Flags().
+		StringVarP(&describeDescription, "description", "d", "", "what the secret is for")
+	secretDescribeCmd.Flags().
+		StringVar(&describeOwner, "owner", "", "who to ask about the secret")
+	secretDescribeCmd.Flags().
+		StringVar(&describeDocsURL, "docs-url", "", "link to the documentation of the secret")
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+	kserrors "github.com/wearedevx/keystone/cli/internal/errors"
+	"github.com/wearedevx/keystone/cli/internal/generator"
+	"github.com/wearedevx/keystone/cli/internal/utils"
+	"github.com/wearedevx/keystone/cli/pkg/core"
+	"github.com/wearedevx/keystone/cli/ui/display"
+	"github.com/wearedevx/keystone/cli/ui/prompts"
+)
+
+var generateCharset string
+var generateLength int
+var generateSame bool
+var generateOptional bool
+
+// secretGenerateCmd represents the generate command
+var secretGenerateCmd = &cobra.Command{
+	Use:   "generate <secret name>",
+	Short: "Sets a secret to a random value",
+	Long: `Sets a secret to a random value.
+
+A different value is generated for every environment you have access to,
+unless ` + "`" + `--same` + "`" + ` is used. The secret is added if it does not exist yet,
+and the environments are sent to all members.
+Generated values are never displayed, nor written to your shell history.
+
+Available charsets:
+  - hex:          hexadecimal, --length is the number of random bytes
+  - base64:       base64, --length is the number of random bytes
+  - alphanumeric: letters and digits, --length is the number of characters
+  - url-safe:     letters, digits, '-' and '_' (default)
+  - uuid:         a random UUID, --length is ignored
+  - words:        a passphrase made of dictionary words, --length is the
+                  number of words (default 6)
+`,
+	Example: `ks secret generate JWT_SECRET
+
+# A 64 characters hexadecimal webhook secret, shared by all environments:
+ks secret generate WEBHOOK_SECRET --charset hex --length 32 --same
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		secretName := args[0]
+		charset := generator.Charset(generateCharset)
+
+		if !charset.IsValid() {
+			exit(kserrors.UnsupportedFlag(generateCharset, nil))
+		}
+
+		length := generateLength
+		if charset == generator.Words && !cmd.Flags().Changed("length") {
+			length = generator.DefaultWordCount
+		}
+
+		exitIfErr(utils.CheckSecretContent(secretName))
+		ctx.MustHaveEnvironment(currentEnvironment)
+
+		changes, messageService := mustFetchMessages()
+
+		exists := ctx.HasSecret(secretName)
+		if exists && !prompts.ConfirmOverrideSecretValue(skipPrompts) {
+			exit(nil)
+		}
+
+		environmentValueMap := make(map[string]string)
+		sharedValue := ""
+
+		for _, environment := range ctx.AccessibleEnvironments {
+			if sharedValue == "" || !generateSame {
+				value, err := generator.Generate(charset, length)
+				if err != nil {
+					exit(kserrors.UnkownError(err))
+				}
+
+				sharedValue = value
+			}
+
+			environmentValueMap[environment.Name] = sharedValue
+		}
+
+		if exists {
+			for environmentName, value := range environmentValueMap {
+				ctx.SetSecret(environmentName, secretName, value)
+			}
+			exitIfErr(ctx.Err())
+		} else {
+			flag := core.S_REQUIRED
+			if generateOptional {
+				flag = core.S_OPTIONAL
+			}
+
+			exitIfErr(ctx.
+				CompareNewSecretWithChanges(
+					secretName,
+					environmentValueMap,
+					changes,
+				).
+				AddSecret(secretName, environmentValueMap, flag).
+				Err())
+		}
+
+		exitIfErr(messageService.
+			SendEnvironments(ctx.AccessibleEnvironments).
+			Err())
+
+		display.SecretIsSetForEnvironment(
+			secretName,
+			len(environmentValueMap),
+		)
+	},
+}
+
+func init() {
+	secretsCmd.AddCommand(secretGenerateCmd)
+
+	charsets := make([]string, len(generator.Charsets))
+	for index, charset := range generator.Charsets {
+		charsets[index] = string(charset)
+	}
+
+	secretGenerateCmd.Flags().
+		StringVar(
+			&generateCharset,
+			"charset",
+			string(generator.URLSafe),
+			"kind of value to generate, one of "+strings.Join(charsets, ", "),
+		)
+	secretGenerateCmd.Flags().
+		IntVarP(&generateLength, "length", "l", generator.DefaultLength, "length of the value, see the charsets above")
+	secretGenerateCmd.Flags().
+		BoolVar(&generateSame, "same", false, "use the same value for all environments")
+	secretGenerateCmd.Flags().
+		BoolVarP(&generateOptional, "optional", "o", false, "mark the secret as optional")
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/wearedevx/keystone/cli/ui/display"
+)
+
+// secretHistoryCmd represents the history command
+var secretHistoryCmd = &cobra.Command{
+	Use:   "history <secret name>",
+	Short: "Lists the past values of a secret",
+	Long: `Lists the past values of a secret for the current environment.
+
+Every change applied to an environment, whether you made it or received it
+from another member, is recorded in a local history, encrypted with your key.
+Each entry shows who sent the value and when it was received.
+
+Use ` + "`" + `ks secret rollback` + "`" + ` to set a secret back to one of its past values.
+`,
+	Example: `ks secret history DATABASE_URL
+
+# History of the 'prod' environment
+ks --env prod secret history DATABASE_URL`,
+	Args: cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		ctx.MustHaveEnvironment(currentEnvironment)
+
+		secretName := args[0]
+
+		shouldFetchMessages()
+
+		entries := ctx.SecretHistory(currentEnvironment, secretName)
+		exitIfErr(ctx.Err())
+
+		display.SecretHistory(secretName, currentEnvironment, entries)
+	},
+}
+
+func init() {
+	secretsCmd.AddCommand(secretHistoryCmd)
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wearedevx/keystone/cli/internal/envfile"
+	kserrors "github.com/wearedevx/keystone/cli/internal/errors"
+	"github.com/wearedevx/keystone/cli/internal/utils"
+	"github.com/wearedevx/keystone/cli/pkg/core"
+	"github.com/wearedevx/keystone/cli/ui/display"
+	"github.com/wearedevx/keystone/cli/ui/prompts"
+)
+
+var importRequired bool
+var importStrategy string
+
+// secretImportCmd represents the import command
+var secretImportCmd = &cobra.Command{
+	Use:   "import <path to a .env file>",
+	Short: "Imports secrets from a .env file",
+	Long: `Imports secrets from a .env file.
+
+Every variable found in the file is added to the project,
+with its value set for the current environment (or the one given with --env).
+
+The differences between the file and the secrets already known are displayed.
+For every secret that has a different value, you will be asked to either:
+  - keep the current value,
+  - overwrite it with the imported value,
+  - or skip it entirely.
+
+With ` + "`" + `--skip` + "`" + `, no question is asked and ` + "`" + `--strategy` + "`" + ` is used instead.
+
+Secrets that are new to the project are optional, since other environments
+have no value for them yet. Use ` + "`" + `--required` + "`" + ` to mark them as required.
+
+Once done, the environments are sent to all members.
+`,
+	Example: `ks secret import .env
+
+# Import in the 'staging' environment, overwriting existing values:
+ks secret import .env.staging --env staging --skip --strategy overwrite
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		filePath := args[0]
+		strategy := core.ImportStrategy(importStrategy)
+
+		if !strategy.IsValid() {
+			exit(kserrors.UnsupportedFlag(importStrategy, nil))
+		}
+
+		ctx.MustHaveEnvironment(currentEnvironment)
+		ctx.MustHaveAccessToEnvironment(currentEnvironment)
+
+		values, err := envfile.Read(filePath, envfile.DefaultLoadOptions())
+		if err != nil {
+			exit(kserrors.FailedToReadDotEnv(filePath, err))
+		}
+
+		for secretName := range values {
+			exitIfErr(utils.CheckSecretContent(secretName))
+		}
+
+		// Fetch messages first, so that we compare with the latest values
+		_, messageService := mustFetchMessages()
+
+		imports := ctx.PrepareSecretsImport(currentEnvironment, values)
+		exitIfErr(ctx.Err())
+
+		display.SecretImportTable(imports, currentEnvironment)
+
+		count := 0
+		for index, secretImport := range imports {
+			if secretImport.IsConflict() {
+				if skipPrompts {
+					imports[index].Strategy = strategy
+				} else {
+					imports[index].Strategy = prompts.
+						ImportStrategyForSecret(secretImport.Name)
+				}
+			}
+
+			if imports[index].Strategy != core.ImportSkip {
+				count++
+			}
+		}
+
+		// Other environments have no value for new secrets yet
+		flag := core.S_OPTIONAL
+		if importRequired {
+			flag = core.S_REQUIRED
+		}
+
+		exitIfErr(ctx.
+			ImportSecrets(currentEnvironment, imports, flag).
+			Err())
+
+		exitIfErr(messageService.
+			SendEnvironments(ctx.AccessibleEnvironments).
+			Err())
+
+		display.SecretsImported(count, currentEnvironment)
+	},
+}
+
+func init() {
+	secretsCmd.AddCommand(secretImportCmd)
+
+	strategies := make([]string, len(core.ImportStrategies))
+	for index, strategy := range core.ImportStrategies {
+		strategies[index] = string(strategy)
+	}
+
+	secretImportCmd.Flags().
+		BoolVarP(&importRequired, "required", "r", false, "mark new secrets as required, they then need a value in every environment")
+	secretImportCmd.Flags().
+		StringVar(
+			&importStrategy,
+			"strategy",
+			string(core.ImportKeep),
+			"what to do with secrets that have a different value when prompts are skipped, one of "+
+				strings.Join(strategies, ", "),
+		)
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	kserrors "github.com/wearedevx/keystone/cli/internal/errors"
+	"github.com/wearedevx/keystone/cli/ui/display"
+)
+
+// secretInfoCmd represents the info command
+var secretInfoCmd = &cobra.Command{
+	Use:   "info <secret name>",
+	Short: "Displays information about a secret",
+	Long: `Displays information about a secret.
+
+Shows the documentation of the secret, and whether it has a value in each
+environment. Values themselves are never displayed.
+`,
+	Example: "ks secret info DATABASE_URL",
+	Args:    cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		secretName := args[0]
+
+		if !ctx.HasSecret(secretName) {
+			exit(kserrors.SecretDoesNotExist(secretName, nil))
+		}
+
+		shouldFetchMessages()
+
+		environments := ctx.ListEnvironments()
+		secret := ctx.GetSecret(secretName)
+		exitIfErr(ctx.Err())
+
+		display.SecretInfo(*secret, environments)
+	},
+}
+
+func init() {
+	secretsCmd.AddCommand(secretInfoCmd)
+}
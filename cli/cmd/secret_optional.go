@@ -29,9 +29,14 @@ var optionalCmd = &cobra.Command{
 
 When a file is marked as optional, its absence or emptiness won’t cause
 ` + "`" + `ks source` + "`" + ` or ` + "`" + `ks ci send` + "`" + ` to fail.
+
+With ` + "`" + `--env` + "`" + `, the secret is only made optional in that environment.
 `,
-	Example: "ks secret optional PORT",
-	Args:    cobra.ExactArgs(1),
+	Example: `ks secret optional PORT
+
+# Optional in dev, required elsewhere
+ks secret optional SENTRY_DSN --env dev`,
+	Args: cobra.ExactArgs(1),
 	Run: func(_ *cobra.Command, args []string) {
 		secretName := args[0]
 
@@ -39,6 +44,15 @@ When a file is marked as optional, its absence or emptiness won’t cause
 			exit(kserrors.SecretDoesNotExist(secretName, nil))
 		}
 
+		if environmentFlagIsSet() {
+			ctx.MustHaveEnvironment(currentEnvironment)
+			ctx.MarkSecretRequiredIn(secretName, currentEnvironment, false)
+			exitIfErr(ctx.Err())
+
+			display.SecretIsNowIn(secretName, display.OPTIONAL, currentEnvironment)
+			return
+		}
+
 		ctx.MarkSecretRequired(secretName, false)
 		exitIfErr(ctx.Err())
 
@@ -33,9 +33,20 @@ Secrets marked as required cannot be unset or set to blank value.
 If they are, ` + "`" + `ks source` + "`" + ` will exit with a non-zero exit code.
 
 Additionally, ` + "`" + `ks ci send` + "`" + ` will fail if a required secrets are missing.
+
+With ` + "`" + `--env` + "`" + `, the secret is only required in that environment.
+In keystone.yaml, it is listed in the ` + "`" + `required_in` + "`" + ` setting of the secret:
+
+  env:
+    - key: SENTRY_DSN
+      strict: false
+      required_in: [staging, prod]
 `,
-	Example: "ks secret require PORT",
-	Args:    cobra.ExactArgs(1),
+	Example: `ks secret require PORT
+
+# Only required in prod
+ks secret require SENTRY_DSN --env prod`,
+	Args: cobra.ExactArgs(1),
 	Run: func(_ *cobra.Command, args []string) {
 		secretName := args[0]
 
@@ -45,6 +56,10 @@ Additionally, ` + "`" + `ks ci send` + "`" + ` will fail if a required secrets a
 
 		// Check for blank values in environments
 		environments := ctx.ListEnvironments()
+		if environmentFlagIsSet() {
+			ctx.MustHaveEnvironment(currentEnvironment)
+			environments = []string{currentEnvironment}
+		}
 
 		secret := ctx.GetSecret(secretName)
 
@@ -62,7 +77,15 @@ Additionally, ` + "`" + `ks ci send` + "`" + ` will fail if a required secrets a
 			ctx.SetSecret(environment, secretName, value)
 		}
 
-		// All is OK, set is as optional
+		// All is OK, set is as required
+		if environmentFlagIsSet() {
+			ctx.MarkSecretRequiredIn(secretName, currentEnvironment, true)
+			exitIfErr(ctx.Err())
+
+			display.SecretIsNowIn(secretName, display.REQUIRED, currentEnvironment)
+			return
+		}
+
 		ctx.MarkSecretRequired(secretName, true)
 
 		exitIfErr(ctx.Err())
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/wearedevx/keystone/api/pkg/models"
+	kserrors "github.com/wearedevx/keystone/cli/internal/errors"
+	"github.com/wearedevx/keystone/cli/internal/history"
+	"github.com/wearedevx/keystone/cli/ui/display"
+)
+
+var rollbackTo int
+
+// secretRollbackCmd represents the rollback command
+var secretRollbackCmd = &cobra.Command{
+	Use:   "rollback <secret name> --to <entry number>",
+	Short: "Sets a secret back to a past value",
+	Long: `Sets a secret back to a past value for the current environment.
+
+The entry number is the one displayed by ` + "`" + `ks secret history` + "`" + `.
+The value is sent to the other members of the project, as with
+` + "`" + `ks secret set` + "`" + `.
+`,
+	Example: `ks secret history DATABASE_URL
+ks secret rollback DATABASE_URL --to 3
+
+# Rollback in the 'prod' environment
+ks --env prod secret rollback DATABASE_URL --to 3`,
+	Args: cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		ctx.MustHaveEnvironment(currentEnvironment)
+
+		secretName := args[0]
+
+		if !ctx.HasSecret(secretName) {
+			exit(kserrors.SecretDoesNotExist(secretName, nil))
+		}
+
+		_, messageService := mustFetchMessages()
+
+		entries := ctx.SecretHistory(currentEnvironment, secretName)
+		exitIfErr(ctx.Err())
+
+		if rollbackTo < 1 || rollbackTo > len(entries) {
+			exit(kserrors.HistoryEntryNotFound(secretName, rollbackTo, nil))
+		}
+
+		entry := entries[rollbackTo-1]
+		value := entry.Value
+		if entry.Type == history.EntryDelete {
+			value = ""
+		}
+
+		ctx.SetSecret(currentEnvironment, secretName, value)
+		exitIfErr(ctx.Err())
+
+		localEnvironment := ctx.LoadEnvironmentsFile().
+			GetByName(currentEnvironment)
+		environment := []models.Environment{{
+			Name:          localEnvironment.Name,
+			VersionID:     localEnvironment.VersionID,
+			EnvironmentID: localEnvironment.EnvironmentID,
+		}}
+
+		exitIfErr(
+			messageService.SendEnvironments(environment).Err(),
+		)
+
+		display.SecretRolledBack(secretName, currentEnvironment, rollbackTo)
+	},
+}
+
+func init() {
+	secretsCmd.AddCommand(secretRollbackCmd)
+
+	secretRollbackCmd.Flags().
+		IntVar(&rollbackTo, "to", 0, "number of the history entry to go back to")
+}
@@ -29,18 +29,28 @@ var secretsSetCmd = &cobra.Command{
 	Long: `Updates a secret’s value for the current environment.
 
 Changes the value of a secret without altering other environments.
+
+With --from-file or --stdin, the value is read as is. Use them for
+multi-line or binary values, such as certificates or keys.
 `,
 	Example: `ks secret set PORT 3000
 
 # Change the value of PORT for the 'staging' environment:
 ks --env staging PORT 4545
+
+# Set a certificate, from a file or from the standard input:
+ks secret set TLS_CERT --from-file ./server.crt
+cat ./server.crt | ks secret set TLS_CERT --stdin
 `,
-	Args: cobra.ExactArgs(2),
+	Args: cobra.RangeArgs(1, 2),
 	Run: func(_ *cobra.Command, args []string) {
 		ctx.MustHaveEnvironment(currentEnvironment)
 
 		secretName := args[0]
-		secretValue := args[1]
+		secretValue, given := readSecretValue(args, 1)
+		if !given {
+			exit(kserrors.MissingSecretValue(nil))
+		}
 
 		if !ctx.HasSecret(secretName) {
 			exit(kserrors.SecretDoesNotExist(secretName, nil))
@@ -49,6 +59,9 @@ ks --env staging PORT 4545
 		_, messageService := mustFetchMessages()
 
 		ctx.SetSecret(currentEnvironment, secretName, secretValue)
+		if secretValueIsVerbatim() {
+			ctx.MarkSecretVerbatim(secretName)
+		}
 		exitIfErr(ctx.Err())
 
 		localEnvironment := ctx.LoadEnvironmentsFile().
@@ -79,4 +92,8 @@ func init() {
 	// Cobra supports local flags which will only run when this command
 	// is called directly, e.g.:
 	// setCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	secretsSetCmd.Flags().
+		StringVar(&secretValueFile, "from-file", "", "read the value from a file")
+	secretsSetCmd.Flags().
+		BoolVar(&secretValueStdin, "stdin", false, "read the value from the standard input")
 }
@@ -40,7 +40,7 @@ The secret must not be required.`,
 			exit(kserrors.SecretDoesNotExist(secretName, nil))
 		}
 
-		if ctx.SecretIsRequired(secretName) {
+		if ctx.SecretIsRequiredIn(secretName, currentEnvironment) {
 			exit(kserrors.SecretRequired(secretName, nil))
 		}
 
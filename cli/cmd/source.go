@@ -17,13 +17,21 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/wearedevx/keystone/cli/internal/config"
+	kserrors "github.com/wearedevx/keystone/cli/internal/errors"
+	"github.com/wearedevx/keystone/cli/internal/serializers"
+	"github.com/wearedevx/keystone/cli/internal/workspacefile"
 
 	"github.com/wearedevx/keystone/cli/internal/utils"
 	core "github.com/wearedevx/keystone/cli/pkg/core"
-	"github.com/wearedevx/keystone/cli/ui"
+)
+
+var (
+	sourceFormat string
+	sourceAll    bool
 )
 
 // sourceCmd represents the source command
@@ -37,8 +45,8 @@ Environment variables values can then be loaded using eval, for example.
 Example:
 ` + "```" + `
 $ ks source
-export KEY="value"
-export OTHER_KEY="other_value"
+export KEY='value'
+export OTHER_KEY='other_value'
 
 $ eval "$(ks source)"
 $ echo $KEY
@@ -46,16 +54,77 @@ value
 $ echo $OTHER_KEY
 other_value
 ` + "```" + `
+
+Values can reference other secrets with ` + "`" + `${OTHER_SECRET}` + "`" + `, or
+` + "`" + `${OTHER_SECRET:-default}` + "`" + ` to use a default when it is empty.
+Use ` + "`" + `$$` + "`" + ` for a literal ` + "`" + `$` + "`" + `. References are resolved here, values are
+stored as they were entered, so each environment can change the parts.
+
+Use ` + "`" + `--format` + "`" + ` to output the secrets in another format:
+  - shell:      export KEY='value' lines, to be evaluated (default)
+  - fish:       set -gx KEY 'value' lines, for the fish shell
+  - powershell: $env:KEY = 'value' lines, for PowerShell
+  - dotenv:     KEY="value" lines, for .env files
+  - json:       a JSON object
+  - yaml:       a YAML mapping
+  - docker:     KEY=value lines, for ` + "`" + `docker run --env-file` + "`" + `
+  - systemd:    KEY="value" lines, for systemd’s EnvironmentFile
+
+Multi-line values are kept as they are. Values holding NUL bytes can only
+be output in the yaml format, and binary values cannot be output in json.
+
+In a workspace, ` + "`" + `--all` + "`" + ` outputs the secrets of every project listed in
+keystone.workspace.yaml. Their names are prefixed with the prefix of the
+project, which defaults to the name of its directory (API_ for ./api).
+
+When ` + "`" + `ks agent` + "`" + ` is running, secrets are taken from it, and messages are
+not fetched: run ` + "`" + `ks watch` + "`" + ` to keep them up to date.
+`,
+	Example: `eval "$(ks source)"
+
+# Write the secrets to a file for docker:
+ks source --format docker > .docker.env
+docker run --env-file .docker.env my-image
+
+# In fish, or in PowerShell:
+ks source --format fish | source
+ks source --format powershell | Invoke-Expression
+
+# Secrets of every project of the workspace, as API_PORT, CLI_PORT…
+eval "$(ks source --all)"
 `,
 	Run: func(_ *cobra.Command, _ []string) {
-		ctx.MustHaveEnvironment(currentEnvironment)
+		serializer, ok := serializers.Get(sourceFormat)
+		if !ok {
+			exit(kserrors.UnsupportedFormat(
+				sourceFormat,
+				strings.Join(serializers.Formats(), ", "),
+				nil,
+			))
+		}
 
-		if config.IsLoggedIn() {
-			shouldFetchMessages()
+		var variables []serializers.Variable
+		if sourceAll {
+			variables = workspaceVariables()
+		} else {
+			variables = sourceVariables()
 		}
 
-		env := ctx.ListSecrets()
+		out, err := serializer.Serialize(variables)
+		if err != nil {
+			exit(kserrors.CannotSerializeSecrets(sourceFormat, err))
+		}
 
+		fmt.Print(out)
+	},
+}
+
+// sourceVariables function returns the secrets of the current project
+// for the current environment, after writing its files
+func sourceVariables() []serializers.Variable {
+	ctx.MustHaveEnvironment(currentEnvironment)
+
+	if secrets, ok := secretsFromAgent(); ok {
 		exitIfErr(ctx.
 			FilesUseEnvironment(
 				currentEnvironment,
@@ -64,29 +133,100 @@ other_value
 			).
 			Err())
 
-		mustNotHaveAnyRequiredThingMissing(ctx)
+		mustNotHaveAnyRequiredFileMissing(ctx)
 
-		for _, secretInfo := range env {
-			value := secretInfo.Values[core.EnvironmentName(currentEnvironment)]
+		variables := make([]serializers.Variable, 0, len(secrets))
+		for _, secret := range secrets {
+			exitIfErr(utils.CheckSecretContent(secret.Name))
 
-			exitIfErr(
-				utils.CheckSecretContent(secretInfo.Name),
-			)
+			variables = append(variables, serializers.Variable{
+				Name:  secret.Name,
+				Value: secret.Value,
+			})
+		}
 
-			if secretInfo.Required && value == "" {
-				// make the eval crash in such situation
-				exit(fmt.Errorf(
-					"secret '%s' is required, but value is missing",
-					secretInfo.Name,
-				))
-			}
+		return variables
+	}
 
-			escapedValue := utils.DoubleQuoteEscape(string(value))
+	if config.IsLoggedIn() {
+		shouldFetchMessages()
+	}
 
-			ui.Print(`export %s="%s"`, secretInfo.Name, escapedValue)
+	env := ctx.ListExpandedSecrets(currentEnvironment)
+	exitIfErr(ctx.Err())
 
+	exitIfErr(ctx.
+		FilesUseEnvironment(
+			currentEnvironment,
+			currentEnvironment,
+			core.CTX_KEEP_LOCAL_FILES,
+		).
+		Err())
+
+	mustNotHaveAnyRequiredThingMissing(ctx)
+	exitIfErr(ctx.ValidateSecretsForEnvironment(currentEnvironment).Err())
+
+	variables := make([]serializers.Variable, 0, len(env))
+
+	for _, secretInfo := range env {
+		value := secretInfo.Values[core.EnvironmentName(currentEnvironment)]
+
+		exitIfErr(
+			utils.CheckSecretContent(secretInfo.Name),
+		)
+
+		if secretInfo.IsRequiredIn(currentEnvironment) && value == "" {
+			// make the eval crash in such situation
+			exit(fmt.Errorf(
+				"secret '%s' is required, but value is missing",
+				secretInfo.Name,
+			))
 		}
-	},
+
+		variables = append(variables, serializers.Variable{
+			Name:  secretInfo.Name,
+			Value: string(value),
+		})
+	}
+
+	return variables
+}
+
+// workspaceVariables function returns the secrets of every project of
+// the workspace, prefixed with the prefix of their project
+func workspaceVariables() []serializers.Variable {
+	root, found := workspacefile.FindRoot(CWD)
+	if !found {
+		exit(kserrors.NoWorkspace(CWD, nil))
+	}
+
+	workspace := new(workspacefile.WorkspaceFile).Load(root)
+	if err := workspace.Err(); err != nil {
+		exit(kserrors.FailedToReadWorkspaceFile(workspace.Path, err))
+	}
+
+	environmentFlag := currentEnvironment
+	variables := make([]serializers.Variable, 0)
+	seen := make(map[string]bool)
+
+	for _, project := range workspace.Projects {
+		ctx = core.NewForProject(workspace.Dir(project))
+		currentEnvironment = environmentFlag
+		initializeProject(true, true)
+
+		for _, variable := range sourceVariables() {
+			variable.Name = workspace.SecretPrefix(project) + variable.Name
+
+			if seen[variable.Name] {
+				exit(kserrors.WorkspaceSecretConflict(variable.Name, nil))
+			}
+			seen[variable.Name] = true
+
+			variables = append(variables, variable)
+		}
+	}
+
+	return variables
 }
 
 func init() {
@@ -101,4 +241,17 @@ func init() {
 	// Cobra supports local flags which will only run when this command
 	// is called directly, e.g.:
 	// sourceCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	sourceCmd.Flags().StringVarP(
+		&sourceFormat,
+		"format",
+		"f",
+		serializers.FormatShell,
+		"output format, one of "+strings.Join(serializers.Formats(), ", "),
+	)
+	sourceCmd.Flags().BoolVar(
+		&sourceAll,
+		"all",
+		false,
+		"source the secrets of every project of the workspace",
+	)
 }
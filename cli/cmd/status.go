@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wearedevx/keystone/api/pkg/models"
+	"github.com/wearedevx/keystone/cli/pkg/client"
+	"github.com/wearedevx/keystone/cli/ui"
+	"github.com/wearedevx/keystone/cli/ui/display"
+)
+
+// statusCmd represents the status command
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Shows an overview of the project state",
+	Long: `Shows an overview of the project state.
+
+Tells what needs to be done before working on the project:
+  - environments whose local version is behind the server,
+  - messages waiting on the server, to be fetched,
+  - files modified locally, to be sent with ` + "`" + `ks file set` + "`" + `,
+  - required secrets and files missing in each environment.
+
+It also shows the current environment and the CI services of the project.
+Nothing is fetched: messages stay on the server.
+
+With ` + "`" + `--quiet` + "`" + `, the result is printed as JSON.
+The command exits with a non-zero status code when action is needed.
+`,
+	Example: `ks status
+
+# In a script:
+ks status --quiet > status.json || echo "run ks secret to sync"
+`,
+	Args: cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		ctx.MustHaveEnvironment(currentEnvironment)
+
+		server := models.GetMessageByEnvironmentResponse{}
+		online := false
+
+		c, kcErr := client.NewKeystoneClient()
+		if kcErr == nil {
+			var err error
+
+			server, err = c.Messages().GetMessages(ctx.GetProjectID())
+			if err != nil {
+				if !quietOutput {
+					ui.PrintStdErr(
+						"WARNING: Could not reach the server (%s)",
+						err.Error(),
+					)
+				}
+			} else {
+				online = true
+			}
+		}
+
+		status := ctx.Status(currentEnvironment, server, online)
+		exitIfErr(ctx.Err())
+
+		if quietOutput {
+			display.StatusJSON(status)
+		} else {
+			display.Status(status)
+		}
+
+		if status.NeedsAction() {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(statusCmd)
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	d "runtime/debug"
@@ -216,6 +217,24 @@ func mustNotHaveAnyRequiredThingMissing(ctx *core.Context) {
 		fmt.Fprintf(os.Stderr, "Required Secret is missing: %s\n", ms)
 	}
 
+	hasMissingFiles := printMissingFiles(ctx)
+
+	if hasMissingFiles || hasMisssingSecrets {
+		os.Exit(1)
+	}
+}
+
+// Exits the program if required files are missing.
+// Secrets served by the agent have been checked by it already.
+func mustNotHaveAnyRequiredFileMissing(ctx *core.Context) {
+	if printMissingFiles(ctx) {
+		os.Exit(1)
+	}
+}
+
+// printMissingFiles function reports the required files that are missing,
+// and returns true if there are any
+func printMissingFiles(ctx *core.Context) bool {
 	missingFiles, hasMissingFiles := ctx.
 		MissingFilesForEnvironment(currentEnvironment)
 
@@ -223,9 +242,7 @@ func mustNotHaveAnyRequiredThingMissing(ctx *core.Context) {
 		fmt.Fprintf(os.Stderr, "Required file is missing or empty: %s\n", mf)
 	}
 
-	if hasMissingFiles || hasMisssingSecrets {
-		os.Exit(1)
-	}
+	return hasMissingFiles
 }
 
 // Exits the program if the user is not admin on the proec
@@ -310,6 +327,12 @@ func mustGetOrganizationName(
 	return organizationName
 }
 
+// environmentFlagIsSet function returns true if the user chose an
+// environment with `--env`
+func environmentFlagIsSet() bool {
+	return RootCmd.PersistentFlags().Changed("env")
+}
+
 // Returns true if `needle` is in `haytack`
 func isIn(haystack []string, needle string) bool {
 	for _, hay := range haystack {
@@ -320,3 +343,79 @@ func isIn(haystack []string, needle string) bool {
 
 	return false
 }
+
+// askKeyPassphrase prompts the user the passphrase of their private key,
+// unless prompts are skipped
+func askKeyPassphrase() (string, error) {
+	if skipPrompts {
+		return "", config.ErrorPassphraseRequired
+	}
+
+	return prompts.KeyPassphrase(), nil
+}
+
+// mustUnlockPrivateKey asks for the passphrase of a protected private key
+// right away, rather than in the middle of a command
+func mustUnlockPrivateKey() {
+	if !config.IsPrivateKeyProtected() {
+		return
+	}
+
+	if _, err := config.GetUserPrivateKey(); err != nil {
+		exit(kserrors.CannotUnlockPrivateKey(err))
+	}
+}
+
+// secretValueIsVerbatim returns true if the secret value is read from a
+// file or from the standard input, see `readSecretValue`
+func secretValueIsVerbatim() bool {
+	return secretValueFile != "" || secretValueStdin
+}
+
+// readSecretValue returns the value of a secret given as the argument at
+// `index`, with `--from-file` or with `--stdin`.
+// Values from a file or the standard input are read verbatim, so that
+// multi-line and binary values are kept as they are.
+// The second returned value is false if no value was given.
+func readSecretValue(args []string, index int) (value string, given bool) {
+	sources := 0
+	for _, isSet := range []bool{
+		len(args) > index,
+		secretValueFile != "",
+		secretValueStdin,
+	} {
+		if isSet {
+			sources++
+		}
+	}
+
+	if sources > 1 {
+		exit(kserrors.TooManySecretValues(nil))
+	}
+
+	switch {
+	case secretValueFile != "":
+		/* #nosec
+		 * The user chose the file, its content is only stored
+		 */
+		data, err := ioutil.ReadFile(secretValueFile)
+		if err != nil {
+			exit(kserrors.CannotReadSecretValue(secretValueFile, err))
+		}
+
+		return string(data), true
+
+	case secretValueStdin:
+		data, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			exit(kserrors.CannotReadSecretValue("the standard input", err))
+		}
+
+		return string(data), true
+
+	case len(args) > index:
+		return args[index], true
+	}
+
+	return "", false
+}
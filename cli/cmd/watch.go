@@ -0,0 +1,233 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"os/signal"
+	"path"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wearedevx/keystone/cli/internal/config"
+	kserrors "github.com/wearedevx/keystone/cli/internal/errors"
+	"github.com/wearedevx/keystone/cli/internal/messages"
+	"github.com/wearedevx/keystone/cli/internal/watcher"
+	"github.com/wearedevx/keystone/cli/pkg/core"
+	"github.com/wearedevx/keystone/cli/ui"
+)
+
+var (
+	watchInterval    time.Duration
+	watchMaxInterval time.Duration
+	watchForeground  bool
+	watchPidFile     string
+	watchLogFile     string
+)
+
+// watchCmd represents the watch command
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Keeps the secrets of every project up to date",
+	Long: `Keeps the secrets of every project up to date.
+
+Every project you have used ks in, on this machine, is checked for new
+messages at regular intervals. Changes are applied as with any other
+command, and your hook is run when secrets or files change.
+
+Each change is reported on a line of its own, without values, to the
+standard output or to ` + "`" + `--log-file` + "`" + `.
+When the server cannot be reached, the delay between checks doubles,
+up to ` + "`" + `--max-interval` + "`" + `.
+
+Without ` + "`" + `--foreground` + "`" + `, the command starts in the background and returns.
+To run it as a systemd user service:
+
+  [Service]
+  ExecStart=/usr/local/bin/ks watch --foreground
+  Restart=on-failure
+`,
+	Example: `ks watch
+
+# As a service, checking every 5 minutes
+ks watch --foreground --interval 5m --pidfile /run/user/1000/ks-watch.pid`,
+	Args: cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		if !watchForeground {
+			detachWatch()
+			return
+		}
+
+		exitIfErr(runWatch())
+	},
+}
+
+// runWatch function checks the projects until it is stopped by a signal,
+// or until the connection token becomes invalid.
+// The pidfile and the log file are cleaned up before it returns.
+func runWatch() error {
+	var out io.Writer = os.Stdout
+	if watchLogFile != "" {
+		/* #nosec
+		 * The log file path is given by the user
+		 */
+		logFile, err := os.OpenFile(
+			watchLogFile,
+			os.O_CREATE|os.O_APPEND|os.O_WRONLY,
+			0o600,
+		)
+		if err != nil {
+			return kserrors.CannotWriteFile(watchLogFile, err)
+		}
+		defer logFile.Close()
+
+		out = logFile
+	}
+
+	if watchPidFile != "" {
+		if err := watcher.WritePidFile(watchPidFile); err != nil {
+			return kserrors.CannotWriteFile(watchPidFile, err)
+		}
+		defer watcher.RemovePidFile(watchPidFile)
+	}
+
+	notifier := watcher.NewNotifier(out)
+	backoff := watcher.NewBackoff(watchInterval, watchMaxInterval)
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+
+	notifier.Notify("ks", "watching %d project(s)", len(config.GetProjects()))
+
+	for {
+		failed := false
+
+		for _, dir := range config.GetProjects() {
+			reached, err := watchProject(dir, notifier)
+			if err != nil {
+				notifier.Notify("ks", "stopped")
+				return err
+			}
+
+			if !reached {
+				failed = true
+			}
+		}
+
+		select {
+		case <-time.After(backoff.Next(failed)):
+		case <-stop:
+			notifier.Notify("ks", "stopped")
+			return nil
+		}
+	}
+}
+
+// detachWatch function starts `ks watch --foreground` in the background
+func detachWatch() {
+	// The background process cannot prompt for the passphrase,
+	// it finds the unlocked key in the keyring
+	mustUnlockPrivateKey()
+
+	logFile := watchLogFile
+	if logFile == "" {
+		configDir, err := config.ConfigDir()
+		if err != nil {
+			exit(kserrors.UnkownError(err))
+		}
+
+		logFile = path.Join(configDir, "watch.log")
+	}
+
+	args := append(os.Args[1:], "--foreground", "--log-file", logFile)
+
+	pid, err := watcher.Detach(args, logFile)
+	if err != nil {
+		exit(kserrors.CannotStartWatch(err))
+	}
+
+	ui.PrintSuccess("Watching in the background (pid %d), logs in %s", pid, logFile)
+}
+
+// watchProject function applies the pending messages of the project
+// in `dir`, and reports the changes.
+// It returns false if the server could not be reached, and an error
+// if watching must stop.
+func watchProject(dir string, notifier *watcher.Notifier) (bool, error) {
+	projectCtx := core.NewForProject(dir)
+	if err := projectCtx.Err(); err != nil {
+		// The project was moved or removed, it is not a server failure
+		notifier.Notify(dir, "skipped: %s", err.Name())
+		return true, nil
+	}
+
+	projectName := projectCtx.GetProjectName()
+
+	ms := messages.NewMessageService(projectCtx)
+	changes := ms.GetMessages()
+
+	if err := ms.Err(); err != nil {
+		notifier.Notify(projectName, "error: %s", err.Name())
+
+		if err.Name() == kserrors.InvalidConnectionToken(nil).Name() {
+			config.CheckExpiredTokenError(err)
+			return false, err
+		}
+
+		return false, nil
+	}
+
+	for environmentName, environmentChanges := range changes.Environments {
+		for _, change := range environmentChanges {
+			switch {
+			case change.IsSecretAdd():
+				notifier.Notify(projectName, "%s: secret %s added", environmentName, change.Name)
+			case change.IsSecretChange():
+				notifier.Notify(projectName, "%s: secret %s updated", environmentName, change.Name)
+			case change.IsSecretDelete():
+				notifier.Notify(projectName, "%s: secret %s removed", environmentName, change.Name)
+			case change.IsFileDelete():
+				notifier.Notify(projectName, "%s: file %s removed", environmentName, change.Name)
+			case change.IsFile():
+				notifier.Notify(projectName, "%s: file %s updated", environmentName, change.Name)
+			}
+		}
+	}
+
+	return true, nil
+}
+
+func init() {
+	RootCmd.AddCommand(watchCmd)
+
+	watchCmd.Flags().DurationVar(
+		&watchInterval,
+		"interval",
+		30*time.Second,
+		"delay between two checks",
+	)
+	watchCmd.Flags().DurationVar(
+		&watchMaxInterval,
+		"max-interval",
+		10*time.Minute,
+		"longest delay between two checks, when the server cannot be reached",
+	)
+	watchCmd.Flags().BoolVar(
+		&watchForeground,
+		"foreground",
+		false,
+		"do not start in the background, for service managers",
+	)
+	watchCmd.Flags().StringVar(
+		&watchPidFile,
+		"pidfile",
+		"",
+		"write the process id to this file",
+	)
+	watchCmd.Flags().StringVar(
+		&watchLogFile,
+		"log-file",
+		"",
+		"append the changes to this file instead of the standard output",
+	)
+}
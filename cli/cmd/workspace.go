@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	kserrors "github.com/wearedevx/keystone/cli/internal/errors"
+	"github.com/wearedevx/keystone/cli/internal/keystonefile"
+	"github.com/wearedevx/keystone/cli/internal/workspacefile"
+	"github.com/wearedevx/keystone/cli/ui/display"
+)
+
+// workspaceCmd represents the workspace command
+var workspaceCmd = &cobra.Command{
+	Use:   "workspace",
+	Short: "Manages the projects of a repository",
+	Long: `Manages the projects of a repository.
+
+A repository can hold several Keystone projects, each in its own directory
+with its own keystone.yaml. They are listed in keystone.workspace.yaml,
+at the root of the repository:
+
+  projects:
+    - path: api
+    - path: cli
+      prefix: KS_
+
+Commands run in a project directory use that project.
+Elsewhere in the workspace, choose one with ` + "`" + `--project <path>` + "`" + `.
+` + "`" + `ks source --all` + "`" + ` outputs the secrets of every project, with their prefix.
+
+Used without arguments, lists the projects of the workspace.
+`,
+	Example: `ks workspace
+
+ks --project api secret`,
+	Args: cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		workspace := mustLoadWorkspace()
+
+		names := make(map[string]string)
+		for _, project := range workspace.Projects {
+			ksfile := keystonefile.LoadKeystoneFile(workspace.Dir(project))
+			if ksfile.Err() == nil {
+				names[project.Path] = ksfile.ProjectName
+			}
+		}
+
+		display.WorkspaceProjects(workspace, names)
+	},
+}
+
+// mustLoadWorkspace function loads the workspace the working directory
+// belongs to, or exits
+func mustLoadWorkspace() *workspacefile.WorkspaceFile {
+	root, found := workspacefile.FindRoot(CWD)
+	if !found {
+		exit(kserrors.NoWorkspace(CWD, nil))
+	}
+
+	workspace := new(workspacefile.WorkspaceFile).Load(root)
+	if err := workspace.Err(); err != nil {
+		exit(kserrors.FailedToReadWorkspaceFile(workspace.Path, err))
+	}
+
+	return workspace
+}
+
+func init() {
+	RootCmd.AddCommand(workspaceCmd)
+}
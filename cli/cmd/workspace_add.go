@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	kserrors "github.com/wearedevx/keystone/cli/internal/errors"
+	"github.com/wearedevx/keystone/cli/internal/keystonefile"
+	"github.com/wearedevx/keystone/cli/internal/workspacefile"
+	"github.com/wearedevx/keystone/cli/ui/display"
+)
+
+var workspaceAddPrefix string
+
+// workspaceAddCmd represents the workspace add command
+var workspaceAddCmd = &cobra.Command{
+	Use:   "add <project path>",
+	Short: "Adds a project to the workspace",
+	Long: `Adds a project to the workspace.
+
+The project directory must have a keystone.yaml.
+If the current directory does not belong to a workspace yet,
+keystone.workspace.yaml is created in it.
+
+With ` + "`" + `ks source --all` + "`" + `, the secrets of the project are prefixed with
+` + "`" + `--prefix` + "`" + `, which defaults to the name of its directory in upper case.
+Adding a project again changes its prefix.
+`,
+	Example: `ks workspace add api
+ks workspace add services/web --prefix FRONT_`,
+	Args: cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		root, found := workspacefile.FindRoot(CWD)
+		workspace := workspacefile.NewWorkspaceFile(CWD)
+		if found {
+			workspace = new(workspacefile.WorkspaceFile).Load(root)
+			if err := workspace.Err(); err != nil {
+				exit(kserrors.FailedToReadWorkspaceFile(workspace.Path, err))
+			}
+		}
+
+		projectDir := filepath.Join(CWD, args[0])
+		if !keystonefile.ExistsKeystoneFile(projectDir) {
+			exit(kserrors.ProjectNotFound(args[0], nil))
+		}
+
+		projectPath, err := filepath.Rel(workspace.Root(), projectDir)
+		if err != nil || projectPath == ".." ||
+			strings.HasPrefix(projectPath, ".."+string(filepath.Separator)) {
+			exit(kserrors.ProjectNotFound(
+				args[0],
+				fmt.Errorf("not in the workspace at %s", workspace.Root()),
+			))
+		}
+
+		project := workspacefile.Project{
+			Path:   projectPath,
+			Prefix: workspaceAddPrefix,
+		}
+
+		if err = workspace.AddProject(project).Save().Err(); err != nil {
+			exit(kserrors.FailedToUpdateWorkspaceFile(workspace.Path, err))
+		}
+
+		project, _ = workspace.GetProject(projectPath)
+		display.WorkspaceProjectAdded(project.Path, workspace.SecretPrefix(project))
+	},
+}
+
+func init() {
+	workspaceCmd.AddCommand(workspaceAddCmd)
+
+	workspaceAddCmd.Flags().StringVar(
+		&workspaceAddPrefix,
+		"prefix",
+		"",
+		"prefix for the secret names of the project, with ks source --all",
+	)
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	kserrors "github.com/wearedevx/keystone/cli/internal/errors"
+	"github.com/wearedevx/keystone/cli/ui/display"
+)
+
+// workspaceRmCmd represents the workspace rm command
+var workspaceRmCmd = &cobra.Command{
+	Use:   "rm <project path>",
+	Short: "Removes a project from the workspace",
+	Long: `Removes a project from the workspace.
+
+The project itself is left untouched.
+`,
+	Example: "ks workspace rm api",
+	Args:    cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		workspace := mustLoadWorkspace()
+
+		projectPath, err := filepath.Rel(
+			workspace.Root(),
+			filepath.Join(CWD, args[0]),
+		)
+		if err != nil {
+			exit(kserrors.ProjectNotFound(args[0], err))
+		}
+
+		if _, ok := workspace.GetProject(projectPath); !ok {
+			exit(kserrors.ProjectNotFound(args[0], nil))
+		}
+
+		if err = workspace.RemoveProject(projectPath).Save().Err(); err != nil {
+			exit(kserrors.FailedToUpdateWorkspaceFile(workspace.Path, err))
+		}
+
+		display.WorkspaceProjectRemoved(projectPath)
+	},
+}
+
+func init() {
+	workspaceCmd.AddCommand(workspaceRmCmd)
+}
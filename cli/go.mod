@@ -30,6 +30,7 @@ require (
 	github.com/xanzy/go-gitlab v0.51.1
 	go.uber.org/zap v1.18.1 // indirect
 	golang.org/x/oauth2 v0.0.0-20210819190943-2bc19b11175f
+	golang.org/x/sys v0.0.0-20211216021012-1d35b9e2eb4e
 	golang.org/x/time v0.0.0-20210723032227-1f47c861a9ac // indirect
 	gopkg.in/yaml.v2 v2.4.0
 )
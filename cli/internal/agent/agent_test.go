@@ -0,0 +1,198 @@
+package agent
+
+import (
+	"errors"
+	"os"
+	"path"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeSource struct {
+	mu      sync.Mutex
+	version string
+	secrets map[string][]Secret
+	loads   int
+}
+
+func (f *fakeSource) Secrets(project, environment string) (string, []Secret, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if environment == "" {
+		environment = "dev"
+	}
+
+	secrets, ok := f.secrets[project+"/"+environment]
+	if !ok {
+		return "", nil, errors.New("unknown project")
+	}
+
+	f.loads++
+
+	return environment, secrets, nil
+}
+
+func (f *fakeSource) Version(string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.version
+}
+
+func (f *fakeSource) set(version string, secrets []Secret) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.version = version
+	f.secrets["/app/dev"] = secrets
+}
+
+func startAgent(t *testing.T, source Source) (*Server, string) {
+	t.Helper()
+
+	socketPath := path.Join(t.TempDir(), "agent.sock")
+
+	listener, err := Listen(socketPath)
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	info, err := os.Stat(socketPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("socket permissions are %o, want 600", perm)
+	}
+
+	server := NewServer(source)
+	go server.Serve(listener)
+
+	return server, socketPath
+}
+
+func TestListAndGet(t *testing.T) {
+	source := &fakeSource{secrets: map[string][]Secret{}}
+	source.set("1", []Secret{{"PORT", "3000"}, {"HOST", "localhost"}})
+
+	_, socketPath := startAgent(t, source)
+
+	client, err := Dial(socketPath)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer client.Close()
+
+	secrets, err := client.List("/app", "dev")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	want := []Secret{{"PORT", "3000"}, {"HOST", "localhost"}}
+	if !reflect.DeepEqual(secrets, want) {
+		t.Errorf("List = %v, want %v", secrets, want)
+	}
+
+	value, err := client.Get("/app", "dev", "HOST")
+	if err != nil || value != "localhost" {
+		t.Errorf("Get = %q, %v, want localhost", value, err)
+	}
+
+	if source.loads != 1 {
+		t.Errorf("secrets were loaded %d times, want 1", source.loads)
+	}
+
+	if _, err = client.Get("/app", "dev", "NOPE"); err == nil {
+		t.Errorf("Get of an unknown secret should fail")
+	}
+	if _, err = client.List("/other", "dev"); err == nil {
+		t.Errorf("List of an unknown project should fail")
+	}
+}
+
+func TestBinaryValues(t *testing.T) {
+	binary := "\x00\xff\xfe-key"
+	multiline := "-----BEGIN KEY-----\r\nabc\n-----END KEY-----\n"
+
+	source := &fakeSource{secrets: map[string][]Secret{}}
+	source.set("1", []Secret{{"BIN", binary}, {"PEM", multiline}})
+
+	_, socketPath := startAgent(t, source)
+
+	client, err := Dial(socketPath)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer client.Close()
+
+	secrets, err := client.List("/app", "dev")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	want := []Secret{{"BIN", binary}, {"PEM", multiline}}
+	if !reflect.DeepEqual(secrets, want) {
+		t.Errorf("List = %q, want %q", secrets, want)
+	}
+
+	value, err := client.Get("/app", "dev", "BIN")
+	if err != nil || value != binary {
+		t.Errorf("Get = %q, %v, want %q", value, err, binary)
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	source := &fakeSource{secrets: map[string][]Secret{}}
+	source.set("1", []Secret{{"PORT", "3000"}})
+
+	server, socketPath := startAgent(t, source)
+
+	client, err := Dial(socketPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	if _, err = client.List("/app", "dev"); err != nil {
+		t.Fatal(err)
+	}
+
+	subscriber, err := Dial(socketPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer subscriber.Close()
+
+	events, err := subscriber.Subscribe("/app", "")
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+
+	source.set("2", []Secret{{"PORT", "4000"}, {"HOST", "localhost"}})
+	server.Refresh()
+
+	select {
+	case event := <-events:
+		want := Event{Project: "/app", Environment: "dev", Changed: []string{"HOST", "PORT"}}
+		if !reflect.DeepEqual(event, want) {
+			t.Errorf("event = %+v, want %+v", event, want)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no event received")
+	}
+
+	value, err := client.Get("/app", "dev", "PORT")
+	if err != nil || value != "4000" {
+		t.Errorf("Get after refresh = %q, %v, want 4000", value, err)
+	}
+}
+
+func TestListenRefusesRunningAgent(t *testing.T) {
+	_, socketPath := startAgent(t, &fakeSource{secrets: map[string][]Secret{}})
+
+	if _, err := Listen(socketPath); err == nil {
+		t.Errorf("Listen should fail while an agent is running")
+	}
+}
@@ -0,0 +1,123 @@
+package agent
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"path"
+	"time"
+)
+
+// SocketEnvVar is the environment variable telling where the agent listens
+const SocketEnvVar = "KS_AGENT_SOCK"
+
+// SocketPath function returns the path of the agent socket:
+// $KS_AGENT_SOCK when it is set, or `agent.sock` in `configDir`
+func SocketPath(configDir string) string {
+	if socketPath := os.Getenv(SocketEnvVar); socketPath != "" {
+		return socketPath
+	}
+
+	return path.Join(configDir, "agent.sock")
+}
+
+// Client struct talks to an agent
+type Client struct {
+	conn    net.Conn
+	scanner *bufio.Scanner
+}
+
+// Dial function connects to the agent listening on `socketPath`
+func Dial(socketPath string) (*Client, error) {
+	conn, err := net.DialTimeout("unix", socketPath, time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	return &Client{conn: conn, scanner: scanner}, nil
+}
+
+// Close method closes the connection
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// List method returns the secrets of `environment` in the project
+// at `project`. An empty `environment` means the current one.
+func (c *Client) List(project, environment string) ([]Secret, error) {
+	response, err := c.do(Request{
+		Op:          OpList,
+		Project:     project,
+		Environment: environment,
+	})
+
+	return response.Secrets, err
+}
+
+// Get method returns the value of a single secret
+func (c *Client) Get(project, environment, name string) (string, error) {
+	response, err := c.do(Request{
+		Op:          OpGet,
+		Project:     project,
+		Environment: environment,
+		Name:        name,
+	})
+
+	return response.Value, err
+}
+
+// Subscribe method returns the changes to the secrets of the project,
+// or of every project when `project` is empty.
+// The channel is closed when the connection is.
+func (c *Client) Subscribe(project, environment string) (<-chan Event, error) {
+	if _, err := c.do(Request{
+		Op:          OpSubscribe,
+		Project:     project,
+		Environment: environment,
+	}); err != nil {
+		return nil, err
+	}
+
+	events := make(chan Event)
+
+	go func() {
+		defer close(events)
+
+		for c.scanner.Scan() {
+			var event Event
+			if json.Unmarshal(c.scanner.Bytes(), &event) == nil {
+				events <- event
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+func (c *Client) do(request Request) (response Response, err error) {
+	if err = json.NewEncoder(c.conn).Encode(request); err != nil {
+		return response, err
+	}
+
+	if !c.scanner.Scan() {
+		if err = c.scanner.Err(); err == nil {
+			err = errors.New("connection closed by the agent")
+		}
+		return response, err
+	}
+
+	if err = json.Unmarshal(c.scanner.Bytes(), &response); err != nil {
+		return response, err
+	}
+
+	if response.Error != "" {
+		err = errors.New(response.Error)
+	}
+
+	return response, err
+}
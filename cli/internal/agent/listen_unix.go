@@ -0,0 +1,17 @@
+// +build !windows
+
+package agent
+
+import (
+	"net"
+	"syscall"
+)
+
+// listenUnix opens the socket with a umask that leaves it only accessible
+// to the current user from the start, not after a chmod
+func listenUnix(socketPath string) (net.Listener, error) {
+	previous := syscall.Umask(0o077)
+	defer syscall.Umask(previous)
+
+	return net.Listen("unix", socketPath)
+}
@@ -0,0 +1,11 @@
+// +build windows
+
+package agent
+
+import "net"
+
+// listenUnix opens the socket, whose access is restricted by the
+// permissions of its directory
+func listenUnix(socketPath string) (net.Listener, error) {
+	return net.Listen("unix", socketPath)
+}
@@ -0,0 +1,27 @@
+// +build darwin
+
+package agent
+
+import (
+	"fmt"
+	"net"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// checkPeer function refuses clients run by another user
+func checkPeer(conn net.Conn) error {
+	return withUnixFd(conn, func(fd uintptr) error {
+		cred, err := unix.GetsockoptXucred(int(fd), unix.SOL_LOCAL, unix.LOCAL_PEERCRED)
+		if err != nil {
+			return err
+		}
+
+		if int(cred.Uid) != os.Getuid() {
+			return fmt.Errorf("client run by uid %d", cred.Uid)
+		}
+
+		return nil
+	})
+}
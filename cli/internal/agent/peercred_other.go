@@ -0,0 +1,11 @@
+// +build !linux,!darwin
+
+package agent
+
+import "net"
+
+// checkPeer function accepts every client: on this system, only the
+// permissions of the socket keep other users out
+func checkPeer(conn net.Conn) error {
+	return nil
+}
@@ -0,0 +1,28 @@
+// +build linux darwin
+
+package agent
+
+import (
+	"errors"
+	"net"
+)
+
+// withUnixFd function calls `fn` with the file descriptor of `conn`
+func withUnixFd(conn net.Conn, fn func(fd uintptr) error) error {
+	unixConn, ok := conn.(*net.UnixConn)
+	if !ok {
+		return errors.New("not a unix socket")
+	}
+
+	raw, err := unixConn.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	var fnErr error
+	if err = raw.Control(func(fd uintptr) { fnErr = fn(fd) }); err != nil {
+		return err
+	}
+
+	return fnErr
+}
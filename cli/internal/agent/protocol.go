@@ -0,0 +1,116 @@
+// Package agent serves decrypted secrets from memory over a Unix socket,
+// so that commands do not have to read and parse the cache every time.
+//
+// The protocol is made of JSON documents, one per line. A client sends a
+// Request, and the agent answers with a Response. After a `subscribe`
+// request, the agent keeps the connection open and sends an Event
+// whenever secrets change.
+package agent
+
+import (
+	"encoding/json"
+
+	"github.com/wearedevx/keystone/api/pkg/models"
+)
+
+// Operations of the protocol
+const (
+	OpList      = "list"
+	OpGet       = "get"
+	OpSubscribe = "subscribe"
+)
+
+// Request struct is sent by clients
+type Request struct {
+	Op string `json:"op"`
+	// Project is the root directory of the project
+	Project string `json:"project"`
+	// Environment defaults to the current environment of the project
+	Environment string `json:"environment,omitempty"`
+	// Name of the secret, for `get`
+	Name string `json:"name,omitempty"`
+}
+
+// Secret struct is a secret and its value
+type Secret struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Response struct is sent by the agent for every request
+type Response struct {
+	Secrets []Secret `json:"secrets,omitempty"`
+	Value   string   `json:"value,omitempty"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// Values that are not valid UTF-8 are sent in base64, with an `encoding`
+// field, so that binary secrets survive JSON
+
+type secretJSON struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+func (s Secret) MarshalJSON() ([]byte, error) {
+	value, encoding := models.EncodeSecretValue(s.Value)
+
+	return json.Marshal(secretJSON{
+		Name:     s.Name,
+		Value:    value,
+		Encoding: encoding,
+	})
+}
+
+func (s *Secret) UnmarshalJSON(data []byte) (err error) {
+	var raw secretJSON
+
+	if err = json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	s.Name = raw.Name
+	s.Value, err = models.DecodeSecretValue(raw.Value, raw.Encoding)
+
+	return err
+}
+
+type responseJSON struct {
+	Secrets  []Secret `json:"secrets,omitempty"`
+	Value    string   `json:"value,omitempty"`
+	Encoding string   `json:"encoding,omitempty"`
+	Error    string   `json:"error,omitempty"`
+}
+
+func (r Response) MarshalJSON() ([]byte, error) {
+	value, encoding := models.EncodeSecretValue(r.Value)
+
+	return json.Marshal(responseJSON{
+		Secrets:  r.Secrets,
+		Value:    value,
+		Encoding: encoding,
+		Error:    r.Error,
+	})
+}
+
+func (r *Response) UnmarshalJSON(data []byte) (err error) {
+	var raw responseJSON
+
+	if err = json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	r.Secrets = raw.Secrets
+	r.Error = raw.Error
+	r.Value, err = models.DecodeSecretValue(raw.Value, raw.Encoding)
+
+	return err
+}
+
+// Event struct is sent to subscribers when secrets change
+type Event struct {
+	Project     string   `json:"project"`
+	Environment string   `json:"environment"`
+	Changed     []string `json:"changed"`
+}
@@ -0,0 +1,320 @@
+package agent
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Source interface gives the agent the secrets of the projects
+type Source interface {
+	// Secrets returns the values of `environment` in the project, in the
+	// order they should be used. An empty `environment` means the current
+	// one, whose name is returned.
+	Secrets(project, environment string) (string, []Secret, error)
+	// Version returns a token that changes whenever the secrets of the
+	// project may have changed
+	Version(project string) string
+}
+
+type entry struct {
+	project     string
+	environment string
+	version     string
+	secrets     []Secret
+}
+
+// Server struct keeps secrets in memory and serves them
+type Server struct {
+	log    *log.Logger
+	source Source
+
+	mu          sync.Mutex
+	entries     map[string]*entry
+	subscribers map[chan Event]Request
+}
+
+// NewServer function returns a Server getting secrets from `source`
+func NewServer(source Source) *Server {
+	return &Server{
+		log:         log.New(log.Writer(), "[Agent] ", 0),
+		source:      source,
+		entries:     make(map[string]*entry),
+		subscribers: make(map[chan Event]Request),
+	}
+}
+
+// Listen function opens the socket at `socketPath`, only accessible to the
+// current user. A stale socket left by a previous agent is replaced.
+// The socket is created with these permissions, in a directory only the
+// user can access when it has to create it.
+func Listen(socketPath string) (net.Listener, error) {
+	if err := os.MkdirAll(filepath.Dir(socketPath), 0o700); err != nil {
+		return nil, err
+	}
+
+	if conn, err := net.Dial("unix", socketPath); err == nil {
+		conn.Close()
+		return nil, fmt.Errorf("an agent is already listening on %s", socketPath)
+	}
+	_ = os.Remove(socketPath)
+
+	listener, err := listenUnix(socketPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = os.Chmod(socketPath, 0o600); err != nil {
+		listener.Close()
+		return nil, err
+	}
+
+	return listener, nil
+}
+
+// Serve method answers the clients connecting to `listener`,
+// until it is closed.
+// Clients run by other users are refused.
+func (s *Server) Serve(listener net.Listener) error {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+
+		if err = checkPeer(conn); err != nil {
+			s.log.Printf("refused client: %v\n", err)
+			conn.Close()
+			continue
+		}
+
+		go s.handle(conn)
+	}
+}
+
+// Refresh method reloads the secrets whose project changed,
+// and notifies the subscribers
+func (s *Server) Refresh() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, e := range s.entries {
+		version := s.source.Version(e.project)
+		if version == e.version {
+			continue
+		}
+
+		_, secrets, err := s.source.Secrets(e.project, e.environment)
+		if err != nil {
+			// Served again once it is fixed
+			delete(s.entries, key)
+			continue
+		}
+
+		changed := changedNames(e.secrets, secrets)
+		e.version = version
+		e.secrets = secrets
+
+		if len(changed) > 0 {
+			s.publish(Event{
+				Project:     e.project,
+				Environment: e.environment,
+				Changed:     changed,
+			})
+		}
+	}
+}
+
+// RefreshEvery method calls Refresh every `interval`, until `stop` is closed
+func (s *Server) RefreshEvery(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.Refresh()
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+
+	scanner := bufio.NewScanner(conn)
+	encoder := json.NewEncoder(conn)
+
+	for scanner.Scan() {
+		var request Request
+
+		if err := json.Unmarshal(scanner.Bytes(), &request); err != nil {
+			_ = encoder.Encode(Response{Error: "bad request: " + err.Error()})
+			continue
+		}
+
+		if request.Op == OpSubscribe {
+			s.subscribe(request, conn, encoder)
+			return
+		}
+
+		if err := encoder.Encode(s.answer(request)); err != nil {
+			s.log.Printf("cannot answer: %v\n", err)
+			return
+		}
+	}
+}
+
+func (s *Server) answer(request Request) Response {
+	switch request.Op {
+	case OpList:
+		secrets, err := s.secrets(request.Project, request.Environment)
+		if err != nil {
+			return Response{Error: err.Error()}
+		}
+
+		return Response{Secrets: secrets}
+
+	case OpGet:
+		secrets, err := s.secrets(request.Project, request.Environment)
+		if err != nil {
+			return Response{Error: err.Error()}
+		}
+
+		for _, secret := range secrets {
+			if secret.Name == request.Name {
+				return Response{Value: secret.Value}
+			}
+		}
+
+		return Response{Error: fmt.Sprintf("no secret named %s", request.Name)}
+
+	default:
+		return Response{Error: fmt.Sprintf("unknown operation %s", request.Op)}
+	}
+}
+
+// secrets returns the secrets of the environment, from memory
+// when they are still up to date
+func (s *Server) secrets(project, environment string) ([]Secret, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	version := s.source.Version(project)
+
+	if e, ok := s.entries[key(project, environment)]; ok && e.version == version {
+		return e.secrets, nil
+	}
+
+	name, secrets, err := s.source.Secrets(project, environment)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &entry{
+		project:     project,
+		environment: name,
+		version:     version,
+		secrets:     secrets,
+	}
+
+	// The current environment may change, so it is not cached
+	// under an empty name
+	if environment != "" {
+		s.entries[key(project, environment)] = e
+	}
+
+	return secrets, nil
+}
+
+func (s *Server) subscribe(request Request, conn net.Conn, encoder *json.Encoder) {
+	events := make(chan Event, 16)
+
+	s.mu.Lock()
+	s.subscribers[events] = request
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.subscribers, events)
+		s.mu.Unlock()
+	}()
+
+	if err := encoder.Encode(Response{}); err != nil {
+		return
+	}
+
+	// Reading only fails when the client goes away
+	gone := make(chan struct{})
+	go func() {
+		_, _ = bufio.NewReader(conn).ReadByte()
+		close(gone)
+	}()
+
+	for {
+		select {
+		case event := <-events:
+			if err := encoder.Encode(event); err != nil {
+				return
+			}
+		case <-gone:
+			return
+		}
+	}
+}
+
+// publish sends an event to the interested subscribers.
+// Must be called with the lock held.
+func (s *Server) publish(event Event) {
+	for events, request := range s.subscribers {
+		if request.Project != "" && request.Project != event.Project {
+			continue
+		}
+		if request.Environment != "" && request.Environment != event.Environment {
+			continue
+		}
+
+		select {
+		case events <- event:
+		default:
+			// Slow subscribers miss events rather than block the agent
+		}
+	}
+}
+
+func key(project, environment string) string {
+	return project + "\x00" + environment
+}
+
+// changedNames returns the names of the secrets that were added, removed
+// or changed between `before` and `after`
+func changedNames(before, after []Secret) []string {
+	values := make(map[string]string)
+	for _, secret := range before {
+		values[secret.Name] = secret.Value
+	}
+
+	changed := make([]string, 0)
+	for _, secret := range after {
+		if value, ok := values[secret.Name]; !ok || value != secret.Value {
+			changed = append(changed, secret.Name)
+		}
+		delete(values, secret.Name)
+	}
+
+	for name := range values {
+		changed = append(changed, name)
+	}
+
+	sort.Strings(changed)
+
+	return changed
+}
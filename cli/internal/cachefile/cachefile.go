@@ -0,0 +1,320 @@
+// Package cachefile reads and writes the files of the .keystone/cache
+// directory, encrypted at rest.
+//
+// Encrypted contents start with a header, so that files written by
+// older versions, in plaintext, can still be read and migrated.
+// Empty files are left empty: an empty file in cache means that the
+// value is not set, and there is nothing to protect in it.
+package cachefile
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// Cipher encrypts and decrypts the contents of cached files
+type Cipher interface {
+	Encrypt(data []byte) ([]byte, error)
+	Decrypt(data []byte) ([]byte, error)
+}
+
+// header prefixes every encrypted file
+var header = []byte("KSCACHE1\n")
+
+// IsEncrypted function returns true if `data` has been encrypted
+// by a Codec
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, header)
+}
+
+// A Codec encrypts cached contents with its Cipher
+type Codec struct {
+	cipher Cipher
+}
+
+// New function returns a Codec that uses `cipher`
+func New(cipher Cipher) Codec {
+	return Codec{cipher: cipher}
+}
+
+// Encrypt method encrypts `data` and prefixes it with the header
+func (c Codec) Encrypt(data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return []byte{}, nil
+	}
+
+	encrypted, err := c.cipher.Encrypt(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(append([]byte{}, header...), encrypted...), nil
+}
+
+// Decrypt method decrypts `data`.
+// Data without the header is returned as is.
+func (c Codec) Decrypt(data []byte) ([]byte, error) {
+	if !IsEncrypted(data) {
+		return data, nil
+	}
+
+	return c.cipher.Decrypt(data[len(header):])
+}
+
+// ReadFile method reads and decrypts the file at `path`
+func (c Codec) ReadFile(path string) ([]byte, error) {
+	/* #nosec
+	 * Caller must ensure that path is within the cache
+	 */
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Decrypt(data)
+}
+
+// WriteFile method encrypts `data` and writes it at `path`,
+// creating the parent directories if needed
+func (c Codec) WriteFile(path string, data []byte) error {
+	encrypted, err := c.Encrypt(data)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, encrypted, 0o600)
+}
+
+// Migrate method encrypts every plaintext file found under `root`,
+// except for those `skip` returns true for.
+// It returns the number of files that have been encrypted.
+func (c Codec) Migrate(root string, skip func(path string) bool) (int, error) {
+	count := 0
+
+	err := filepath.Walk(
+		root,
+		func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if !info.Mode().IsRegular() || skip(path) {
+				return nil
+			}
+
+			/* #nosec */
+			data, err := ioutil.ReadFile(path)
+			if err != nil {
+				return err
+			}
+
+			if len(data) == 0 || IsEncrypted(data) {
+				return nil
+			}
+
+			if err = c.WriteFile(path, data); err != nil {
+				return fmt.Errorf("failed to encrypt %s (%w)", path, err)
+			}
+			count++
+
+			return nil
+		},
+	)
+
+	return count, err
+}
+
+// rekeySuffix is appended to the directory being rekeyed to name the
+// directory Rekey works in, next to it: nothing in the cache is ever
+// written there
+const rekeySuffix = ".rekey"
+
+// Rekey method decrypts every file found under `root` and encrypts it
+// again with `to`, except for those `skip` returns true for.
+// Files are all written aside first, then replace the originals, which
+// are kept aside until the end, so that a failure, or an interruption,
+// leaves the cache as it was.
+// It returns the number of files that have been encrypted.
+func (c Codec) Rekey(
+	root string,
+	to Codec,
+	skip func(path string) bool,
+) (int, error) {
+	root = filepath.Clean(root)
+	workDir := root + rekeySuffix
+	encryptedDir := filepath.Join(workDir, "new")
+	originalsDir := filepath.Join(workDir, "old")
+
+	// Left by an interrupted rekey
+	if err := restoreOriginals(root, originalsDir); err != nil {
+		return 0, err
+	}
+	if err := os.RemoveAll(workDir); err != nil {
+		return 0, fmt.Errorf("failed to remove %s (%w)", workDir, err)
+	}
+
+	rekeyed := make([]string, 0)
+
+	err := filepath.Walk(
+		root,
+		func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if !info.Mode().IsRegular() || skip(path) {
+				return nil
+			}
+
+			data, err := c.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("failed to decrypt %s (%w)", path, err)
+			}
+
+			if len(data) == 0 {
+				return nil
+			}
+
+			encrypted, err := to.Encrypt(data)
+			if err != nil {
+				return fmt.Errorf("failed to encrypt %s (%w)", path, err)
+			}
+
+			relativePath, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+
+			encryptedPath := filepath.Join(encryptedDir, relativePath)
+			if err = writeFileAll(encryptedPath, encrypted); err != nil {
+				return fmt.Errorf("failed to encrypt %s (%w)", path, err)
+			}
+
+			rekeyed = append(rekeyed, relativePath)
+
+			return nil
+		},
+	)
+	if err != nil {
+		_ = os.RemoveAll(workDir)
+		return 0, err
+	}
+
+	for _, relativePath := range rekeyed {
+		p := filepath.Join(root, relativePath)
+		originalPath := filepath.Join(originalsDir, relativePath)
+
+		if err = os.MkdirAll(filepath.Dir(originalPath), 0o700); err == nil {
+			if err = os.Rename(p, originalPath); err == nil {
+				err = os.Rename(filepath.Join(encryptedDir, relativePath), p)
+			}
+		}
+
+		if err != nil {
+			if restoreErr := restoreOriginals(root, originalsDir); restoreErr != nil {
+				return 0, fmt.Errorf(
+					"failed to replace %s (%w), the original files are in %s",
+					p,
+					err,
+					originalsDir,
+				)
+			}
+
+			_ = os.RemoveAll(workDir)
+			return 0, fmt.Errorf("failed to replace %s (%w)", p, err)
+		}
+	}
+
+	if err = os.RemoveAll(workDir); err != nil {
+		return 0, fmt.Errorf("failed to remove %s (%w)", workDir, err)
+	}
+
+	return len(rekeyed), nil
+}
+
+// restoreOriginals moves every file under `originalsDir` back to its place
+// under `root`, replacing the rekeyed one
+func restoreOriginals(root string, originalsDir string) error {
+	if _, err := os.Stat(originalsDir); os.IsNotExist(err) {
+		return nil
+	}
+
+	return filepath.Walk(
+		originalsDir,
+		func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if !info.Mode().IsRegular() {
+				return nil
+			}
+
+			relativePath, err := filepath.Rel(originalsDir, path)
+			if err != nil {
+				return err
+			}
+
+			p := filepath.Join(root, relativePath)
+			// Rename cannot replace a file on every system
+			if err = os.Remove(p); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to restore %s (%w)", p, err)
+			}
+
+			if err = os.MkdirAll(filepath.Dir(p), 0o700); err != nil {
+				return fmt.Errorf("failed to restore %s (%w)", p, err)
+			}
+
+			if err = os.Rename(path, p); err != nil {
+				return fmt.Errorf("failed to restore %s (%w)", p, err)
+			}
+
+			return nil
+		},
+	)
+}
+
+// writeFileAll writes `data` at `p`, creating its parent directories
+func writeFileAll(p string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(p), 0o700); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(p, data, 0o600)
+}
+
+// fallbackCipher encrypts with its first cipher, and decrypts with the
+// first one that succeeds
+type fallbackCipher []Cipher
+
+// Fallback function returns a cipher that encrypts with `cipher`, and
+// decrypts with it or, if it fails, with one of the `previous` ones.
+// Contents encrypted before a change of key can still be read with it.
+func Fallback(cipher Cipher, previous ...Cipher) Cipher {
+	if len(previous) == 0 {
+		return cipher
+	}
+
+	return append(fallbackCipher{cipher}, previous...)
+}
+
+func (f fallbackCipher) Encrypt(data []byte) ([]byte, error) {
+	return f[0].Encrypt(data)
+}
+
+func (f fallbackCipher) Decrypt(data []byte) (decrypted []byte, err error) {
+	for _, cipher := range f {
+		if decrypted, err = cipher.Decrypt(data); err == nil {
+			return decrypted, nil
+		}
+	}
+
+	return nil, err
+}
@@ -0,0 +1,304 @@
+package cachefile
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+)
+
+// xorCipher is a reversible stand-in for the real cipher
+type xorCipher byte
+
+func (c xorCipher) Encrypt(data []byte) ([]byte, error) {
+	out := make([]byte, len(data))
+	for i, b := range data {
+		out[i] = b ^ byte(c)
+	}
+	return out, nil
+}
+
+func (c xorCipher) Decrypt(data []byte) ([]byte, error) {
+	return c.Encrypt(data)
+}
+
+func TestWriteAndReadFile(t *testing.T) {
+	p := path.Join(t.TempDir(), "prod", "files", "config.json")
+	codec := New(xorCipher(42))
+
+	if err := codec.WriteFile(p, []byte(`{"password":"hunter2"}`)); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	raw, err := ioutil.ReadFile(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsEncrypted(raw) || bytes.Contains(raw, []byte("hunter2")) {
+		t.Errorf("file is not encrypted: %q", raw)
+	}
+
+	data, err := codec.ReadFile(p)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(data) != `{"password":"hunter2"}` {
+		t.Errorf("got %q", data)
+	}
+}
+
+func TestEmptyFilesStayEmpty(t *testing.T) {
+	p := path.Join(t.TempDir(), ".env")
+	codec := New(xorCipher(42))
+
+	if err := codec.WriteFile(p, []byte{}); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	info, err := os.Stat(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() != 0 {
+		t.Errorf("expected an empty file, got %d bytes", info.Size())
+	}
+}
+
+func TestReadPlaintextFile(t *testing.T) {
+	p := path.Join(t.TempDir(), ".env")
+	if err := ioutil.WriteFile(p, []byte("PORT=\"3000\"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := New(xorCipher(42)).ReadFile(p)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(data) != "PORT=\"3000\"\n" {
+		t.Errorf("got %q", data)
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	root := t.TempDir()
+	codec := New(xorCipher(42))
+
+	plain := path.Join(root, "dev", ".env")
+	encrypted := path.Join(root, "prod", ".env")
+	skipped := path.Join(root, "dev", "history")
+	empty := path.Join(root, "dev", "files", "empty")
+
+	for p, content := range map[string]string{
+		plain:   "PORT=\"3000\"\n",
+		skipped: "already encrypted journal",
+		empty:   "",
+	} {
+		if err := os.MkdirAll(path.Dir(p), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := codec.WriteFile(encrypted, []byte("PORT=\"80\"\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	count, err := codec.Migrate(root, func(p string) bool { return p == skipped })
+	if err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("expected 1 file to be migrated, got %d", count)
+	}
+
+	for p, expected := range map[string]string{
+		plain:     "PORT=\"3000\"\n",
+		encrypted: "PORT=\"80\"\n",
+	} {
+		data, err := codec.ReadFile(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != expected {
+			t.Errorf("%s: got %q", p, data)
+		}
+	}
+
+	raw, _ := ioutil.ReadFile(skipped)
+	if string(raw) != "already encrypted journal" {
+		t.Errorf("skipped file was modified")
+	}
+
+	// A second run has nothing left to do
+	if count, _ = codec.Migrate(root, func(p string) bool { return p == skipped }); count != 0 {
+		t.Errorf("expected nothing to migrate, got %d", count)
+	}
+}
+
+func TestRekey(t *testing.T) {
+	root := t.TempDir()
+	from := New(xorCipher(42))
+	to := New(xorCipher(7))
+
+	encrypted := path.Join(root, "prod", ".env")
+	plain := path.Join(root, "dev", ".env")
+	skipped := path.Join(root, "dev", "history")
+
+	if err := from.WriteFile(encrypted, []byte("PORT=\"80\"\n")); err != nil {
+		t.Fatal(err)
+	}
+	for p, content := range map[string]string{
+		plain:   "PORT=\"3000\"\n",
+		skipped: "journal",
+	} {
+		if err := os.MkdirAll(path.Dir(p), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	count, err := from.Rekey(root, to, func(p string) bool { return p == skipped })
+	if err != nil {
+		t.Fatalf("Rekey: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("expected 2 files to be rekeyed, got %d", count)
+	}
+
+	for p, expected := range map[string]string{
+		plain:     "PORT=\"3000\"\n",
+		encrypted: "PORT=\"80\"\n",
+	} {
+		raw, _ := ioutil.ReadFile(p)
+		if !IsEncrypted(raw) {
+			t.Errorf("%s: not encrypted", p)
+		}
+
+		data, err := to.ReadFile(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != expected {
+			t.Errorf("%s: got %q", p, data)
+		}
+	}
+
+	raw, _ := ioutil.ReadFile(skipped)
+	if string(raw) != "journal" {
+		t.Errorf("skipped file was modified")
+	}
+}
+
+// checkedCipher is a stand-in that, like the real cipher, fails to
+// decrypt what another key encrypted
+type checkedCipher byte
+
+func (c checkedCipher) Encrypt(data []byte) ([]byte, error) {
+	out, _ := xorCipher(c).Encrypt(data)
+	return append([]byte{byte(c)}, out...), nil
+}
+
+func (c checkedCipher) Decrypt(data []byte) ([]byte, error) {
+	if len(data) == 0 || data[0] != byte(c) {
+		return nil, errors.New("wrong key")
+	}
+	return xorCipher(c).Decrypt(data[1:])
+}
+
+func TestFallback(t *testing.T) {
+	old := New(checkedCipher(1))
+	current := New(Fallback(checkedCipher(2), checkedCipher(1)))
+
+	encrypted, _ := old.Encrypt([]byte("PORT=80"))
+	data, err := current.Decrypt(encrypted)
+	if err != nil || string(data) != "PORT=80" {
+		t.Errorf("previous keys should decrypt: %q, %v", data, err)
+	}
+
+	encrypted, _ = current.Encrypt([]byte("PORT=80"))
+	if _, err = old.Decrypt(encrypted); err == nil {
+		t.Errorf("the current key should encrypt")
+	}
+
+	if _, err = New(Fallback(checkedCipher(3))).Decrypt(encrypted); err == nil {
+		t.Errorf("unknown keys should not decrypt")
+	}
+}
+
+func TestRekeyFailureKeepsFiles(t *testing.T) {
+	root := t.TempDir()
+	from := New(checkedCipher(1))
+	to := New(checkedCipher(2))
+
+	good := path.Join(root, "dev", ".env")
+	bad := path.Join(root, "prod", ".env")
+
+	if err := from.WriteFile(good, []byte("PORT=3000")); err != nil {
+		t.Fatal(err)
+	}
+	if err := New(checkedCipher(3)).WriteFile(bad, []byte("PORT=80")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := from.Rekey(root, to, func(string) bool { return false }); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if data, err := from.ReadFile(good); err != nil || string(data) != "PORT=3000" {
+		t.Errorf("files should be left as they were: %q, %v", data, err)
+	}
+
+	if _, err := os.Stat(root + rekeySuffix); !os.IsNotExist(err) {
+		t.Errorf("temporary files should be removed")
+	}
+}
+
+func TestRekeyKeepsFilesNamedLikeTemporaryFiles(t *testing.T) {
+	root := t.TempDir()
+	from := New(xorCipher(42))
+	to := New(xorCipher(7))
+
+	tracked := path.Join(root, "dev", "files", "foo"+rekeySuffix)
+	if err := from.WriteFile(tracked, []byte("content")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := from.Rekey(root, to, func(string) bool { return false }); err != nil {
+		t.Fatalf("Rekey: %v", err)
+	}
+
+	if data, err := to.ReadFile(tracked); err != nil || string(data) != "content" {
+		t.Errorf("tracked file should be rekeyed: %q, %v", data, err)
+	}
+}
+
+func TestRekeyRestoresInterruptedRekey(t *testing.T) {
+	root := t.TempDir()
+	from := New(xorCipher(42))
+	to := New(xorCipher(7))
+
+	// Interrupted after the original was moved aside,
+	// before the rekeyed file replaced it
+	original := path.Join(root+rekeySuffix, "old", "dev", ".env")
+	if err := from.WriteFile(original, []byte("PORT=3000")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := from.Rekey(root, to, func(string) bool { return false }); err != nil {
+		t.Fatalf("Rekey: %v", err)
+	}
+
+	data, err := to.ReadFile(path.Join(root, "dev", ".env"))
+	if err != nil || string(data) != "PORT=3000" {
+		t.Errorf("the original should be restored and rekeyed: %q, %v", data, err)
+	}
+
+	if _, err := os.Stat(root + rekeySuffix); !os.IsNotExist(err) {
+		t.Errorf("temporary files should be removed")
+	}
+}
@@ -58,7 +58,7 @@ func getArchiveBuffer(
 		return nil, err
 	}
 
-	if err := copyFilesToTempDir(ctx, tempdir, environmentName, ksfile); err != nil {
+	if err := copyFilesToTempDir(ctx, tempdir, environmentName); err != nil {
 		return nil, err
 	}
 
@@ -79,9 +79,9 @@ func makeTemporaryDotEnv(
 	archiveDotEnvPath := path.Join(cachepath, environmentName, ".env")
 	os.MkdirAll(cachepath, 0o700)
 
-	dotenv := new(envfile.EnvFile).
-		Load(ctx.CachedEnvironmentDotEnvPath(environmentName), nil)
-	if err := dotenv.Err(); err != nil {
+	// Values are resolved, so that inherited ones are sent as well
+	values := ctx.GetAllSecrets(environmentName)
+	if err := ctx.Err(); err != nil {
 		return err
 	}
 
@@ -93,9 +93,9 @@ func makeTemporaryDotEnv(
 
 	for _, v := range ksfile.Env {
 		key := v.Key
-		value, ok := dotenv.Get(key)
+		value, ok := values[key]
 
-		if v.Strict && ok {
+		if v.IsRequiredIn(environmentName) && ok {
 			archiveDotEnv.Set(key, value)
 		}
 	}
@@ -107,8 +107,10 @@ func makeTemporaryDotEnv(
 	return nil
 }
 
-func copyFilesToTempDir(ctx *core.Context, tempdir, environmentName string,
-	ksfile *keystonefile.KeystoneFile) error {
+func copyFilesToTempDir(
+	ctx *core.Context,
+	tempdir, environmentName string,
+) error {
 	filesdirpath := path.Join(
 		tempdir,
 		".keystone",
@@ -120,14 +122,11 @@ func copyFilesToTempDir(ctx *core.Context, tempdir, environmentName string,
 		return err
 	}
 
-	for _, f := range ksfile.Files {
+	for _, f := range ctx.ListExpandedFiles(environmentName) {
 		fp := f.Path
-		current := path.Join(
-			ctx.CachedEnvironmentFilesPath(environmentName),
-			fp,
-		)
+		current, _ := ctx.CachedFilePathForEnvironment(environmentName, fp)
 		if !utils.FileExists(current) {
-			if f.Strict {
+			if f.IsRequiredIn(environmentName) {
 				return errors.New("required file not found")
 			}
 			continue
@@ -135,7 +134,7 @@ func copyFilesToTempDir(ctx *core.Context, tempdir, environmentName string,
 
 		inArchive := path.Join(filesdirpath, fp)
 
-		if err := utils.CopyFile(current, inArchive); err != nil {
+		if err := ctx.CopyFromCache(current, inArchive); err != nil {
 			return err
 		}
 	}
@@ -1,14 +1,13 @@
 package ci
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
 	"errors"
 	"fmt"
 	"log"
 	"net/url"
-	"os"
-	"path"
 	"strings"
 
 	"github.com/google/go-github/v40/github"
@@ -530,7 +529,7 @@ func (g *gitHubCiService) sendEnvironmentSecrets() *gitHubCiService {
 
 	for _, secret := range secrets {
 		value, ok := secret.Values[core.EnvironmentName(g.environment)]
-		if !ok && secret.Required {
+		if !ok && secret.IsRequiredIn(g.environment) {
 			// TODO: have a better error handling
 			g.err = fmt.Errorf("missing required secret: %s", string(value))
 			break
@@ -555,23 +554,25 @@ func (g *gitHubCiService) sendEnvironmentFiles() *gitHubCiService {
 		return g
 	}
 
-	files := g.ctx.ListFiles()
+	files := g.ctx.ListExpandedFiles(g.environment)
 
 	g.sentFiles = make([]string, 0)
 
-	filecachepath := g.ctx.CachedEnvironmentFilesPath(g.environment)
 	for _, file := range files {
 		g.log.Printf("Sending file with path: %s\n", file.Path)
 
-		fullpath := path.Join(filecachepath, file.Path)
-		freader, err := os.Open(fullpath)
+		fullpath, _ := g.ctx.CachedFilePathForEnvironment(
+			g.environment,
+			file.Path,
+		)
+		content, err := g.ctx.ReadCachedFile(fullpath)
 		if err != nil {
-			g.log.Printf("Error opening %s\n", fullpath)
+			g.log.Printf("Error reading %s\n", fullpath)
 			g.err = err
 			break
 		}
 
-		contents, err := base64encode(freader)
+		contents, err := base64encode(bytes.NewReader(content))
 		if err != nil {
 			g.log.Printf("Error base64 encoding file %s\n", fullpath)
 			break
@@ -671,7 +672,7 @@ func (g *gitHubCiService) cleanFiles() *gitHubCiService {
 		return g
 	}
 
-	files := g.ctx.ListFiles()
+	files := g.ctx.ListExpandedFiles(g.environment)
 	for _, file := range files {
 		key := pathToVarname(file.Path)
 
@@ -1,10 +1,9 @@
 package ci
 
 import (
+	"bytes"
 	"fmt"
 	"log"
-	"os"
-	"path"
 	"strings"
 
 	"github.com/hashicorp/go-retryablehttp"
@@ -393,7 +392,7 @@ func (g *gitlabCiService) sendEnvironmentSecrets() *gitlabCiService {
 	for _, secret := range secrets {
 		key := secret.Name
 		value, ok := secret.Values[core.EnvironmentName(g.environment)]
-		if !ok && secret.Required {
+		if !ok && secret.IsRequiredIn(g.environment) {
 			g.err = fmt.Errorf("required secret is missing %s", key)
 			break
 		}
@@ -418,18 +417,20 @@ func (g *gitlabCiService) sendEnvironmentFiles() *gitlabCiService {
 		return g
 	}
 
-	files := g.ctx.ListFiles()
-	filecachepath := g.ctx.CachedEnvironmentFilesPath(g.environment)
+	files := g.ctx.ListExpandedFiles(g.environment)
 
 	for _, file := range files {
-		fullpath := path.Join(filecachepath, file.Path)
-		f, err := os.Open(fullpath)
+		fullpath, _ := g.ctx.CachedFilePathForEnvironment(
+			g.environment,
+			file.Path,
+		)
+		content, err := g.ctx.ReadCachedFile(fullpath)
 		if err != nil {
 			g.err = err
 			break
 		}
 
-		contents, err := base64encode(f)
+		contents, err := base64encode(bytes.NewReader(content))
 		if err != nil {
 			g.err = err
 			break
@@ -474,7 +475,7 @@ func (g *gitlabCiService) cleanFiles() *gitlabCiService {
 		return g
 	}
 
-	files := g.ctx.ListFiles()
+	files := g.ctx.ListExpandedFiles(g.environment)
 	for _, file := range files {
 		key := pathToVarname(file.Path)
 
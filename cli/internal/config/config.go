@@ -123,6 +123,10 @@ func GetUserPrivateKey() (privateKey []byte, err error) {
 		return []byte(pk), nil
 	}
 
+	if IsPrivateKeyProtected() {
+		return unlockPrivateKey(privateKey)
+	}
+
 	return privateKey, nil
 }
 
@@ -145,6 +149,8 @@ func GetUserPublicKey() (publicKey []byte, err error) {
 func SetUserPrivateKey(privateKey []byte) {
 	encodedKey := base64.StdEncoding.EncodeToString(privateKey)
 	viper.Set("private_key", encodedKey)
+	viper.Set("private_key_protected", false)
+	unlockedPrivateKey = nil
 }
 
 // SetUserPublicKey function sets the pulblic key for the currently logged in use
@@ -188,7 +194,18 @@ func GetDeviceUID() string {
 // RevokeDevice removes device information (including keys) from
 // the configuration file
 func RevokeDevice() error {
-	return unset("device", "device_uid", "private_key", "public_key")
+	if err := LockUserPrivateKey(); err != nil {
+		return err
+	}
+
+	return unset(
+		"device",
+		"device_uid",
+		"private_key",
+		"private_key_protected",
+		"previous_private_keys",
+		"public_key",
+	)
 }
 
 // GetServiceApiKey function returns the API Key for the named CI service
@@ -241,6 +258,7 @@ func InitConfig(cfgFile string) (err error) {
 	viper.SetDefault("accounts", defaultAccounts)
 
 	viper.SetDefault("device_uid", uuid.NewV4().String())
+	viper.SetDefault("key_cache_duration", DefaultKeyCacheDuration.String())
 
 	// If a config file is found, read it in.
 	if err := viper.ReadInConfig(); err == nil {
@@ -266,6 +284,9 @@ func Logout() {
 	SetCurrentAccount(-1)
 	SetAuthToken("")
 	Write()
+
+	// The unlocked key must not outlive the session
+	_ = LockUserPrivateKey()
 }
 
 func AddHook(command string) {
@@ -288,6 +309,43 @@ func RemoveHook() {
 	}
 }
 
+// AddProject function adds a project directory to the projects known
+// on this machine. It returns false if it was already known.
+// ! does not write to disk
+func AddProject(dir string) bool {
+	projects := GetProjects()
+
+	for _, project := range projects {
+		if project == dir {
+			return false
+		}
+	}
+
+	viper.Set("projects", append(projects, dir))
+
+	return true
+}
+
+// GetProjects function returns the directories of the projects
+// known on this machine
+func GetProjects() []string {
+	return viper.GetStringSlice("projects")
+}
+
+// RemoveProject function forgets a project directory
+// ! does not write to disk
+func RemoveProject(dir string) {
+	projects := make([]string, 0)
+
+	for _, project := range GetProjects() {
+		if project != dir {
+			projects = append(projects, project)
+		}
+	}
+
+	viper.Set("projects", projects)
+}
+
 // castAccount casts a map[interface{}]interface{}, which is returned by
 // viper, into a more manageable map[string]string
 func castAccount(
@@ -301,6 +359,36 @@ func castAccount(
 	}
 }
 
+// castStringMaps casts a list of maps, as returned by viper, into
+// a list of map[string]string
+func castStringMaps(raw interface{}) []map[string]string {
+	switch list := raw.(type) {
+	case []map[string]string:
+		return list
+
+	case []interface{}:
+		result := make([]map[string]string, 0, len(list))
+
+		for _, item := range deepCast(list).([]interface{}) {
+			m, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			entry := make(map[string]string)
+			for k, v := range m {
+				entry[k] = fmt.Sprint(v)
+			}
+
+			result = append(result, entry)
+		}
+
+		return result
+	}
+
+	return make([]map[string]string, 0)
+}
+
 // deepCasts an entire object (as returned by viper.AllValues(), for
 // instance) into an object where `map[interface{}]interface{}` have
 // been cast to `map[string]interface{}` (recursively, at that), so that
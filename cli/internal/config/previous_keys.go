@@ -0,0 +1,195 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"strconv"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/wearedevx/keystone/cli/internal/crypto"
+)
+
+// DefaultKeyGracePeriod is how long the previous private key of a device
+// is kept after a rotation, so that messages sent to it can still be read
+const DefaultKeyGracePeriod = 7 * 24 * time.Hour
+
+// previousPrivateKey is a private key the device had before a rotation
+type previousPrivateKey struct {
+	key       []byte
+	expiresAt time.Time
+}
+
+// previousKeysKey encrypts the previous private keys with a key derived
+// from the current one, so that they are as protected as it is
+func previousKeysKey(privateKey []byte) []byte {
+	sum := sha256.Sum256(
+		append([]byte("keystone previous keys\x00"), privateKey...),
+	)
+
+	return sum[:]
+}
+
+// RotateUserKeys function replaces the keys of the device with
+// `privateKey` and `publicKey`.
+// The current private key is kept for `gracePeriod`, it is returned.
+// ! does not write to disk
+func RotateUserKeys(
+	privateKey []byte,
+	publicKey []byte,
+	gracePeriod time.Duration,
+) (oldPrivateKey []byte, err error) {
+	oldPrivateKey, err = GetUserPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	previous := append(getPreviousPrivateKeys(oldPrivateKey), previousPrivateKey{
+		key:       oldPrivateKey,
+		expiresAt: time.Now().Add(gracePeriod),
+	})
+
+	// The keyring holds the old key, it must not be used anymore
+	if err = LockUserPrivateKey(); err != nil {
+		return nil, err
+	}
+
+	SetUserPrivateKey(privateKey)
+	SetUserPublicKey(publicKey)
+
+	if err = setPreviousPrivateKeys(privateKey, previous); err != nil {
+		return nil, err
+	}
+
+	return oldPrivateKey, nil
+}
+
+// GetPreviousPrivateKeys function returns the private keys the device had
+// before its last rotations, whose grace period has not ended yet.
+// The most recent comes first.
+func GetPreviousPrivateKeys() ([][]byte, error) {
+	privateKey, err := GetUserPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	previous := getPreviousPrivateKeys(privateKey)
+	keys := make([][]byte, 0, len(previous))
+	for i := len(previous) - 1; i >= 0; i-- {
+		keys = append(keys, previous[i].key)
+	}
+
+	return keys, nil
+}
+
+// getPreviousPrivateKeys returns the previous private keys that have not
+// expired, decrypted with `privateKey`
+func getPreviousPrivateKeys(privateKey []byte) []previousPrivateKey {
+	entries := castStringMaps(viper.Get("previous_private_keys"))
+	previous := make([]previousPrivateKey, 0, len(entries))
+	key := previousKeysKey(privateKey)
+	now := time.Now()
+
+	// Entries that cannot be read were left by another key of the device,
+	// before it was revoked or logged in again, and are of no use
+	for _, entry := range entries {
+		expiry, err := strconv.ParseInt(entry["expires_at"], 10, 64)
+		if err != nil || now.After(time.Unix(expiry, 0)) {
+			continue
+		}
+
+		encrypted, err := base64.StdEncoding.DecodeString(entry["key"])
+		if err != nil {
+			continue
+		}
+
+		decrypted, err := crypto.DecryptWithKey(key, encrypted)
+		if err != nil {
+			continue
+		}
+
+		previous = append(previous, previousPrivateKey{
+			key:       decrypted,
+			expiresAt: time.Unix(expiry, 0),
+		})
+	}
+
+	return previous
+}
+
+// setPreviousPrivateKeys stores `previous`, encrypted with `privateKey`
+func setPreviousPrivateKeys(
+	privateKey []byte,
+	previous []previousPrivateKey,
+) error {
+	entries := make([]map[string]string, 0, len(previous))
+	key := previousKeysKey(privateKey)
+
+	for _, p := range previous {
+		encrypted, err := crypto.EncryptWithKey(key, p.key)
+		if err != nil {
+			return err
+		}
+
+		entries = append(entries, map[string]string{
+			"key":        base64.StdEncoding.EncodeToString(encrypted),
+			"expires_at": strconv.FormatInt(p.expiresAt.Unix(), 10),
+		})
+	}
+
+	viper.Set("previous_private_keys", entries)
+
+	return nil
+}
+
+// keySettings are the settings holding the keys of the device
+var keySettings = []string{
+	"private_key",
+	"private_key_protected",
+	"public_key",
+	"previous_private_keys",
+}
+
+// UserKeys is a copy of the keys of the device, as they are stored
+type UserKeys map[string]interface{}
+
+// SaveUserKeys function returns a copy of the keys of the device, so
+// that they can be restored if a rotation cannot be completed
+func SaveUserKeys() UserKeys {
+	keys := make(UserKeys)
+
+	for _, setting := range keySettings {
+		keys[setting] = viper.Get(setting)
+	}
+
+	return keys
+}
+
+// RestoreUserKeys function puts back the keys copied by SaveUserKeys,
+// `privateKey` being the private key they hold.
+// The server may have stored the public key of `rotatedPrivateKey` before
+// the rotation failed, so that key is kept for `gracePeriod`, like
+// previous keys are.
+// ! does not write to disk
+func RestoreUserKeys(
+	keys UserKeys,
+	privateKey []byte,
+	rotatedPrivateKey []byte,
+	gracePeriod time.Duration,
+) error {
+	for setting, value := range keys {
+		viper.Set(setting, value)
+	}
+
+	previous := append(getPreviousPrivateKeys(privateKey), previousPrivateKey{
+		key:       rotatedPrivateKey,
+		expiresAt: time.Now().Add(gracePeriod),
+	})
+
+	if err := setPreviousPrivateKeys(privateKey, previous); err != nil {
+		return err
+	}
+
+	// The keyring may hold the key that is being replaced
+	return LockUserPrivateKey()
+}
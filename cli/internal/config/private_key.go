@@ -0,0 +1,136 @@
+package config
+
+import (
+	"encoding/base64"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/wearedevx/keystone/cli/internal/crypto"
+	"github.com/wearedevx/keystone/cli/internal/keyring"
+)
+
+// KeyPassphraseEnvVar is the environment variable holding the passphrase
+// of a protected private key, for non-interactive use
+const KeyPassphraseEnvVar = "KS_KEY_PASSPHRASE"
+
+// DefaultKeyCacheDuration is how long an unlocked private key is kept
+// in the keyring, unless configured otherwise
+const DefaultKeyCacheDuration = 15 * time.Minute
+
+var (
+	ErrorPassphraseRequired = errors.New(
+		"the private key is protected by a passphrase",
+	)
+	ErrorWrongPassphrase = errors.New("wrong passphrase")
+	ErrorKeyProtected    = errors.New(
+		"the private key is already protected by a passphrase",
+	)
+)
+
+// AskPassphrase is called to get the passphrase of a protected private
+// key when it is not set in the environment.
+// It is nil when prompting the user is not possible.
+var AskPassphrase func() (string, error)
+
+// unlockedPrivateKey keeps the private key once unlocked, so that the
+// passphrase is asked for at most once per run
+var unlockedPrivateKey []byte
+
+// IsPrivateKeyProtected function returns true if the private key of the
+// device is sealed with a passphrase
+func IsPrivateKeyProtected() bool {
+	return viper.GetBool("private_key_protected")
+}
+
+// ProtectUserPrivateKey function seals the private key of the device
+// with `passphrase`.
+// ! does not write to disk
+func ProtectUserPrivateKey(passphrase string) error {
+	if IsPrivateKeyProtected() {
+		return ErrorKeyProtected
+	}
+
+	privateKey, err := GetUserPrivateKey()
+	if err != nil {
+		return err
+	}
+
+	sealed, err := crypto.EncryptWithPassphrase(passphrase, privateKey)
+	if err != nil {
+		return err
+	}
+
+	viper.Set("private_key", base64.StdEncoding.EncodeToString(sealed))
+	viper.Set("private_key_protected", true)
+
+	return nil
+}
+
+// GetKeyCacheDuration function returns how long an unlocked private key
+// is kept in the keyring
+func GetKeyCacheDuration() time.Duration {
+	return viper.GetDuration("key_cache_duration")
+}
+
+// SetKeyCacheDuration function sets how long an unlocked private key
+// is kept in the keyring. Zero disables the keyring.
+// ! does not write to disk
+func SetKeyCacheDuration(duration time.Duration) {
+	viper.Set("key_cache_duration", duration.String())
+}
+
+// LockUserPrivateKey function forgets the unlocked private key,
+// so that the passphrase is asked for again
+func LockUserPrivateKey() error {
+	unlockedPrivateKey = nil
+
+	return deviceKeyring().Clear()
+}
+
+func deviceKeyring() *keyring.Keyring {
+	return keyring.New(keyring.Dir(), GetDeviceUID())
+}
+
+// unlockPrivateKey returns the private key sealed in `sealed`, using the
+// keyring, the passphrase from the environment, or asking for it
+func unlockPrivateKey(sealed []byte) ([]byte, error) {
+	if unlockedPrivateKey != nil {
+		return unlockedPrivateKey, nil
+	}
+
+	ring := deviceKeyring()
+	if privateKey, ok := ring.Load(); ok {
+		unlockedPrivateKey = privateKey
+		return privateKey, nil
+	}
+
+	passphrase := os.Getenv(KeyPassphraseEnvVar)
+	fromEnvironment := passphrase != ""
+	if !fromEnvironment {
+		if AskPassphrase == nil {
+			return nil, ErrorPassphraseRequired
+		}
+
+		var err error
+		if passphrase, err = AskPassphrase(); err != nil {
+			return nil, err
+		}
+	}
+
+	privateKey, err := crypto.DecryptWithPassphrase(passphrase, sealed)
+	if err != nil {
+		return nil, ErrorWrongPassphrase
+	}
+
+	unlockedPrivateKey = privateKey
+
+	// Automation provides the passphrase every time, there is no need
+	// to leave the key behind
+	if !fromEnvironment {
+		_ = ring.Store(privateKey, GetKeyCacheDuration())
+	}
+
+	return privateKey, nil
+}
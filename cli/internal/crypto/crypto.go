@@ -65,6 +65,55 @@ func DecryptMessage(
 	return p, nil
 }
 
+// EncryptWithKey function encrypts data with a symmetric key,
+// using a themis secure cell in seal mode
+func EncryptWithKey(key []byte, data []byte) (encrypted []byte, err error) {
+	scell, err := cell.SealWithKey(&keys.SymmetricKey{Value: key})
+	if err != nil {
+		return nil, err
+	}
+
+	return scell.Encrypt(data, nil)
+}
+
+// DecryptWithKey function decrypts data encrypted with EncryptWithKey
+func DecryptWithKey(key []byte, encrypted []byte) (data []byte, err error) {
+	scell, err := cell.SealWithKey(&keys.SymmetricKey{Value: key})
+	if err != nil {
+		return nil, err
+	}
+
+	return scell.Decrypt(encrypted, nil)
+}
+
+// EncryptWithPassphrase function encrypts data with a user-provided
+// passphrase, using a themis secure cell in seal mode
+func EncryptWithPassphrase(
+	passphrase string,
+	data []byte,
+) (encrypted []byte, err error) {
+	scell, err := cell.SealWithPassphrase(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	return scell.Encrypt(data, nil)
+}
+
+// DecryptWithPassphrase function decrypts data encrypted with
+// EncryptWithPassphrase
+func DecryptWithPassphrase(
+	passphrase string,
+	encrypted []byte,
+) (data []byte, err error) {
+	scell, err := cell.SealWithPassphrase(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	return scell.Decrypt(encrypted, nil)
+}
+
 // Encrypts a file using a user-provided passphrase.
 // `filepath` is the path to the file to be encrypted, and
 // `passphrase` is the user-provided passphrase.
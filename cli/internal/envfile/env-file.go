@@ -1,6 +1,7 @@
 package envfile
 
 import (
+	"encoding/base64"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -21,11 +22,19 @@ type EnvFile struct {
 	opts LoadOptions
 }
 
+// Cipher encrypts and decrypts the contents of an encrypted .env file
+type Cipher interface {
+	Encrypt(data []byte) ([]byte, error)
+	Decrypt(data []byte) ([]byte, error)
+}
+
 // Options for loading the .env file
 type LoadOptions struct {
 	// Don’t unescape double quotes and other special characters on read.
 	// If loaded with this option, dumping will panic.
 	DontUnescapeChars bool
+	// When set, the file is decrypted on read and encrypted on write
+	Cipher Cipher
 }
 
 // DefaultLoadOptions function returns LoadOptions with default values
@@ -117,16 +126,33 @@ func (f *EnvFile) Dump() *EnvFile {
 	var sb strings.Builder
 
 	for key, value := range f.data {
-		trimed := strings.Trim(value, " \n\r\t")
-		// prepared := overEscape(trimed)
-		escaped := doubleQuoteEscape(trimed)
+		// Values are written as is: leading and trailing whitespace, and
+		// line breaks are part of them
+		if utils.IsBinary(value) {
+			sb.WriteString(fmt.Sprintf(
+				"%s=%s%s\"\n",
+				key,
+				base64Prefix,
+				base64.StdEncoding.EncodeToString([]byte(value)),
+			))
+			continue
+		}
 
-		sb.WriteString(fmt.Sprintf("%s=\"%s\"\n", key, escaped))
+		sb.WriteString(fmt.Sprintf("%s=\"%s\"\n", key, doubleQuoteEscape(value)))
 	}
 
-	contents := sb.String()
+	contents := []byte(sb.String())
 
-	if err := ioutil.WriteFile(f.path, []byte(contents), 0o600); err != nil {
+	if f.opts.Cipher != nil {
+		encrypted, err := f.opts.Cipher.Encrypt(contents)
+		if err != nil {
+			f.err = fmt.Errorf("failed to encrypt `%s` (%w)", f.path, err)
+			return f
+		}
+		contents = encrypted
+	}
+
+	if err := ioutil.WriteFile(f.path, contents, 0o600); err != nil {
 		f.err = fmt.Errorf("failed to write `%s` (%w)", f.path, err)
 	}
 
@@ -246,5 +272,19 @@ func readFile(
 	}
 	defer utils.Close(file)
 
-	return Parse(file, opts)
+	if opts.Cipher == nil {
+		return Parse(file, opts)
+	}
+
+	encrypted, err := ioutil.ReadAll(file)
+	if err != nil {
+		return
+	}
+
+	data, err := opts.Cipher.Decrypt(encrypted)
+	if err != nil {
+		return
+	}
+
+	return UnmarshalBytes(data, opts)
 }
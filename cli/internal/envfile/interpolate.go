@@ -0,0 +1,204 @@
+package envfile
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var variableNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// CycleError is returned by `Expand` when variables reference each other
+// in a loop
+type CycleError struct {
+	// Names of the variables in the loop, the first one being repeated
+	// at the end
+	Cycle []string
+}
+
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("reference cycle: %s", strings.Join(e.Cycle, " -> "))
+}
+
+// HasReferences function tells if `value` references another variable
+func HasReferences(value string) bool {
+	return strings.Contains(value, "${")
+}
+
+// Expand function resolves the references to other variables in `values`.
+// Supported forms are `${VAR}`, `${VAR:-default}` (default is used when
+// VAR is unset or empty), and `$$` for a literal `$`.
+// Unknown variables expand to an empty string.
+// The values of the `literals` variables are kept as they are, they can
+// still be referenced by other variables.
+// The input map is left untouched.
+func Expand(
+	values map[string]string,
+	literals ...string,
+) (map[string]string, error) {
+	e := &expander{
+		values:   values,
+		expanded: make(map[string]string),
+		visiting: make(map[string]bool),
+		stack:    make([]string, 0),
+	}
+
+	for _, name := range literals {
+		if value, ok := values[name]; ok {
+			e.expanded[name] = value
+		}
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+
+	// Always report the same cycle first
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, err := e.resolve(name); err != nil {
+			return nil, err
+		}
+	}
+
+	return e.expanded, nil
+}
+
+type expander struct {
+	values   map[string]string
+	expanded map[string]string
+	visiting map[string]bool
+	stack    []string
+}
+
+// resolve returns the expanded value of the variable `name`
+func (e *expander) resolve(name string) (string, error) {
+	if value, ok := e.expanded[name]; ok {
+		return value, nil
+	}
+
+	raw, ok := e.values[name]
+	if !ok {
+		return "", nil
+	}
+
+	if e.visiting[name] {
+		start := 0
+		for index, visited := range e.stack {
+			if visited == name {
+				start = index
+				break
+			}
+		}
+
+		cycle := append([]string{}, e.stack[start:]...)
+		cycle = append(cycle, name)
+
+		return "", &CycleError{Cycle: cycle}
+	}
+
+	e.visiting[name] = true
+	e.stack = append(e.stack, name)
+
+	value, err := e.interpolate(raw)
+
+	e.stack = e.stack[:len(e.stack)-1]
+	delete(e.visiting, name)
+
+	if err != nil {
+		return "", err
+	}
+
+	e.expanded[name] = value
+
+	return value, nil
+}
+
+// interpolate replaces the references found in `s` with their values
+func (e *expander) interpolate(s string) (string, error) {
+	var sb strings.Builder
+
+	for i := 0; i < len(s); {
+		if s[i] != '$' || i+1 == len(s) {
+			sb.WriteByte(s[i])
+			i++
+			continue
+		}
+
+		switch s[i+1] {
+		case '$':
+			sb.WriteByte('$')
+			i += 2
+
+		case '{':
+			end := closingBrace(s, i+1)
+			if end == -1 {
+				// Unterminated reference, keep it as is
+				sb.WriteString(s[i:])
+				i = len(s)
+				continue
+			}
+
+			reference := s[i+2 : end]
+			name := reference
+			defaultValue := ""
+			hasDefault := false
+
+			if index := strings.Index(reference, ":-"); index != -1 {
+				name = reference[:index]
+				defaultValue = reference[index+2:]
+				hasDefault = true
+			}
+
+			if !variableNameRegex.MatchString(name) {
+				// Not a reference, keep it as is
+				sb.WriteString(s[i : end+1])
+				i = end + 1
+				continue
+			}
+
+			value, err := e.resolve(name)
+			if err != nil {
+				return "", err
+			}
+
+			if value == "" && hasDefault {
+				if value, err = e.interpolate(defaultValue); err != nil {
+					return "", err
+				}
+			}
+
+			sb.WriteString(value)
+			i = end + 1
+
+		default:
+			sb.WriteByte(s[i])
+			i++
+		}
+	}
+
+	return sb.String(), nil
+}
+
+// closingBrace returns the index of the brace closing the one at `open`,
+// taking nested braces into account, or -1
+func closingBrace(s string, open int) int {
+	depth := 0
+
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
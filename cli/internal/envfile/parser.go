@@ -2,6 +2,7 @@ package envfile
 
 import (
 	"bytes"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"regexp"
@@ -15,6 +16,10 @@ const (
 	prefixDoubleQuote = '"'
 
 	exportPrefix = "export"
+
+	// base64Prefix marks binary values, which are written in base64
+	// between double quotes: KEY=base64:"AAEC..."
+	base64Prefix = "base64:\""
 )
 
 var (
@@ -122,6 +127,10 @@ loop:
 
 // extractVarValue extracts variable value and returns rest of slice
 func extractVarValue(src []byte, vars map[string]string, opts LoadOptions) (value string, rest []byte, err error) {
+	if bytes.HasPrefix(src, []byte(base64Prefix)) {
+		return extractBase64Value(src[len(base64Prefix)-1:])
+	}
+
 	quote, hasPrefix := hasQuotePrefix(src)
 	if !hasPrefix {
 		// unquoted value - read until whitespace
@@ -134,19 +143,9 @@ func extractVarValue(src []byte, vars map[string]string, opts LoadOptions) (valu
 	}
 
 	// lookup quoted string terminator
-	for i := 1; i < len(src); i++ {
-		if char := src[i]; char != quote {
-			continue
-		}
-
-		// skip escaped quote symbol (\" or \', depends on quote)
-		if prevChar := src[i-1]; prevChar == '\\' {
-			continue
-		}
-
-		// trim quotes
-		trimFunc := isCharFunc(rune(quote))
-		value = string(bytes.TrimLeftFunc(bytes.TrimRightFunc(src[0:i], trimFunc), trimFunc))
+	if i := indexOfClosingQuote(src, quote); i != -1 {
+		// files edited on Windows have CRLF line breaks in multi-line values
+		value = strings.ReplaceAll(string(src[1:i]), "\r\n", "\n")
 		if quote == prefixDoubleQuote {
 			// unescape newlines for double quote (this is compat feature)
 			// and expand environment variables
@@ -161,13 +160,56 @@ func extractVarValue(src []byte, vars map[string]string, opts LoadOptions) (valu
 		return value, src[i+1:], nil
 	}
 
-	// return formatted error if quoted string is not terminated
+	return "", nil, unterminatedValueError(src)
+}
+
+// extractBase64Value decodes a binary value, `src` starts at the
+// opening double quote
+func extractBase64Value(src []byte) (value string, rest []byte, err error) {
+	i := indexOfClosingQuote(src, prefixDoubleQuote)
+	if i == -1 {
+		return "", nil, unterminatedValueError(src)
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(string(src[1:i]))
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid base64 value %s (%w)", src[:i+1], err)
+	}
+
+	return string(decoded), src[i+1:], nil
+}
+
+// indexOfClosingQuote returns the position of the quote that terminates
+// the value starting with `quote`, or -1 if there is none.
+// A quote is escaped when preceded by an odd number of backslashes.
+func indexOfClosingQuote(src []byte, quote byte) int {
+	for i := 1; i < len(src); i++ {
+		if src[i] != quote {
+			continue
+		}
+
+		backslashes := 0
+		for j := i - 1; j > 0 && src[j] == '\\'; j-- {
+			backslashes++
+		}
+
+		if backslashes%2 == 0 {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// unterminatedValueError returns formatted error if quoted string is not
+// terminated
+func unterminatedValueError(src []byte) error {
 	valEndIndex := bytes.IndexFunc(src, isCharFunc('\n'))
 	if valEndIndex == -1 {
 		valEndIndex = len(src)
 	}
 
-	return "", nil, fmt.Errorf("unterminated quoted value %s", src[:valEndIndex])
+	return fmt.Errorf("unterminated quoted value %s", src[:valEndIndex])
 }
 
 func expandEscapes(str string) string {
@@ -0,0 +1,141 @@
+package envfile
+
+import (
+	"io/ioutil"
+	"path"
+	"reflect"
+	"testing"
+)
+
+func TestUnmarshalBytes(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want map[string]string
+	}{
+		{
+			name: "unquoted",
+			src:  "PORT=3000\nexport HOST=localhost\n",
+			want: map[string]string{"PORT": "3000", "HOST": "localhost"},
+		},
+		{
+			name: "embedded quotes",
+			src:  `QUOTED="\"hello\"" SINGLE='it"s' MIXED="say \"hi\" to 'them'"`,
+			want: map[string]string{
+				"QUOTED": `"hello"`,
+				"SINGLE": `it"s`,
+				"MIXED":  `say "hi" to 'them'`,
+			},
+		},
+		{
+			name: "trailing backslash",
+			src:  "PATH_SEP=\"C:\\\\\"\nNEXT=\"value\"\n",
+			want: map[string]string{"PATH_SEP": `C:\`, "NEXT": "value"},
+		},
+		{
+			name: "escaped line breaks",
+			src:  `PEM="-----BEGIN KEY-----\nabc\r\n-----END KEY-----\n"`,
+			want: map[string]string{
+				"PEM": "-----BEGIN KEY-----\nabc\r\n-----END KEY-----\n",
+			},
+		},
+		{
+			name: "raw multi-line value",
+			src:  "PEM=\"-----BEGIN KEY-----\nabc\n-----END KEY-----\"\nNEXT=1\n",
+			want: map[string]string{
+				"PEM":  "-----BEGIN KEY-----\nabc\n-----END KEY-----",
+				"NEXT": "1",
+			},
+		},
+		{
+			name: "CRLF",
+			src:  "# comment\r\nA=1\r\nB=\"two\"\r\nC=\"multi\r\nline\"\r\n",
+			want: map[string]string{"A": "1", "B": "two", "C": "multi\nline"},
+		},
+		{
+			name: "whitespace is kept in quotes",
+			src:  `PADDED="  value  "`,
+			want: map[string]string{"PADDED": "  value  "},
+		},
+		{
+			name: "base64",
+			src:  `BINARY=base64:"AP/+a2V5"`,
+			want: map[string]string{"BINARY": "\x00\xff\xfekey"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UnmarshalBytes([]byte(tt.src), DefaultLoadOptions())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestUnmarshalBytesErrors(t *testing.T) {
+	for _, src := range []string{
+		`UNTERMINATED="value`,
+		`ESCAPED_END="value\"`,
+		`BINARY=base64:"AP/+`,
+		`BINARY=base64:"not base64"`,
+	} {
+		if _, err := UnmarshalBytes([]byte(src), DefaultLoadOptions()); err == nil {
+			t.Errorf("parsing %q should fail", src)
+		}
+	}
+}
+
+func TestDumpRoundTrip(t *testing.T) {
+	data := map[string]string{
+		"PEM":       "-----BEGIN KEY-----\nabc\n-----END KEY-----\n",
+		"CRLF":      "line one\r\nline two",
+		"QUOTES":    `"quoted" and 'single'`,
+		"BACKSLASH": `ends with \`,
+		"JSON":      `{"key": "a\nb \"c\""}`,
+		"SHELL":     "$HOME `pwd` !",
+		"PADDED":    "  spaces and tabs\t",
+		"BINARY":    "\x00\xff\xfe\x01key",
+		"EMPTY":     "",
+	}
+
+	p := path.Join(t.TempDir(), ".env")
+
+	if err := new(EnvFile).Load(p, nil).SetData(data).Dump().Err(); err != nil {
+		t.Fatalf("Dump: %v", err)
+	}
+
+	got := new(EnvFile).Load(p, nil)
+	if err := got.Err(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if !reflect.DeepEqual(got.GetData(), data) {
+		t.Errorf("expected %q, got %q", data, got.GetData())
+	}
+}
+
+func TestDumpMarksBinaryValues(t *testing.T) {
+	p := path.Join(t.TempDir(), ".env")
+
+	f := new(EnvFile).Load(p, nil).
+		SetData(map[string]string{"BINARY": "\x00\x01"}).
+		Dump()
+	if err := f.Err(); err != nil {
+		t.Fatalf("Dump: %v", err)
+	}
+
+	contents, err := ioutil.ReadFile(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "BINARY=base64:\"AAE=\"\n"; string(contents) != want {
+		t.Errorf("expected %q, got %q", want, contents)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"log"
 	"strings"
 
+	"github.com/wearedevx/keystone/api/pkg/apierrors"
 	"github.com/wearedevx/keystone/api/pkg/models"
 	"github.com/wearedevx/keystone/cli/internal/config"
 	kserrors "github.com/wearedevx/keystone/cli/internal/errors"
@@ -26,6 +27,9 @@ type environmentService struct {
 type EnvironmentService interface {
 	Err() *kserrors.Error
 	GetAccessibleEnvironments() []models.Environment
+	AddEnvironment(name string, environmentType string) models.Environment
+	RenameEnvironment(name string, newName string) models.Environment
+	RemoveEnvironment(name string)
 }
 
 // NewEnvironmentService function return an instance of EnvironmentService
@@ -84,3 +88,128 @@ func (s *environmentService) GetAccessibleEnvironments() []models.Environment {
 
 	return accessibleEnvironments
 }
+
+// AddEnvironment method creates a new environment in the project.
+// The environment gets the access rights of `environmentType`.
+func (s *environmentService) AddEnvironment(
+	name string,
+	environmentType string,
+) models.Environment {
+	if s.err != nil {
+		return models.Environment{}
+	}
+
+	sp := spinner.Spinner("")
+	sp.Start()
+
+	environment, err := s.client.Project(s.ctx.GetProjectID()).
+		AddEnvironment(name, environmentType)
+	sp.Stop()
+
+	if err != nil {
+		// The only resource that may not be found is the environment type
+		if strings.Contains(err.Error(), "not found") {
+			s.err = kserrors.BadEnvironmentType(environmentType, nil)
+		} else {
+			s.handleError(name, err)
+		}
+	}
+
+	return environment
+}
+
+// RenameEnvironment method changes the name of a user defined environment
+func (s *environmentService) RenameEnvironment(
+	name string,
+	newName string,
+) models.Environment {
+	environmentID := s.accessibleEnvironmentID(name)
+	if s.err != nil {
+		return models.Environment{}
+	}
+
+	sp := spinner.Spinner("")
+	sp.Start()
+
+	environment, err := s.client.Project(s.ctx.GetProjectID()).
+		RenameEnvironment(environmentID, newName)
+	sp.Stop()
+
+	if err != nil {
+		if strings.Contains(err.Error(), apierrors.ErrorBadEnvironmentName.Error()) ||
+			strings.Contains(err.Error(), apierrors.ErrorEnvironmentAlreadyExists.Error()) {
+			s.handleError(newName, err)
+		} else {
+			s.handleError(name, err)
+		}
+	}
+
+	return environment
+}
+
+// RemoveEnvironment method deletes a user defined environment
+func (s *environmentService) RemoveEnvironment(name string) {
+	environmentID := s.accessibleEnvironmentID(name)
+	if s.err != nil {
+		return
+	}
+
+	sp := spinner.Spinner("")
+	sp.Start()
+
+	err := s.client.Project(s.ctx.GetProjectID()).
+		RemoveEnvironment(environmentID)
+	sp.Stop()
+
+	if err != nil {
+		s.handleError(name, err)
+	}
+}
+
+// accessibleEnvironmentID returns the ID of the environment named `name`,
+// among the ones the user has access to
+func (s *environmentService) accessibleEnvironmentID(name string) string {
+	if s.err != nil {
+		return ""
+	}
+
+	names := make([]string, 0, len(s.ctx.AccessibleEnvironments))
+
+	for _, environment := range s.ctx.AccessibleEnvironments {
+		if environment.Name == name {
+			return environment.EnvironmentID
+		}
+
+		names = append(names, environment.Name)
+	}
+
+	s.err = kserrors.EnvironmentDoesntExist(name, strings.Join(names, ", "), nil)
+
+	return ""
+}
+
+// handleError turns errors returned by the API when managing environments
+// into keystone errors
+func (s *environmentService) handleError(name string, err error) {
+	message := err.Error()
+
+	switch {
+	case errors.Is(err, auth.ErrorUnauthorized):
+		config.Logout()
+		s.err = kserrors.InvalidConnectionToken(err)
+	case errors.Is(err, auth.ErrorServiceNotAvailable):
+		s.err = kserrors.ServiceNotAvailable(err)
+	case strings.Contains(message, "not found"):
+		s.err = kserrors.EnvironmentDoesntExist(name, "", err)
+	case strings.Contains(message, apierrors.ErrorPermissionDenied.Error()):
+		s.err = kserrors.PermissionDenied(name, err)
+	case strings.Contains(message, apierrors.ErrorBadEnvironmentName.Error()):
+		s.err = kserrors.BadEnvironmentName(name, nil)
+	case strings.Contains(message, apierrors.ErrorEnvironmentAlreadyExists.Error()):
+		s.err = kserrors.EnvironmentAlreadyExists(name, nil)
+	case strings.Contains(message, apierrors.ErrorDefaultEnvironmentCannotChange.Error()):
+		s.err = kserrors.DefaultEnvironmentCannotChange(name, nil)
+	default:
+		s.err = kserrors.UnkownError(err)
+	}
+}
@@ -170,6 +170,60 @@ func (file *EnvironmentsFile) GetByName(environmentName string) *Env {
 	return nil
 }
 
+// GetByID method returns the environment with the id `environmentID`
+// from the environmentfile, or nil if theres no such environment
+func (file *EnvironmentsFile) GetByID(environmentID string) *Env {
+	if file.Err() != nil {
+		return nil
+	}
+	for _, env := range file.Environments {
+		if env.EnvironmentID == environmentID {
+			return &env
+		}
+	}
+	return nil
+}
+
+// Rename method changes the name of an environment, following it
+// if it is the current one
+func (file *EnvironmentsFile) Rename(environmentName string, newName string) *EnvironmentsFile {
+	if file.Err() != nil {
+		return file
+	}
+
+	for i, env := range file.Environments {
+		if env.Name == environmentName {
+			file.Environments[i].Name = newName
+		}
+	}
+
+	if file.Current == environmentName {
+		file.Current = newName
+	}
+
+	return file
+}
+
+// RemoveEnvironment method removes an environment from the
+// environments file
+func (file *EnvironmentsFile) RemoveEnvironment(environmentName string) *EnvironmentsFile {
+	if file.Err() != nil {
+		return file
+	}
+
+	environments := make([]Env, 0)
+
+	for _, env := range file.Environments {
+		if env.Name != environmentName {
+			environments = append(environments, env)
+		}
+	}
+
+	file.Environments = environments
+
+	return file
+}
+
 // Replaces an environment in the environment file with updated data
 // If the environment does not exist in the environment file,
 // it should be appended to it
@@ -499,6 +499,259 @@ To edit the service, try:
 	"CouldNotSendToCIService": `
 {{ ERROR }} {{ .Name | red }}
 
+This happened because: {{ .Cause }}
+`,
+	"CannotRunCommand": `
+{{ ERROR }} {{ .Name | red }} {{ .Command | red }}
+
+This happened because: {{ .Cause }}
+`,
+	"UnsupportedFormat": `
+{{ ERROR }} {{ .Name | red }} {{ .Format | red }}
+Available formats are: {{ .Available }}
+`,
+	"CannotSerializeSecrets": `
+{{ ERROR }} {{ .Name | red }}
+Secrets could not be written in the {{ .Format }} format.
+
+This happened because: {{ .Cause }}
+`,
+	"InvalidSecretValue": `
+{{ ERROR }} {{ .Name | red }} {{- ": '" | red }} {{- .Secret | red }} {{- "'" | red }}
+The value for '{{ .Secret }}' in the '{{ .Environment }}' environment does not satisfy the rules declared in keystone.yaml.
+
+This happened because: {{ .Cause }}
+`,
+	"InvalidValidationRule": `
+{{ ERROR }} {{ .Name | red }} {{- ": '" | red }} {{- .Secret | red }} {{- "'" | red }}
+The rules declared for '{{ .Secret }}' in keystone.yaml are malformed.
+
+This happened because: {{ .Cause }}
+
+You may fix them by editing keystone.yaml.
+`,
+	"SecretReferenceCycle": `
+{{ ERROR }} {{ .Name | red }}
+Some secrets of the '{{ .Environment }}' environment reference each other in a loop, so their values cannot be resolved.
+
+This happened because: {{ .Cause }}
+
+You may break the loop by changing one of the values with:
+  $ ks --env {{ .Environment }} secret set <SECRET_NAME> <SECRET_VALUE>
+`,
+	"EnvironmentAlreadyExists": `
+{{ ERROR }} {{ .Name | red }} {{- ": '" | red }} {{- .Environment | red }} {{- "'" | red }}
+The project already has an environment named '{{ .Environment }}'.
+
+To list the environments:
+  $ ks env
+`,
+	"BadEnvironmentName": `
+{{ ERROR }} {{ .Name | red }} {{- ": '" | red }} {{- .Environment | red }} {{- "'" | red }}
+Environment names may only contain lowercase letters, digits, dashes and underscores,
+and must start with a letter or a digit.
+`,
+	"DefaultEnvironmentCannotChange": `
+{{ ERROR }} {{ .Name | red }} {{- ": '" | red }} {{- .Environment | red }} {{- "'" | red }}
+The dev, staging and prod environments cannot be renamed or removed.
+Only environments added with "ks env add" can.
+`,
+	"BadEnvironmentType": `
+{{ ERROR }} {{ .Name | red }} {{- ": '" | red }} {{- .EnvironmentType | red }} {{- "'" | red }}
+Valid environment types are: dev, staging and prod.
+An environment gets the access rights of its type.
+`,
+	"EnvironmentInheritanceCycle": `
+{{ ERROR }} {{ .Name | red }} {{- ": '" | red }} {{- .Environment | red }} {{- "'" | red }}
+The environments declared in keystone.yaml inherit from each other in a loop,
+so the values of '{{ .Environment }}' cannot be resolved.
+
+This happened because: {{ .Cause }}
+
+You may fix it by editing the 'inherits' settings in keystone.yaml.
+`,
+	"UnknownParentEnvironment": `
+{{ ERROR }} {{ .Name | red }} {{- ": '" | red }} {{- .Environment | red }} {{- "'" | red }}
+An environment declared in keystone.yaml inherits from an environment
+that does not exist, or that you cannot access,
+so the values of '{{ .Environment }}' cannot be resolved.
+
+This happened because: {{ .Cause }}
+
+You may fix it by editing the 'inherits' settings in keystone.yaml.
+`,
+	"EnvironmentIsInherited": `
+{{ ERROR }} {{ .Name | red }} {{- ": '" | red }} {{- .Environment | red }} {{- "'" | red }}
+The following environments inherit from '{{ .Environment }}': {{ .Children }}
+
+Change their 'inherits' setting in keystone.yaml before removing it.
+`,
+	"FailedToReadHistory": `
+{{ ERROR }} {{ .Name | red }} {{- ": '" | red }} {{- .Path | red }} {{- "'" | red }}
+The history of the environment could not be read.
+
+This happened because: {{ .Cause }}
+
+It may have been written with another account or another key.
+`,
+	"FailedToWriteHistory": `
+{{ ERROR }} {{ .Name | red }} {{- ": '" | red }} {{- .Path | red }} {{- "'" | red }}
+The change could not be recorded in the history of the environment.
+
+This happened because: {{ .Cause }}
+`,
+	"HistoryEntryNotFound": `
+{{ ERROR }} {{ .Name | red }} {{- ": '" | red }} {{- .SecretName | red }} {{- "'" | red }}
+There is no entry number {{ .Entry }} in the history of the secret.
+
+Use "ks secret history {{ .SecretName }}" to list the available entries.
+`,
+	"MustChooseProject": `
+{{ ERROR }} {{ .Name | red }}
+The workspace at {{ .Path }} holds several projects: {{ .Projects }}.
+
+Run the command from the directory of a project, or choose one with:
+  $ ks --project <path> <command>
+`,
+	"ProjectNotFound": `
+{{ ERROR }} {{ .Name | red }} {{- ": '" | red }} {{- .Path | red }} {{- "'" | red }}
+There is no keystone.yaml in that directory, relative to the current
+directory or to the root of the workspace.
+`,
+	"NoWorkspace": `
+{{ ERROR }} {{ .Name | red }}
+Neither the current directory ({{ .Path }}), nor any of its parent,
+have a keystone.workspace.yaml file.
+
+To list the projects of your repository, run from its root:
+  $ ks workspace add <project path>
+`,
+	"FailedToReadWorkspaceFile": `
+{{ ERROR }} {{ .Name | red }} {{- ": '" | red }} {{- .Path | red }} {{- "'" | red }}
+This happened because: {{ .Cause }}
+`,
+	"FailedToUpdateWorkspaceFile": `
+{{ ERROR }} {{ .Name | red }} {{- ": '" | red }} {{- .Path | red }} {{- "'" | red }}
+This happened because: {{ .Cause }}
+`,
+	"WorkspaceSecretConflict": `
+{{ ERROR }} {{ .Name | red }} {{- ": '" | red }} {{- .SecretName | red }} {{- "'" | red }}
+Several projects of the workspace define that secret, once prefixed.
+
+Give the projects different prefixes in keystone.workspace.yaml:
+  $ ks workspace add <project path> --prefix <PREFIX_>
+`,
+	"CannotWriteFile": `
+{{ ERROR }} {{ .Name | red }} {{- ": '" | red }} {{- .Path | red }} {{- "'" | red }}
+This happened because: {{ .Cause }}
+`,
+	"CannotStartWatch": `
+{{ ERROR }} {{ .Name | red }}
+ks watch could not be started in the background.
+
+This happened because: {{ .Cause }}
+
+You can still run it with:
+  $ ks watch --foreground
+`,
+	"CannotStartAgent": `
+{{ ERROR }} {{ .Name | red }} {{- ": '" | red }} {{- .Path | red }} {{- "'" | red }}
+The agent could not listen on that socket.
+
+This happened because: {{ .Cause }}
+`,
+	"CannotGetSecretsFromAgent": `
+{{ ERROR }} {{ .Name | red }} {{- ": '" | red }} {{- .Path | red }} {{- "'" | red }}
+The agent listening on that socket could not serve the secrets.
+
+This happened because: {{ .Cause }}
+
+Fix the problem, or stop the agent to read the secrets from the cache.
+`,
+	"FailedToReadCache": `
+{{ ERROR }} {{ .Name | red }} {{- ": '" | red }} {{- .Path | red }} {{- "'" | red }}
+The cache of the project could not be decrypted.
+
+This happened because: {{ .Cause }}
+
+It is encrypted with your private key. If you restored a backup made
+on another device, ask a member to send you the environments again with ks env send.
+`,
+	"FailedToEncryptCache": `
+{{ ERROR }} {{ .Name | red }} {{- ": '" | red }} {{- .Path | red }} {{- "'" | red }}
+The cache of the project could not be encrypted.
+
+This happened because: {{ .Cause }}
+`,
+	"CannotUnlockPrivateKey": `
+{{ ERROR }} {{ .Name | red }}
+Your private key is protected by a passphrase, and could not be unlocked.
+
+This happened because: {{ .Cause }}
+
+In scripts, set the passphrase in the KS_KEY_PASSPHRASE environment variable.
+`,
+	"CannotProtectPrivateKey": `
+{{ ERROR }} {{ .Name | red }}
+Your private key could not be protected by a passphrase.
+
+This happened because: {{ .Cause }}
+`,
+	"CannotRotateKey": `
+{{ ERROR }} {{ .Name | red }}
+The keys of this device could not be replaced.
+
+This happened because: {{ .Cause }}
+`,
+	"CannotReadSecretValue": `
+{{ ERROR }} {{ .Name | red }}
+The value could not be read from {{ .Source }}.
+
+This happened because: {{ .Cause }}
+`,
+	"TooManySecretValues": `
+{{ ERROR }} {{ .Name | red }}
+The value can be given as an argument, with --from-file, or with --stdin,
+but only one of them at a time.
+`,
+	"MissingSecretValue": `
+{{ ERROR }} {{ .Name | red }}
+Give the value as an argument, with --from-file, or with --stdin.
+`,
+	"MissingTemplateValue": `
+{{ ERROR }} {{ .Name | red }}
+The template {{ .Template }} uses {{ .Value }}, which has no value in this environment.
+
+Set it with:
+  $ ks secret set {{ .Value }} <value>
+or, for a file:
+  $ ks file set {{ .Value }}
+`,
+	"CannotRenderTemplate": `
+{{ ERROR }} {{ .Name | red }}
+The template {{ .Template }} could not be rendered.
+
+This happened because: {{ .Cause }}
+`,
+	"CannotDiffFile": `
+{{ ERROR }} {{ .Name | red }} {{- ": '" | red }} {{- .Path | red }} {{- "'" | red }}
+You tried to see the local changes to '{{ .Path }}', but it is not a secret file.
+Secret files are listed by:
+  $ ks file
+
+This happened because: {{ .Cause }}
+`,
+	"CannotScanFiles": `
+{{ ERROR }} {{ .Name | red }}
+The files could not be searched for secrets.
+
+This happened because: {{ .Cause }}
+`,
+	"CannotInstallGitHook": `
+{{ ERROR }} {{ .Name | red }}
+The git {{ .Hook }} hook could not be installed.
+Make sure the project is in a git repository.
+
 This happened because: {{ .Cause }}
 `,
 }
@@ -1060,3 +1313,275 @@ func CouldNotSendToCIService(cause error) *Error {
 
 	return NewError("Could Not Send to CI Service", helpTexts["CouldNotSendToCIService"], meta, cause)
 }
+
+func CannotRunCommand(command string, cause error) *Error {
+	meta := map[string]interface{}{
+		"Command": string(command),
+	}
+	return NewError("Cannot Run Command", helpTexts["CannotRunCommand"], meta, cause)
+}
+
+func UnsupportedFormat(format string, available string, cause error) *Error {
+	meta := map[string]interface{}{
+		"Format":    string(format),
+		"Available": string(available),
+	}
+	return NewError("Unsupported Format", helpTexts["UnsupportedFormat"], meta, cause)
+}
+
+func CannotSerializeSecrets(format string, cause error) *Error {
+	meta := map[string]interface{}{
+		"Format": string(format),
+	}
+	return NewError("Cannot Serialize Secrets", helpTexts["CannotSerializeSecrets"], meta, cause)
+}
+
+func InvalidSecretValue(secret string, environment string, cause error) *Error {
+	meta := map[string]interface{}{
+		"Secret":      string(secret),
+		"Environment": string(environment),
+	}
+	return NewError("Invalid Secret Value", helpTexts["InvalidSecretValue"], meta, cause)
+}
+
+func InvalidValidationRule(secret string, cause error) *Error {
+	meta := map[string]interface{}{
+		"Secret": string(secret),
+	}
+	return NewError("Invalid Validation Rule", helpTexts["InvalidValidationRule"], meta, cause)
+}
+
+func SecretReferenceCycle(environment string, cause error) *Error {
+	meta := map[string]interface{}{
+		"Environment": string(environment),
+	}
+	return NewError("Secret Reference Cycle", helpTexts["SecretReferenceCycle"], meta, cause)
+}
+
+func EnvironmentAlreadyExists(environment string, cause error) *Error {
+	meta := map[string]interface{}{
+		"Environment": string(environment),
+	}
+	return NewError("Environment Already Exists", helpTexts["EnvironmentAlreadyExists"], meta, cause)
+}
+
+func BadEnvironmentName(environment string, cause error) *Error {
+	meta := map[string]interface{}{
+		"Environment": string(environment),
+	}
+	return NewError("Bad Environment Name", helpTexts["BadEnvironmentName"], meta, cause)
+}
+
+func DefaultEnvironmentCannotChange(environment string, cause error) *Error {
+	meta := map[string]interface{}{
+		"Environment": string(environment),
+	}
+	return NewError("Default Environment Cannot Change", helpTexts["DefaultEnvironmentCannotChange"], meta, cause)
+}
+
+func BadEnvironmentType(environmentType string, cause error) *Error {
+	meta := map[string]interface{}{
+		"EnvironmentType": string(environmentType),
+	}
+	return NewError("Bad Environment Type", helpTexts["BadEnvironmentType"], meta, cause)
+}
+
+func EnvironmentInheritanceCycle(environment string, cause error) *Error {
+	meta := map[string]interface{}{
+		"Environment": string(environment),
+	}
+	return NewError("Environment Inheritance Cycle", helpTexts["EnvironmentInheritanceCycle"], meta, cause)
+}
+
+func UnknownParentEnvironment(environment string, cause error) *Error {
+	meta := map[string]interface{}{
+		"Environment": string(environment),
+	}
+	return NewError("Unknown Parent Environment", helpTexts["UnknownParentEnvironment"], meta, cause)
+}
+
+func EnvironmentIsInherited(environment string, children string, cause error) *Error {
+	meta := map[string]interface{}{
+		"Environment": string(environment),
+		"Children":    string(children),
+	}
+	return NewError("Environment Is Inherited", helpTexts["EnvironmentIsInherited"], meta, cause)
+}
+
+func FailedToReadHistory(path string, cause error) *Error {
+	meta := map[string]interface{}{
+		"Path": string(path),
+	}
+	return NewError("Failed To Read History", helpTexts["FailedToReadHistory"], meta, cause)
+}
+
+func FailedToWriteHistory(path string, cause error) *Error {
+	meta := map[string]interface{}{
+		"Path": string(path),
+	}
+	return NewError("Failed To Write History", helpTexts["FailedToWriteHistory"], meta, cause)
+}
+
+func HistoryEntryNotFound(secretName string, entry int, cause error) *Error {
+	meta := map[string]interface{}{
+		"SecretName": string(secretName),
+		"Entry":      int(entry),
+	}
+	return NewError("History Entry Not Found", helpTexts["HistoryEntryNotFound"], meta, cause)
+}
+
+func MustChooseProject(path string, projects string, cause error) *Error {
+	meta := map[string]interface{}{
+		"Path":     string(path),
+		"Projects": string(projects),
+	}
+	return NewError("Must Choose Project", helpTexts["MustChooseProject"], meta, cause)
+}
+
+func ProjectNotFound(path string, cause error) *Error {
+	meta := map[string]interface{}{
+		"Path": string(path),
+	}
+	return NewError("Project Not Found", helpTexts["ProjectNotFound"], meta, cause)
+}
+
+func NoWorkspace(path string, cause error) *Error {
+	meta := map[string]interface{}{
+		"Path": string(path),
+	}
+	return NewError("No Workspace", helpTexts["NoWorkspace"], meta, cause)
+}
+
+func FailedToReadWorkspaceFile(path string, cause error) *Error {
+	meta := map[string]interface{}{
+		"Path": string(path),
+	}
+	return NewError("Failed To Read Workspace File", helpTexts["FailedToReadWorkspaceFile"], meta, cause)
+}
+
+func FailedToUpdateWorkspaceFile(path string, cause error) *Error {
+	meta := map[string]interface{}{
+		"Path": string(path),
+	}
+	return NewError("Failed To Update Workspace File", helpTexts["FailedToUpdateWorkspaceFile"], meta, cause)
+}
+
+func WorkspaceSecretConflict(secretName string, cause error) *Error {
+	meta := map[string]interface{}{
+		"SecretName": string(secretName),
+	}
+	return NewError("Workspace Secret Conflict", helpTexts["WorkspaceSecretConflict"], meta, cause)
+}
+
+func CannotWriteFile(path string, cause error) *Error {
+	meta := map[string]interface{}{
+		"Path": string(path),
+	}
+	return NewError("Cannot Write File", helpTexts["CannotWriteFile"], meta, cause)
+}
+
+func CannotStartWatch(cause error) *Error {
+	meta := map[string]interface{}{}
+
+	return NewError("Cannot Start Watch", helpTexts["CannotStartWatch"], meta, cause)
+}
+
+func CannotStartAgent(path string, cause error) *Error {
+	meta := map[string]interface{}{
+		"Path": string(path),
+	}
+	return NewError("Cannot Start Agent", helpTexts["CannotStartAgent"], meta, cause)
+}
+
+func CannotGetSecretsFromAgent(path string, cause error) *Error {
+	meta := map[string]interface{}{
+		"Path": string(path),
+	}
+	return NewError("Cannot Get Secrets From Agent", helpTexts["CannotGetSecretsFromAgent"], meta, cause)
+}
+
+func FailedToReadCache(path string, cause error) *Error {
+	meta := map[string]interface{}{
+		"Path": string(path),
+	}
+	return NewError("Failed To Read Cache", helpTexts["FailedToReadCache"], meta, cause)
+}
+
+func FailedToEncryptCache(path string, cause error) *Error {
+	meta := map[string]interface{}{
+		"Path": string(path),
+	}
+	return NewError("Failed To Encrypt Cache", helpTexts["FailedToEncryptCache"], meta, cause)
+}
+
+func CannotUnlockPrivateKey(cause error) *Error {
+	meta := map[string]interface{}{}
+
+	return NewError("Cannot Unlock Private Key", helpTexts["CannotUnlockPrivateKey"], meta, cause)
+}
+
+func CannotProtectPrivateKey(cause error) *Error {
+	meta := map[string]interface{}{}
+
+	return NewError("Cannot Protect Private Key", helpTexts["CannotProtectPrivateKey"], meta, cause)
+}
+
+func CannotRotateKey(cause error) *Error {
+	meta := map[string]interface{}{}
+
+	return NewError("Cannot Rotate Key", helpTexts["CannotRotateKey"], meta, cause)
+}
+
+func CannotReadSecretValue(source string, cause error) *Error {
+	meta := map[string]interface{}{
+		"Source": string(source),
+	}
+	return NewError("Cannot Read Secret Value", helpTexts["CannotReadSecretValue"], meta, cause)
+}
+
+func TooManySecretValues(cause error) *Error {
+	meta := map[string]interface{}{}
+
+	return NewError("Too Many Secret Values", helpTexts["TooManySecretValues"], meta, cause)
+}
+
+func MissingSecretValue(cause error) *Error {
+	meta := map[string]interface{}{}
+
+	return NewError("Missing Secret Value", helpTexts["MissingSecretValue"], meta, cause)
+}
+
+func MissingTemplateValue(template string, value string, cause error) *Error {
+	meta := map[string]interface{}{
+		"Template": string(template),
+		"Value":    string(value),
+	}
+	return NewError("Missing Template Value", helpTexts["MissingTemplateValue"], meta, cause)
+}
+
+func CannotRenderTemplate(template string, cause error) *Error {
+	meta := map[string]interface{}{
+		"Template": string(template),
+	}
+	return NewError("Cannot Render Template", helpTexts["CannotRenderTemplate"], meta, cause)
+}
+
+func CannotDiffFile(path string, cause error) *Error {
+	meta := map[string]interface{}{
+		"Path": string(path),
+	}
+	return NewError("Cannot Diff File", helpTexts["CannotDiffFile"], meta, cause)
+}
+
+func CannotScanFiles(cause error) *Error {
+	meta := map[string]interface{}{}
+
+	return NewError("Cannot Scan Files", helpTexts["CannotScanFiles"], meta, cause)
+}
+
+func CannotInstallGitHook(hook string, cause error) *Error {
+	meta := map[string]interface{}{
+		"Hook": string(hook),
+	}
+	return NewError("Cannot Install Git Hook", helpTexts["CannotInstallGitHook"], meta, cause)
+}
@@ -0,0 +1,141 @@
+// Package generator produces random values for secrets
+package generator
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+// Charset determines what a generated value looks like
+type Charset string
+
+const (
+	Hex          Charset = "hex"
+	Base64       Charset = "base64"
+	Alphanumeric Charset = "alphanumeric"
+	URLSafe      Charset = "url-safe"
+	UUID         Charset = "uuid"
+	Words        Charset = "words"
+)
+
+// Charsets lists the supported charsets
+var Charsets = []Charset{Hex, Base64, Alphanumeric, URLSafe, UUID, Words}
+
+const (
+	// DefaultLength is the default length for every charset but `Words`
+	DefaultLength = 32
+	// DefaultWordCount is the default length for the `Words` charset
+	DefaultWordCount = 6
+)
+
+const (
+	alphanumericChars = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+	urlSafeChars      = alphanumericChars + "-_"
+)
+
+// IsValid method returns true if the charset is supported
+func (c Charset) IsValid() bool {
+	for _, charset := range Charsets {
+		if c == charset {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Generate function returns a random value, using a cryptographically
+// secure source.
+// `length` is the number of random bytes for `Hex` and `Base64`,
+// the number of characters for `Alphanumeric` and `URLSafe`,
+// and the number of words for `Words`. It is ignored for `UUID`.
+func Generate(charset Charset, length int) (string, error) {
+	if charset != UUID && length <= 0 {
+		return "", fmt.Errorf("length must be positive, got %d", length)
+	}
+
+	switch charset {
+	case Hex:
+		b, err := randomBytes(length)
+		return hex.EncodeToString(b), err
+
+	case Base64:
+		b, err := randomBytes(length)
+		return base64.StdEncoding.EncodeToString(b), err
+
+	case Alphanumeric:
+		return randomString(alphanumericChars, length)
+
+	case URLSafe:
+		return randomString(urlSafeChars, length)
+
+	case UUID:
+		return uuid.NewV4().String(), nil
+
+	case Words:
+		return randomWords(length)
+
+	default:
+		return "", fmt.Errorf("unsupported charset `%s`", charset)
+	}
+}
+
+// randomBytes returns `n` random bytes
+func randomBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// randomIndex returns a uniformly distributed random number in [0, max)
+func randomIndex(max int) (int, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(max)))
+	if err != nil {
+		return 0, err
+	}
+
+	return int(n.Int64()), nil
+}
+
+// randomString returns `length` characters picked at random in `chars`
+func randomString(chars string, length int) (string, error) {
+	var sb strings.Builder
+
+	for i := 0; i < length; i++ {
+		index, err := randomIndex(len(chars))
+		if err != nil {
+			return "", err
+		}
+
+		sb.WriteByte(chars[index])
+	}
+
+	return sb.String(), nil
+}
+
+// randomWords returns `count` words picked at random in the word list,
+// separated by dashes
+func randomWords(count int) (string, error) {
+	words := make([]string, count)
+
+	for i := range words {
+		index, err := randomIndex(len(wordList))
+		if err != nil {
+			return "", err
+		}
+
+		words[i] = wordList[index]
+	}
+
+	return strings.Join(words, "-"), nil
+}
@@ -0,0 +1,77 @@
+package generator
+
+import (
+	"encoding/base64"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	cases := []struct {
+		charset Charset
+		length  int
+		pattern string
+	}{
+		{Hex, 16, `^[0-9a-f]{32}$`},
+		{Alphanumeric, 20, `^[A-Za-z0-9]{20}$`},
+		{URLSafe, 40, `^[A-Za-z0-9_-]{40}$`},
+		{UUID, 0, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`},
+		{Words, 4, `^[a-z]+(-[a-z]+){3}$`},
+	}
+
+	for _, c := range cases {
+		t.Run(string(c.charset), func(t *testing.T) {
+			value, err := Generate(c.charset, c.length)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !regexp.MustCompile(c.pattern).MatchString(value) {
+				t.Errorf("%q does not match %s", value, c.pattern)
+			}
+		})
+	}
+
+	t.Run("base64", func(t *testing.T) {
+		value, err := Generate(Base64, 32)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		decoded, err := base64.StdEncoding.DecodeString(value)
+		if err != nil || len(decoded) != 32 {
+			t.Errorf("expected 32 bytes of base64, got %q", value)
+		}
+	})
+
+	t.Run("values differ", func(t *testing.T) {
+		a, _ := Generate(Alphanumeric, DefaultLength)
+		b, _ := Generate(Alphanumeric, DefaultLength)
+
+		if a == b {
+			t.Errorf("expected two different values, got %q twice", a)
+		}
+	})
+
+	t.Run("invalid length", func(t *testing.T) {
+		if _, err := Generate(Hex, 0); err == nil {
+			t.Error("expected an error for a zero length")
+		}
+	})
+}
+
+func TestWordListIsUnique(t *testing.T) {
+	seen := make(map[string]bool)
+
+	for _, word := range wordList {
+		if seen[word] || strings.Contains(word, "-") {
+			t.Errorf("invalid or duplicate word %q", word)
+		}
+		seen[word] = true
+	}
+
+	if len(wordList) != 512 {
+		t.Errorf("expected 512 words, got %d", len(wordList))
+	}
+}
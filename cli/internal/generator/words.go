@@ -0,0 +1,70 @@
+package generator
+
+// wordList is used to generate passphrases.
+// It holds 512 words, so each word adds 9 bits of entropy.
+var wordList = []string{
+	"able", "acid", "acorn", "actor", "adapt", "admit", "adult", "agent",
+	"agree", "ahead", "aisle", "alarm", "album", "alert", "alien", "alley",
+	"allow", "alpha", "amber", "ample", "angle", "ankle", "apple", "apron",
+	"arena", "argue", "armor", "arrow", "aside", "asset", "atlas", "attic",
+	"audio", "avoid", "awake", "award", "bacon", "badge", "bagel", "baker",
+	"balmy", "banjo", "barge", "basil", "basin", "batch", "beach", "beard",
+	"beast", "began", "being", "bench", "berry", "bike", "birch", "bison",
+	"black", "blade", "blank", "blaze", "blend", "bliss", "block", "bloom",
+	"blues", "blunt", "board", "boast", "bonus", "boost", "booth", "boxer",
+	"brain", "brave", "bread", "brick", "bride", "brief", "brisk", "broad",
+	"brook", "brush", "buddy", "bugle", "build", "bunch", "cabin", "cable",
+	"cacao", "camel", "canal", "candy", "canoe", "canon", "cargo", "carol",
+	"carry", "catch", "cedar", "chain", "chalk", "charm", "chart", "chase",
+	"cheek", "chess", "chief", "chili", "chirp", "choir", "chord", "cider",
+	"cinema", "civic", "clamp", "clash", "clerk", "cliff", "climb", "cloak",
+	"clock", "cloud", "clove", "coach", "coast", "cobra", "cocoa", "comet",
+	"coral", "couch", "cover", "crane", "crate", "cream", "crisp", "crown",
+	"crumb", "crust", "cubic", "curve", "cycle", "daily", "dairy", "daisy",
+	"dance", "decoy", "delta", "denim", "depot", "diary", "digit", "diner",
+	"disco", "ditch", "diver", "dizzy", "dodge", "donor", "dough", "draft",
+	"drama", "dream", "dress", "drift", "drone", "drum", "dusty", "eagle",
+	"early", "earth", "easel", "eight", "elbow", "elder", "ember", "empty",
+	"enjoy", "entry", "equal", "error", "essay", "event", "exact", "exile",
+	"extra", "fable", "facet", "fairy", "faith", "fancy", "feast", "fence",
+	"ferry", "fever", "fiber", "field", "fifty", "final", "flame", "flask",
+	"fleet", "flint", "float", "flock", "flour", "fluid", "flute", "focus",
+	"foggy", "forge", "forty", "forum", "fossil", "frame", "fresh", "frost",
+	"fruit", "fudge", "fungi", "gala", "galaxy", "gamma", "garden", "gecko",
+	"ghost", "giant", "ginger", "given", "glade", "glass", "globe", "glove",
+	"glyph", "goose", "grape", "graph", "grass", "gravy", "great", "green",
+	"grill", "group", "guard", "guest", "guide", "habit", "hammer", "happy",
+	"harbor", "harp", "hatch", "haven", "hazel", "heart", "hedge", "hello",
+	"helmet", "heron", "hinge", "hobby", "honey", "hornet", "hotel", "house",
+	"humor", "husky", "icing", "igloo", "image", "index", "inlet", "input",
+	"ivory", "jacket", "jelly", "jewel", "joint", "jolly", "judge", "juice",
+	"jumbo", "kayak", "kettle", "khaki", "kiosk", "kite", "knack", "koala",
+	"label", "ladder", "lagoon", "lake", "lamp", "lapel", "laser", "latch",
+	"lemon", "level", "lilac", "linen", "lobby", "lodge", "logic", "lotus",
+	"lucky", "lunar", "lunch", "lyric", "magic", "major", "mango", "manor",
+	"maple", "march", "marsh", "medal", "melon", "mercy", "merit", "metal",
+	"meter", "mimic", "minor", "mirth", "mocha", "model", "molar", "month",
+	"moose", "motel", "motor", "mural", "music", "nacho", "navy", "nectar",
+	"needle", "noble", "noise", "north", "novel", "nudge", "nylon", "oasis",
+	"ocean", "olive", "omega", "onion", "opera", "orbit", "order", "otter",
+	"ounce", "owner", "oxide", "paddle", "panda", "panel", "paper", "parade",
+	"patio", "peach", "pearl", "pedal", "penny", "pepper", "piano", "pilot",
+	"pixel", "pizza", "plaid", "plane", "plaza", "plume", "polar", "pony",
+	"poppy", "porch", "prism", "proud", "pulse", "punch", "puppy", "quail",
+	"quart", "queen", "quest", "quiet", "quilt", "quota", "radar", "radio",
+	"rally", "ranch", "raven", "razor", "realm", "relay", "remix", "ridge",
+	"rifle", "river", "roast", "robin", "rocket", "rodeo", "rover", "royal",
+	"ruby", "rugby", "ruler", "rumba", "saddle", "salad", "salsa", "sandy",
+	"satin", "sauce", "scale", "scarf", "scout", "sedan", "seven", "shade",
+	"shark", "shelf", "shell", "shine", "shore", "silk", "siren", "skate",
+	"sketch", "slate", "sleet", "slope", "smile", "snack", "solar", "sonic",
+	"spark", "spice", "spoon", "spray", "squid", "stack", "stage", "stamp",
+	"steam", "stone", "storm", "stove", "straw", "sugar", "sunny", "surf",
+	"swamp", "swan", "sweet", "swift", "table", "tango", "tapir", "teapot",
+	"tempo", "tent", "thorn", "thumb", "tiger", "timber", "toast", "token",
+	"topaz", "torch", "tower", "trail", "train", "tread", "trend", "tribe",
+	"trout", "truck", "tulip", "tunnel", "turtle", "tweed", "twist", "ultra",
+	"uncle", "union", "unity", "urban", "usher", "valid", "valley", "vapor",
+	"vault", "velvet", "venue", "verse", "vigor", "villa", "vinyl", "violet",
+	"viper", "visor", "vital", "vivid", "vocal", "vodka", "voice", "wafer",
+}
@@ -0,0 +1,116 @@
+// Package githelper reads the index and the configuration of the git
+// repository a project belongs to, using the git command
+package githelper
+
+import (
+	"bytes"
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// IsRepository function returns true if `dir` is in a git working tree
+func IsRepository(dir string) bool {
+	out, err := run(dir, "rev-parse", "--is-inside-work-tree")
+
+	return err == nil && strings.TrimSpace(string(out)) == "true"
+}
+
+// ListFiles function returns the files under `paths` a commit could
+// include: the tracked ones, and the untracked ones that are not ignored.
+// Paths are relative to `dir`.
+func ListFiles(dir string, paths []string) ([]string, error) {
+	args := []string{"ls-files", "-z", "--cached", "--others", "--exclude-standard", "--"}
+
+	out, err := run(dir, append(args, paths...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return splitNull(out), nil
+}
+
+// StagedFiles function returns the files under `paths` that are added,
+// copied, modified or renamed in the index.
+// Paths are relative to `dir`.
+func StagedFiles(dir string, paths []string) ([]string, error) {
+	args := []string{
+		"diff", "--cached", "--name-only", "-z",
+		"--diff-filter=ACMR", "--relative", "--",
+	}
+
+	out, err := run(dir, append(args, paths...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return splitNull(out), nil
+}
+
+// StagedFileContents function returns the content of the file `filePath`,
+// relative to `dir`, as it is in the index
+func StagedFileContents(dir string, filePath string) ([]byte, error) {
+	return run(dir, "cat-file", "blob", ":./"+filepath.ToSlash(filePath))
+}
+
+// HooksPath function returns the absolute path to the directory git looks
+// for hooks in, for the repository `dir` is in
+func HooksPath(dir string) (string, error) {
+	out, err := run(dir, "rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return "", err
+	}
+
+	hooksPath := strings.TrimSpace(string(out))
+	if !filepath.IsAbs(hooksPath) {
+		hooksPath = filepath.Join(dir, hooksPath)
+	}
+
+	return hooksPath, nil
+}
+
+// Prefix function returns the path of `dir` relative to the root of the
+// working tree, with a trailing slash, or an empty string at the root
+func Prefix(dir string) (string, error) {
+	out, err := run(dir, "rev-parse", "--show-prefix")
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
+// run executes git with `args` in `dir`, and returns its output.
+// Errors hold what git wrote on stderr.
+func run(dir string, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return nil, errors.New(message)
+		}
+
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// splitNull splits the NUL separated output of git
+func splitNull(out []byte) []string {
+	paths := make([]string, 0)
+
+	for _, p := range strings.Split(string(out), "\x00") {
+		if p != "" {
+			paths = append(paths, p)
+		}
+	}
+
+	return paths
+}
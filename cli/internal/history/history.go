@@ -0,0 +1,293 @@
+// Package history keeps an append-only, encrypted journal of the changes
+// made to the secrets of an environment.
+package history
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/wearedevx/keystone/api/pkg/models"
+	"github.com/wearedevx/keystone/cli/internal/utils"
+)
+
+// EntryType tells what kind of change an entry is about
+type EntryType string
+
+const (
+	// EntryInitial is the value a secret had before its first
+	// recorded change
+	EntryInitial EntryType = "initial"
+	EntryAdd     EntryType = "add"
+	EntryChange  EntryType = "change"
+	EntryDelete  EntryType = "delete"
+)
+
+// Entry struct is a single change of a secret value
+type Entry struct {
+	Secret string    `json:"secret"`
+	Value  string    `json:"value"`
+	Type   EntryType `json:"type"`
+	Sender string    `json:"sender"`
+	Date   time.Time `json:"date"`
+}
+
+// entryJSON is how an entry is written in the journal.
+// Binary values are encoded, JSON strings cannot hold them.
+type entryJSON struct {
+	Secret   string    `json:"secret"`
+	Value    string    `json:"value"`
+	Encoding string    `json:"encoding,omitempty"`
+	Type     EntryType `json:"type"`
+	Sender   string    `json:"sender"`
+	Date     time.Time `json:"date"`
+}
+
+func (e Entry) MarshalJSON() ([]byte, error) {
+	value, encoding := models.EncodeSecretValue(e.Value)
+
+	return json.Marshal(entryJSON{
+		Secret:   e.Secret,
+		Value:    value,
+		Encoding: encoding,
+		Type:     e.Type,
+		Sender:   e.Sender,
+		Date:     e.Date,
+	})
+}
+
+func (e *Entry) UnmarshalJSON(data []byte) (err error) {
+	var raw entryJSON
+
+	if err = json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	e.Secret = raw.Secret
+	e.Type = raw.Type
+	e.Sender = raw.Sender
+	e.Date = raw.Date
+	e.Value, err = models.DecodeSecretValue(raw.Value, raw.Encoding)
+
+	return err
+}
+
+// Cipher interface encrypts and decrypts the entries of a journal
+type Cipher interface {
+	Encrypt(data []byte) ([]byte, error)
+	Decrypt(data []byte) ([]byte, error)
+}
+
+// A Journal represents the history file of an environment.
+// Each line of the file is an encrypted entry, and lines are only
+// ever appended to it.
+type Journal struct {
+	// err is set everytime an error occurs while working with the journal
+	err     error
+	path    string
+	cipher  Cipher
+	entries []Entry
+}
+
+// New function returns a journal stored at `path`,
+// whose entries are encrypted with `cipher`
+func New(path string, cipher Cipher) *Journal {
+	return &Journal{
+		path:    path,
+		cipher:  cipher,
+		entries: make([]Entry, 0),
+	}
+}
+
+// Load method reads and decrypts all the entries of the journal.
+// A journal that does not exist yet has no entries.
+func (j *Journal) Load() *Journal {
+	if j.err != nil {
+		return j
+	}
+
+	j.entries = make([]Entry, 0)
+
+	if !utils.FileExists(j.path) {
+		return j
+	}
+
+	/* #nosec
+	 * Caller must ensure that path is within ctx.Wd
+	 */
+	file, err := os.Open(j.path)
+	if err != nil {
+		return j.setError("failed to open %s (%w)", j.path, err)
+	}
+	defer utils.Close(file)
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		entry, err := j.decode(line)
+		if err != nil {
+			return j.setError(
+				"failed to read entry %d of %s (%w)",
+				lineNumber,
+				j.path,
+				err,
+			)
+		}
+
+		j.entries = append(j.entries, entry)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return j.setError("failed to read %s (%w)", j.path, err)
+	}
+
+	return j
+}
+
+// Append method encrypts the entries and adds them at the end of the journal
+func (j *Journal) Append(entries ...Entry) *Journal {
+	if j.err != nil || len(entries) == 0 {
+		return j
+	}
+
+	var sb strings.Builder
+
+	for _, entry := range entries {
+		line, err := j.encode(entry)
+		if err != nil {
+			return j.setError("failed to encrypt entry (%w)", err)
+		}
+
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+
+	/* #nosec
+	 * Caller must ensure that path is within ctx.Wd
+	 */
+	file, err := os.OpenFile(
+		j.path,
+		os.O_CREATE|os.O_APPEND|os.O_WRONLY,
+		0o600,
+	)
+	if err != nil {
+		return j.setError("failed to open %s (%w)", j.path, err)
+	}
+	defer utils.Close(file)
+
+	if _, err = file.WriteString(sb.String()); err != nil {
+		return j.setError("failed to write %s (%w)", j.path, err)
+	}
+
+	j.entries = append(j.entries, entries...)
+
+	return j
+}
+
+// Rekey method encrypts every entry of the journal again with `cipher`,
+// which is used from then on.
+// The journal is rewritten as a whole, and replaced once complete.
+func (j *Journal) Rekey(cipher Cipher) *Journal {
+	if j.Load().Err() != nil {
+		return j
+	}
+
+	j.cipher = cipher
+
+	if !utils.FileExists(j.path) {
+		return j
+	}
+
+	var sb strings.Builder
+
+	for _, entry := range j.entries {
+		line, err := j.encode(entry)
+		if err != nil {
+			return j.setError("failed to encrypt entry (%w)", err)
+		}
+
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+
+	tmpPath := j.path + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, []byte(sb.String()), 0o600); err != nil {
+		return j.setError("failed to write %s (%w)", tmpPath, err)
+	}
+
+	if err := os.Rename(tmpPath, j.path); err != nil {
+		return j.setError("failed to replace %s (%w)", j.path, err)
+	}
+
+	return j
+}
+
+// Entries method returns every entry of the journal, oldest first
+func (j *Journal) Entries() []Entry {
+	return j.entries
+}
+
+// For method returns the entries about `secretName`, oldest first
+func (j *Journal) For(secretName string) []Entry {
+	result := make([]Entry, 0)
+
+	for _, entry := range j.entries {
+		if entry.Secret == secretName {
+			result = append(result, entry)
+		}
+	}
+
+	return result
+}
+
+// Err method returns the last error that occurred
+func (j *Journal) Err() error {
+	return j.err
+}
+
+func (j *Journal) setError(format string, a ...interface{}) *Journal {
+	j.err = fmt.Errorf(format, a...)
+
+	return j
+}
+
+func (j *Journal) encode(entry Entry) (string, error) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return "", err
+	}
+
+	encrypted, err := j.cipher.Encrypt(data)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(encrypted), nil
+}
+
+func (j *Journal) decode(line string) (entry Entry, err error) {
+	encrypted, err := base64.StdEncoding.DecodeString(line)
+	if err != nil {
+		return entry, err
+	}
+
+	data, err := j.cipher.Decrypt(encrypted)
+	if err != nil {
+		return entry, err
+	}
+
+	err = json.Unmarshal(data, &entry)
+
+	return entry, err
+}
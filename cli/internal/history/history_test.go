@@ -0,0 +1,147 @@
+package history
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+)
+
+// xorCipher is a reversible stand-in for the real cipher
+type xorCipher byte
+
+func (c xorCipher) Encrypt(data []byte) ([]byte, error) {
+	out := make([]byte, len(data))
+	for i, b := range data {
+		out[i] = b ^ byte(c)
+	}
+	return out, nil
+}
+
+func (c xorCipher) Decrypt(data []byte) ([]byte, error) {
+	return c.Encrypt(data)
+}
+
+type failingCipher struct{}
+
+func (failingCipher) Encrypt(data []byte) ([]byte, error) { return data, nil }
+
+func (failingCipher) Decrypt([]byte) ([]byte, error) {
+	return nil, errors.New("bad key")
+}
+
+func TestAppendAndLoad(t *testing.T) {
+	journalPath := path.Join(t.TempDir(), "history")
+	date := time.Date(2021, 10, 4, 12, 0, 0, 0, time.UTC)
+
+	first := Entry{Secret: "PORT", Value: "3000", Type: EntryAdd, Sender: "alice", Date: date}
+	second := Entry{Secret: "KEY", Value: "\x00\xff\xfe\nkey", Type: EntryAdd, Sender: "alice", Date: date}
+	third := Entry{Secret: "PORT", Value: "4000", Type: EntryChange, Sender: "bob", Date: date.Add(time.Hour)}
+
+	if err := New(journalPath, xorCipher(42)).Append(first, second).Err(); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	if err := New(journalPath, xorCipher(42)).Append(third).Err(); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+
+	contents, err := ioutil.ReadFile(journalPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(contents, []byte("4000")) {
+		t.Errorf("journal contains a clear text value")
+	}
+
+	journal := New(journalPath, xorCipher(42)).Load()
+	if err := journal.Err(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if got := len(journal.Entries()); got != 3 {
+		t.Fatalf("got %d entries, want 3", got)
+	}
+
+	port := journal.For("PORT")
+	if len(port) != 2 {
+		t.Fatalf("got %d entries for PORT, want 2", len(port))
+	}
+	if port[0] != first || port[1] != third {
+		t.Errorf("For(PORT) = %+v, want [%+v %+v]", port, first, third)
+	}
+
+	if key := journal.For("KEY"); len(key) != 1 || key[0] != second {
+		t.Errorf("For(KEY) = %+v, want [%+v]", key, second)
+	}
+}
+
+func TestLoadMissingJournal(t *testing.T) {
+	journal := New(path.Join(t.TempDir(), "history"), xorCipher(42)).Load()
+
+	if err := journal.Err(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := len(journal.Entries()); got != 0 {
+		t.Errorf("got %d entries, want 0", got)
+	}
+}
+
+func TestLoadWithWrongKey(t *testing.T) {
+	journalPath := path.Join(t.TempDir(), "history")
+	entry := Entry{Secret: "PORT", Value: "3000", Type: EntryAdd}
+
+	if err := New(journalPath, failingCipher{}).Append(entry).Err(); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+
+	if err := New(journalPath, failingCipher{}).Load().Err(); err == nil {
+		t.Errorf("Load: expected an error")
+	}
+}
+
+func TestRekey(t *testing.T) {
+	journalPath := path.Join(t.TempDir(), "history")
+	entry := Entry{Secret: "PORT", Value: "3000", Type: EntryAdd, Sender: "alice"}
+
+	if err := New(journalPath, xorCipher(42)).Append(entry).Err(); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+
+	journal := New(journalPath, xorCipher(42)).Rekey(xorCipher(7))
+	if err := journal.Err(); err != nil {
+		t.Fatalf("Rekey: %v", err)
+	}
+
+	// New entries are encrypted with the new cipher too
+	next := Entry{Secret: "PORT", Value: "4000", Type: EntryChange, Sender: "bob"}
+	if err := journal.Append(next).Err(); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+
+	if err := New(journalPath, xorCipher(42)).Load().Err(); err == nil {
+		t.Errorf("Load: expected the old cipher to fail")
+	}
+
+	got := New(journalPath, xorCipher(7)).Load()
+	if err := got.Err(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if entries := got.For("PORT"); len(entries) != 2 ||
+		entries[0].Value != "3000" || entries[1].Value != "4000" {
+		t.Errorf("got %+v", entries)
+	}
+}
+
+func TestRekeyMissingJournal(t *testing.T) {
+	journalPath := path.Join(t.TempDir(), "history")
+
+	if err := New(journalPath, xorCipher(42)).Rekey(xorCipher(7)).Err(); err != nil {
+		t.Fatalf("Rekey: %v", err)
+	}
+	if _, err := os.Stat(journalPath); !os.IsNotExist(err) {
+		t.Errorf("expected no journal to be created")
+	}
+}
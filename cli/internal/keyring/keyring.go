@@ -0,0 +1,128 @@
+// Package keyring keeps the unlocked private key of the device for a
+// short time, so that the passphrase protecting it is not asked for by
+// every command.
+//
+// The key is kept in a file of the runtime directory of the user, which
+// is not part of the configuration directory nor of backups. The file is
+// removed when it is found expired, and when the user logs out.
+// The directory is only used if it belongs to the user, and no one else
+// can access it.
+package keyring
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"time"
+)
+
+// Keyring is the short-lived file holding the key of one device
+type Keyring struct {
+	path string
+}
+
+type entry struct {
+	Key       []byte    `json:"key"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Dir function returns the directory where keyrings are kept:
+// $XDG_RUNTIME_DIR/keystone, or a directory of its own in the temporary
+// directory of the system
+func Dir() string {
+	if runtimeDir := os.Getenv("XDG_RUNTIME_DIR"); runtimeDir != "" {
+		return path.Join(runtimeDir, "keystone")
+	}
+
+	return path.Join(os.TempDir(), fmt.Sprintf("keystone-%d", os.Getuid()))
+}
+
+// New function returns the keyring for the device `deviceUID`,
+// in the directory `dir`
+func New(dir string, deviceUID string) *Keyring {
+	return &Keyring{
+		path: path.Join(dir, "keyring-"+deviceUID),
+	}
+}
+
+// Path method returns the path to the keyring file
+func (k *Keyring) Path() string {
+	return k.path
+}
+
+// Store method keeps `key` in the keyring for `ttl`.
+// A zero or negative `ttl` clears the keyring instead.
+func (k *Keyring) Store(key []byte, ttl time.Duration) error {
+	if ttl <= 0 {
+		return k.Clear()
+	}
+
+	contents, err := json.Marshal(entry{
+		Key:       key,
+		ExpiresAt: time.Now().Add(ttl),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(path.Dir(k.path), 0o700); err != nil {
+		return err
+	}
+
+	if err = checkDir(path.Dir(k.path)); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(k.path, contents, 0o600)
+}
+
+// Load method returns the key found in the keyring.
+// The second value is false if there is none, if it has expired, or if
+// the directory of the keyring cannot be trusted.
+func (k *Keyring) Load() ([]byte, bool) {
+	if checkDir(path.Dir(k.path)) != nil {
+		return nil, false
+	}
+
+	/* #nosec */
+	contents, err := ioutil.ReadFile(k.path)
+	if err != nil {
+		return nil, false
+	}
+
+	var e entry
+	if err = json.Unmarshal(contents, &e); err != nil ||
+		time.Now().After(e.ExpiresAt) {
+		_ = k.Clear()
+		return nil, false
+	}
+
+	return e.Key, true
+}
+
+// Clear method removes the key from the keyring
+func (k *Keyring) Clear() error {
+	if err := os.Remove(k.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// checkDir function returns an error unless `dir` is a directory, and not
+// a link to one, that belongs to the current user and that only they
+// can access
+func checkDir(dir string) error {
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return err
+	}
+
+	if info.Mode()&os.ModeSymlink != 0 || !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+
+	return checkPrivate(dir, info)
+}
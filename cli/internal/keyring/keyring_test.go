@@ -0,0 +1,116 @@
+package keyring
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+)
+
+// keyringDir returns a directory that does not exist yet,
+// as the keyring directory of a new session
+func keyringDir(t *testing.T) string {
+	return path.Join(t.TempDir(), "keystone")
+}
+
+func TestStoreAndLoad(t *testing.T) {
+	k := New(keyringDir(t), "device-uid")
+
+	if _, ok := k.Load(); ok {
+		t.Fatalf("expected an empty keyring")
+	}
+
+	if err := k.Store([]byte("private key"), time.Minute); err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+
+	info, err := os.Stat(k.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("expected 0600 permissions, got %v", info.Mode().Perm())
+	}
+
+	key, ok := k.Load()
+	if !ok || string(key) != "private key" {
+		t.Errorf("got %q, %v", key, ok)
+	}
+}
+
+func TestExpiredKeyIsRemoved(t *testing.T) {
+	k := New(keyringDir(t), "device-uid")
+
+	if err := k.Store([]byte("private key"), time.Nanosecond); err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+	time.Sleep(time.Millisecond)
+
+	if _, ok := k.Load(); ok {
+		t.Errorf("expected the key to have expired")
+	}
+	if _, err := os.Stat(k.Path()); !os.IsNotExist(err) {
+		t.Errorf("expected the keyring file to be removed")
+	}
+}
+
+func TestZeroTTLClearsKeyring(t *testing.T) {
+	k := New(keyringDir(t), "device-uid")
+
+	if err := k.Store([]byte("private key"), time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if err := k.Store([]byte("private key"), 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := k.Load(); ok {
+		t.Errorf("expected an empty keyring")
+	}
+}
+
+func TestKeyringsAreDistinctPerDevice(t *testing.T) {
+	dir := keyringDir(t)
+
+	if err := New(dir, "one").Store([]byte("first"), time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := New(dir, "two").Load(); ok {
+		t.Errorf("expected the keyring of another device to be empty")
+	}
+}
+
+func TestUntrustedDirIsRefused(t *testing.T) {
+	dir := keyringDir(t)
+
+	if err := New(dir, "device-uid").Store([]byte("private key"), time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	// Others can access the directory
+	if err := os.Chmod(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := New(dir, "device-uid").Load(); ok {
+		t.Errorf("expected a directory others can access to be refused")
+	}
+	if err := New(dir, "device-uid").Store([]byte("private key"), time.Minute); err == nil {
+		t.Errorf("expected Store to refuse a directory others can access")
+	}
+
+	if err := os.Chmod(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	// Links could point anywhere
+	link := path.Join(t.TempDir(), "link")
+	if err := os.Symlink(dir, link); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := New(link, "device-uid").Load(); ok {
+		t.Errorf("expected a link to be refused")
+	}
+}
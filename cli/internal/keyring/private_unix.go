@@ -0,0 +1,24 @@
+// +build !windows
+
+package keyring
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// checkPrivate function returns an error unless the current user owns
+// `dir`, and is the only one who can access it
+func checkPrivate(dir string, info os.FileInfo) error {
+	if perm := info.Mode().Perm(); perm != 0o700 {
+		return fmt.Errorf("%s has permissions %o, instead of 700", dir, perm)
+	}
+
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok || int(stat.Uid) != os.Getuid() {
+		return fmt.Errorf("%s does not belong to the current user", dir)
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package keystonefile
+
+import (
+	"path"
+	"sort"
+	"strings"
+)
+
+// Contains method returns true if `filePath` is one of the files tracked
+// by a directory entry: it is under the directory, matches one of the
+// `Include` patterns if there are any, and none of the `Exclude` ones.
+//
+// Patterns use the `path.Match` syntax, and are matched against the path
+// relative to the directory, its base name, and each of its parent
+// directories, so `*.pem` matches `certs/ca.pem` and `old` excludes
+// everything under `old/`.
+func (fk FileKey) Contains(filePath string) bool {
+	if !fk.Directory {
+		return false
+	}
+
+	directory := path.Clean(fk.Path)
+	filePath = path.Clean(filePath)
+
+	if !strings.HasPrefix(filePath, directory+"/") {
+		return false
+	}
+
+	relativePath := strings.TrimPrefix(filePath, directory+"/")
+
+	if len(fk.Include) > 0 && !matchesAny(fk.Include, relativePath) {
+		return false
+	}
+
+	return !matchesAny(fk.Exclude, relativePath)
+}
+
+// Expand method returns an entry for each of the `filePaths` the
+// directory entry contains, sorted and without duplicates.
+// They are required in the same environments as the directory.
+// A single file entry expands to itself.
+func (fk FileKey) Expand(filePaths []string) []FileKey {
+	if !fk.Directory {
+		return []FileKey{fk}
+	}
+
+	contained := make([]string, 0)
+	for _, filePath := range filePaths {
+		filePath = path.Clean(filePath)
+
+		if fk.Contains(filePath) && !containsString(contained, filePath) {
+			contained = append(contained, filePath)
+		}
+	}
+
+	sort.Strings(contained)
+
+	files := make([]FileKey, 0, len(contained))
+	for _, filePath := range contained {
+		files = append(files, FileKey{
+			Path:       filePath,
+			Strict:     fk.Strict,
+			RequiredIn: fk.RequiredIn,
+			Mode:       fk.Mode,
+			FromCache:  fk.FromCache,
+		})
+	}
+
+	return files
+}
+
+// ValidatePatterns function returns `path.ErrBadPattern` if one of the
+// `patterns` is malformed
+func ValidatePatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// matchesAny returns true if one of the `patterns` matches
+// `relativePath`, its base name, or one of its parent directories
+func matchesAny(patterns []string, relativePath string) bool {
+	candidates := []string{relativePath, path.Base(relativePath)}
+
+	for dir := path.Dir(relativePath); dir != "."; dir = path.Dir(dir) {
+		candidates = append(candidates, dir, path.Base(dir))
+	}
+
+	for _, pattern := range patterns {
+		for _, candidate := range candidates {
+			if matched, _ := path.Match(pattern, candidate); matched {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,72 @@
+package keystonefile
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestContains(t *testing.T) {
+	cases := []struct {
+		name     string
+		file     FileKey
+		path     string
+		expected bool
+	}{
+		{"single file", FileKey{Path: "certs"}, "certs/ca.pem", false},
+		{"in directory", FileKey{Path: "certs", Directory: true}, "certs/ca.pem", true},
+		{"in subdirectory", FileKey{Path: "certs/", Directory: true}, "certs/prod/ca.pem", true},
+		{"directory itself", FileKey{Path: "certs", Directory: true}, "certs", false},
+		{"same prefix", FileKey{Path: "certs", Directory: true}, "certs-old/ca.pem", false},
+		{"included", FileKey{Path: "certs", Directory: true, Include: []string{"*.pem"}}, "certs/prod/ca.pem", true},
+		{"not included", FileKey{Path: "certs", Directory: true, Include: []string{"*.pem"}}, "certs/README.md", false},
+		{"excluded", FileKey{Path: "certs", Directory: true, Exclude: []string{"*.md"}}, "certs/README.md", false},
+		{"excluded directory", FileKey{Path: "certs", Directory: true, Exclude: []string{"old"}}, "certs/old/ca.pem", false},
+		{"exclude wins", FileKey{Path: "certs", Directory: true, Include: []string{"*.pem"}, Exclude: []string{"prod/*"}}, "certs/prod/ca.pem", false},
+	}
+
+	for _, c := range cases {
+		if got := c.file.Contains(c.path); got != c.expected {
+			t.Errorf("%s: expected %v, got %v", c.name, c.expected, got)
+		}
+	}
+}
+
+func TestExpand(t *testing.T) {
+	directory := FileKey{
+		Path:       "certs",
+		RequiredIn: []string{"prod"},
+		Directory:  true,
+		Exclude:    []string{"*.md"},
+	}
+
+	got := directory.Expand([]string{
+		"certs/b.pem",
+		"certs/README.md",
+		"config.json",
+		"certs/a.pem",
+		"./certs/b.pem",
+	})
+	expected := []FileKey{
+		{Path: "certs/a.pem", RequiredIn: []string{"prod"}},
+		{Path: "certs/b.pem", RequiredIn: []string{"prod"}},
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+
+	single := FileKey{Path: "config.json", Strict: true}
+	if got := single.Expand(nil); !reflect.DeepEqual(got, []FileKey{single}) {
+		t.Errorf("a single file should expand to itself, got %+v", got)
+	}
+}
+
+func TestValidatePatterns(t *testing.T) {
+	if err := ValidatePatterns([]string{"*.pem", "prod/*"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if err := ValidatePatterns([]string{"*.pem", "[a-"}); err == nil {
+		t.Error("malformed patterns should be rejected")
+	}
+}
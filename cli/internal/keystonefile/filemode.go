@@ -0,0 +1,64 @@
+package keystonefile
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// DefaultFileMode is the mode of files that have none
+const DefaultFileMode os.FileMode = 0o644
+
+// FileMode is the permissions of a file, written in octal
+// in keystone.yaml: `mode: "0600"`
+type FileMode os.FileMode
+
+// ParseFileMode function parses an octal mode, such as `0600` or `600`.
+// `0000` is refused: a zero mode means that there is none.
+func ParseFileMode(mode string) (FileMode, error) {
+	value, err := strconv.ParseUint(mode, 8, 32)
+	if err != nil || value == 0 || os.FileMode(value)&^os.ModePerm != 0 {
+		return 0, fmt.Errorf("invalid file mode %q", mode)
+	}
+
+	return FileMode(value), nil
+}
+
+// Perm method returns the permissions, or `DefaultFileMode` if there
+// are none
+func (mode FileMode) Perm() os.FileMode {
+	if mode == 0 {
+		return DefaultFileMode
+	}
+
+	return os.FileMode(mode).Perm()
+}
+
+// IsWorldReadable method returns true if anyone can read the file
+func (mode FileMode) IsWorldReadable() bool {
+	return mode.Perm()&0o004 != 0
+}
+
+func (mode FileMode) String() string {
+	return fmt.Sprintf("%04o", uint32(mode))
+}
+
+func (mode FileMode) MarshalYAML() (interface{}, error) {
+	return mode.String(), nil
+}
+
+func (mode *FileMode) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var value string
+	if err := unmarshal(&value); err != nil {
+		return err
+	}
+
+	parsed, err := ParseFileMode(value)
+	if err != nil {
+		return err
+	}
+
+	*mode = parsed
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package keystonefile
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestParseFileMode(t *testing.T) {
+	cases := map[string]os.FileMode{
+		"0600": 0o600,
+		"600":  0o600,
+		"0755": 0o755,
+	}
+
+	for value, expected := range cases {
+		mode, err := ParseFileMode(value)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", value, err)
+			continue
+		}
+
+		if mode.Perm() != expected {
+			t.Errorf("%s: expected %o, got %o", value, expected, mode.Perm())
+		}
+	}
+
+	for _, value := range []string{"", "rw-------", "0900", "17777", "0000", "0"} {
+		if _, err := ParseFileMode(value); err == nil {
+			t.Errorf("%q should not be a valid mode", value)
+		}
+	}
+}
+
+func TestFileModePerm(t *testing.T) {
+	if FileMode(0).Perm() != DefaultFileMode {
+		t.Errorf("files without a mode should get %o", DefaultFileMode)
+	}
+
+	if FileMode(0o600).IsWorldReadable() || !FileMode(0o644).IsWorldReadable() {
+		t.Error("only files readable by others are world readable")
+	}
+}
+
+func TestFileModeYaml(t *testing.T) {
+	file := new(KeystoneFile).fromYaml([]byte(`
+files:
+  - path: .ssh/id_ed25519
+    strict: true
+    mode: "0600"
+  - path: config.json
+    strict: false
+`))
+	if err := file.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if file.Files[0].Mode.Perm() != 0o600 || file.Files[1].Mode != 0 {
+		t.Errorf("unexpected modes: %+v", file.Files)
+	}
+
+	out := string(file.toYaml())
+	if !strings.Contains(out, `mode: "0600"`) {
+		t.Errorf("the mode should be saved in octal:\n%s", out)
+	}
+
+	if strings.Count(out, "mode:") != 1 {
+		t.Errorf("files without a mode should not have one:\n%s", out)
+	}
+
+	if new(KeystoneFile).fromYaml([]byte(`
+files:
+  - path: key.pem
+    mode: "rw"
+`)).Err() == nil {
+		t.Error("invalid modes should be rejected")
+	}
+}
@@ -0,0 +1,140 @@
+package keystonefile
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// InheritanceCycleError is returned by `KeystoneFile.Parents` when
+// environments inherit from each other in a loop
+type InheritanceCycleError struct {
+	// Names of the environments in the loop, the first one being repeated
+	// at the end
+	Cycle []string
+}
+
+func (e *InheritanceCycleError) Error() string {
+	return fmt.Sprintf("inheritance cycle: %s", strings.Join(e.Cycle, " -> "))
+}
+
+// UnknownParentEnvironmentError is returned by `KeystoneFile.Parents` when
+// an environment inherits from one that is not known
+type UnknownParentEnvironmentError struct {
+	Environment string
+	Parent      string
+}
+
+func (e *UnknownParentEnvironmentError) Error() string {
+	return fmt.Sprintf(
+		"'%s' inherits from unknown environment '%s'",
+		e.Environment,
+		e.Parent,
+	)
+}
+
+// Parents method returns the environments `environmentName` inherits
+// values from, nearest first.
+// An environment inherits from the one declared in its `inherits` setting,
+// which in turn inherits from its own parent, and so on.
+// Every parent must be one of `environments`.
+func (file *KeystoneFile) Parents(
+	environmentName string,
+	environments []string,
+) ([]string, error) {
+	parents := make([]string, 0)
+
+	if file.Err() != nil {
+		return parents, nil
+	}
+
+	visited := []string{environmentName}
+	current := environmentName
+
+	for {
+		parent := file.Environments[current].Inherits
+		if parent == "" {
+			return parents, nil
+		}
+
+		if !containsString(environments, parent) {
+			return parents, &UnknownParentEnvironmentError{
+				Environment: current,
+				Parent:      parent,
+			}
+		}
+
+		for index, name := range visited {
+			if name == parent {
+				cycle := append([]string{}, visited[index:]...)
+				cycle = append(cycle, parent)
+
+				return parents, &InheritanceCycleError{Cycle: cycle}
+			}
+		}
+
+		parents = append(parents, parent)
+		visited = append(visited, parent)
+		current = parent
+	}
+}
+
+// Children method returns the environments that inherit directly
+// from `environmentName`, sorted by name
+func (file *KeystoneFile) Children(environmentName string) []string {
+	children := make([]string, 0)
+
+	for name, settings := range file.Environments {
+		if settings.Inherits == environmentName {
+			children = append(children, name)
+		}
+	}
+
+	sort.Strings(children)
+
+	return children
+}
+
+// RenameEnvironment method moves the settings of an environment to
+// `newName`, and makes the environments inheriting from it inherit
+// from `newName`.
+// Secrets and files required in it become required in `newName`.
+func (file *KeystoneFile) RenameEnvironment(
+	name string,
+	newName string,
+) *KeystoneFile {
+	if file.Err() != nil {
+		return file
+	}
+
+	if settings, ok := file.Environments[name]; ok {
+		delete(file.Environments, name)
+		file.Environments[newName] = settings
+	}
+
+	for environment, settings := range file.Environments {
+		if settings.Inherits == name {
+			settings.Inherits = newName
+			file.Environments[environment] = settings
+		}
+	}
+
+	file.replaceRequiredIn(name, newName)
+
+	return file
+}
+
+// RemoveEnvironment method removes the settings of an environment,
+// and stops requiring secrets and files in it.
+// Environments inheriting from it are left untouched, see
+// `KeystoneFile.Children`
+func (file *KeystoneFile) RemoveEnvironment(name string) *KeystoneFile {
+	if file.Err() != nil {
+		return file
+	}
+
+	delete(file.Environments, name)
+	file.replaceRequiredIn(name, "")
+
+	return file
+}
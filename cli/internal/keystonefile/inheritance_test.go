@@ -0,0 +1,140 @@
+package keystonefile
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParents(t *testing.T) {
+	file := &KeystoneFile{
+		Environments: map[string]EnvironmentSettings{
+			"staging": {Inherits: "dev"},
+			"prod":    {Inherits: "staging"},
+			"qa":      {},
+		},
+	}
+
+	environments := []string{"dev", "staging", "prod", "qa"}
+
+	tests := []struct {
+		environment string
+		want        []string
+	}{
+		{"dev", []string{}},
+		{"qa", []string{}},
+		{"staging", []string{"dev"}},
+		{"prod", []string{"staging", "dev"}},
+	}
+
+	for _, tt := range tests {
+		got, err := file.Parents(tt.environment, environments)
+		if err != nil {
+			t.Errorf("Parents(%s) unexpected error: %v", tt.environment, err)
+		}
+
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Parents(%s) = %v, want %v", tt.environment, got, tt.want)
+		}
+	}
+}
+
+func TestParentsCycle(t *testing.T) {
+	file := &KeystoneFile{
+		Environments: map[string]EnvironmentSettings{
+			"dev":     {Inherits: "prod"},
+			"staging": {Inherits: "dev"},
+			"prod":    {Inherits: "staging"},
+		},
+	}
+
+	_, err := file.Parents("prod", []string{"dev", "staging", "prod"})
+
+	var cycleError *InheritanceCycleError
+	if !errors.As(err, &cycleError) {
+		t.Fatalf("expected a cycle error, got %v", err)
+	}
+
+	want := []string{"prod", "staging", "dev", "prod"}
+	if !reflect.DeepEqual(cycleError.Cycle, want) {
+		t.Errorf("expected cycle %v, got %v", want, cycleError.Cycle)
+	}
+}
+
+func TestParentsUnknownParent(t *testing.T) {
+	file := &KeystoneFile{
+		Environments: map[string]EnvironmentSettings{
+			"staging": {Inherits: "dev"},
+			"prod":    {Inherits: "preprod"},
+		},
+	}
+
+	_, err := file.Parents("prod", []string{"dev", "staging", "prod"})
+
+	var unknownError *UnknownParentEnvironmentError
+	if !errors.As(err, &unknownError) {
+		t.Fatalf("expected an unknown parent error, got %v", err)
+	}
+
+	if unknownError.Environment != "prod" || unknownError.Parent != "preprod" {
+		t.Errorf("unexpected error %v", unknownError)
+	}
+}
+
+func TestRenameEnvironment(t *testing.T) {
+	file := &KeystoneFile{
+		Environments: map[string]EnvironmentSettings{
+			"staging": {Inherits: "dev"},
+			"prod":    {Inherits: "staging"},
+			"qa":      {Inherits: "staging"},
+		},
+	}
+
+	if got := file.Children("staging"); !reflect.DeepEqual(got, []string{"prod", "qa"}) {
+		t.Errorf("Children(staging) = %v", got)
+	}
+
+	file.RenameEnvironment("staging", "preprod")
+
+	want := map[string]EnvironmentSettings{
+		"preprod": {Inherits: "dev"},
+		"prod":    {Inherits: "preprod"},
+		"qa":      {Inherits: "preprod"},
+	}
+	if !reflect.DeepEqual(file.Environments, want) {
+		t.Errorf("expected %v, got %v", want, file.Environments)
+	}
+}
+
+func TestRenameEnvironmentRequiredIn(t *testing.T) {
+	file := &KeystoneFile{
+		Env: []EnvKey{
+			{Key: "API_KEY", RequiredIn: []string{"qa", "prod"}},
+			{Key: "LOG_LEVEL", RequiredIn: []string{"prod"}},
+		},
+		Files: []FileKey{
+			{Path: "config.json", RequiredIn: []string{"qa"}},
+		},
+	}
+
+	file.RenameEnvironment("qa", "preprod")
+
+	if got := file.Env[0].RequiredIn; !reflect.DeepEqual(got, []string{"prod", "preprod"}) {
+		t.Errorf("API_KEY required in %v", got)
+	}
+	if got := file.Env[1].RequiredIn; !reflect.DeepEqual(got, []string{"prod"}) {
+		t.Errorf("LOG_LEVEL required in %v", got)
+	}
+	if got := file.Files[0].RequiredIn; !reflect.DeepEqual(got, []string{"preprod"}) {
+		t.Errorf("config.json required in %v", got)
+	}
+
+	file.RemoveEnvironment("preprod")
+
+	if got := file.Env[0].RequiredIn; !reflect.DeepEqual(got, []string{"prod"}) {
+		t.Errorf("API_KEY required in %v", got)
+	}
+	if got := file.Files[0].RequiredIn; got != nil {
+		t.Errorf("config.json required in %v", got)
+	}
+}
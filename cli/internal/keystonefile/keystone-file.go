@@ -15,12 +15,67 @@ import (
 type EnvKey struct {
 	Key    string
 	Strict bool
+	// Environments the variable is required in, when not `Strict`
+	RequiredIn []string `yaml:"required_in,omitempty"`
+	// Values are not expanded, they were given as files or binary data
+	Verbatim         bool `yaml:"verbatim,omitempty"`
+	EnvDocumentation `yaml:",inline"`
+	// Optional constraints on the secret value, see `EnvKey.Validate`
+	Type      string   `yaml:"type,omitempty"`
+	Regex     string   `yaml:"regex,omitempty"`
+	MinLength int      `yaml:"min_length,omitempty"`
+	MaxLength int      `yaml:"max_length,omitempty"`
+	Enum      []string `yaml:"enum,omitempty"`
+}
+
+// EnvDocumentation holds what people need to know about a variable
+type EnvDocumentation struct {
+	Description string `yaml:"description,omitempty"`
+	Owner       string `yaml:"owner,omitempty"`
+	DocsURL     string `yaml:"docs_url,omitempty"`
 }
 
 type FileKey struct {
-	Path      string
-	Strict    bool
-	FromCache bool `yaml:"-"`
+	Path   string
+	Strict bool
+	// Environments the file is required in, when not `Strict`
+	RequiredIn []string `yaml:"required_in,omitempty"`
+	// Directory entries track every file under `Path`,
+	// see `FileKey.Contains`
+	Directory bool     `yaml:"directory,omitempty"`
+	Include   []string `yaml:"include,omitempty"`
+	Exclude   []string `yaml:"exclude,omitempty"`
+	// Permissions the file is written with, see `Context.FileMode`
+	Mode      FileMode `yaml:"mode,omitempty"`
+	FromCache bool     `yaml:"-"`
+}
+
+// IsRequiredIn method returns true if the variable must have a value
+// in `environment`
+func (ek EnvKey) IsRequiredIn(environment string) bool {
+	return ek.Strict || containsString(ek.RequiredIn, environment)
+}
+
+// IsRequiredIn method returns true if the file must have content
+// in `environment`
+func (fk FileKey) IsRequiredIn(environment string) bool {
+	return fk.Strict || containsString(fk.RequiredIn, environment)
+}
+
+// RenderKey is a template rendered with the secrets and files of the
+// current environment, see `ks render`
+type RenderKey struct {
+	// Template path, relative to the project root
+	Template string `yaml:"template"`
+	// Output path, relative to the project root
+	Output string `yaml:"output"`
+}
+
+// EnvironmentSettings holds the per environment configuration
+type EnvironmentSettings struct {
+	// Name of the environment whose values are used when a value is
+	// not set in this environment
+	Inherits string `yaml:"inherits,omitempty"`
 }
 
 type keystoneFileOptions struct {
@@ -29,19 +84,18 @@ type keystoneFileOptions struct {
 
 // Represents the contents of the keystone.yaml file
 type KeystoneFile struct {
-	Path        string `yaml:"-"`
-	err         error  `yaml:"-"`
-	ProjectId   string `yaml:"project_id"`
-	ProjectName string `yaml:"name"`
-	Env         []EnvKey
-	Files       []FileKey
-	Options     keystoneFileOptions
-	CiServices  []CiService `yaml:"ci_services"`
+	Path         string `yaml:"-"`
+	err          error  `yaml:"-"`
+	ProjectId    string `yaml:"project_id"`
+	ProjectName  string `yaml:"name"`
+	Env          []EnvKey
+	Files        []FileKey
+	Options      keystoneFileOptions
+	CiServices   []CiService                    `yaml:"ci_services"`
+	Environments map[string]EnvironmentSettings `yaml:"environments,omitempty"`
+	Renders      []RenderKey                    `yaml:"renders,omitempty"`
 }
 
-var ksf *KeystoneFile
-var loaded bool = false
-
 type CiService struct {
 	Name    string            `yaml:"name"`
 	Type    string            `yaml:"type"`
@@ -68,20 +122,11 @@ func NewKeystoneFile(wd string, project models.Project) *KeystoneFile {
 	}
 }
 
+// LoadKeystoneFile function reads the keystone.yaml of the project in `wd`.
+// Nothing is cached, so that several projects can be worked on
+// in the same process, and changes saved to the file are always seen.
 func LoadKeystoneFile(wd string) *KeystoneFile {
-	if loaded && ksf != nil {
-		return ksf
-	} else {
-		k := new(KeystoneFile).Load(wd)
-		if k.err != nil {
-			panic(k.err)
-		} else {
-			loaded = true
-			ksf = k
-		}
-
-		return ksf
-	}
+	return new(KeystoneFile).Load(wd)
 }
 
 // Checks if current execution context contains a keystone.yaml
@@ -176,7 +221,14 @@ func (file *KeystoneFile) SetEnv(varname string, strict bool) *KeystoneFile {
 		return file
 	}
 
-	file.UnsetEnv(varname) // avoid duplicates
+	// Keep the validation rules of an already declared variable
+	for index, env := range file.Env {
+		if env.Key == varname {
+			file.Env[index].Strict = strict
+
+			return file
+		}
+	}
 
 	file.Env = append(file.Env, EnvKey{
 		Key:    varname,
@@ -186,6 +238,30 @@ func (file *KeystoneFile) SetEnv(varname string, strict bool) *KeystoneFile {
 	return file
 }
 
+// SetEnvRequiredIn method marks the variable `varname` as required, or not,
+// in `environment` only
+func (file *KeystoneFile) SetEnvRequiredIn(
+	varname string,
+	environment string,
+	required bool,
+) *KeystoneFile {
+	if file.Err() != nil {
+		return file
+	}
+
+	for index, env := range file.Env {
+		if env.Key == varname {
+			file.Env[index].RequiredIn = setMembership(
+				env.RequiredIn,
+				environment,
+				required,
+			)
+		}
+	}
+
+	return file
+}
+
 // HasEnv method returns true the environment variable `varname` exists
 // in the keystone file
 func (file *KeystoneFile) HasEnv(varname string) (hasIt bool, strict bool) {
@@ -202,6 +278,58 @@ func (file *KeystoneFile) HasEnv(varname string) (hasIt bool, strict bool) {
 	return false, false
 }
 
+// GetEnv method returns the declaration of the environment variable
+// `varname`, and wether it exists in the keystone file
+func (file *KeystoneFile) GetEnv(varname string) (envKey EnvKey, hasIt bool) {
+	if file.Err() != nil {
+		return envKey, false
+	}
+
+	for _, ek := range file.Env {
+		if ek.Key == varname {
+			return ek, true
+		}
+	}
+
+	return envKey, false
+}
+
+// SetEnvDocumentation method replaces the documentation of the
+// environment variable `varname`
+func (file *KeystoneFile) SetEnvDocumentation(
+	varname string,
+	documentation EnvDocumentation,
+) *KeystoneFile {
+	if file.Err() != nil {
+		return file
+	}
+
+	for index, env := range file.Env {
+		if env.Key == varname {
+			file.Env[index].EnvDocumentation = documentation
+		}
+	}
+
+	return file
+}
+
+// SetEnvVerbatim method marks the values of the environment variable
+// `varname` as verbatim: references to other variables in them are
+// not expanded
+func (file *KeystoneFile) SetEnvVerbatim(varname string) *KeystoneFile {
+	if file.Err() != nil {
+		return file
+	}
+
+	for index, env := range file.Env {
+		if env.Key == varname {
+			file.Env[index].Verbatim = true
+		}
+	}
+
+	return file
+}
+
 // Removes a variable from the project
 func (file *KeystoneFile) UnsetEnv(varname string) *KeystoneFile {
 	if file.Err() != nil {
@@ -272,6 +400,30 @@ func (file *KeystoneFile) SetFileRequired(
 	return file
 }
 
+// SetFileRequiredIn method marks a file as required, or not,
+// in `environment` only
+func (file *KeystoneFile) SetFileRequiredIn(
+	filepath string,
+	environment string,
+	required bool,
+) *KeystoneFile {
+	if file.Err() != nil {
+		return file
+	}
+
+	for index, f := range file.Files {
+		if f.Path == filepath {
+			file.Files[index].RequiredIn = setMembership(
+				f.RequiredIn,
+				environment,
+				required,
+			)
+		}
+	}
+
+	return file
+}
+
 // AddCiService method adds a CI service to the keystone file
 func (file *KeystoneFile) AddCiService(ciService CiService) *KeystoneFile {
 	if file.Err() != nil {
@@ -313,3 +465,65 @@ func (file *KeystoneFile) GetCiService(serviceName string) CiService {
 	}
 	return ciService
 }
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// replaceRequiredIn replaces `environment` with `newEnvironment` in the
+// environments variables and files are required in.
+// With an empty `newEnvironment`, `environment` is only removed.
+func (file *KeystoneFile) replaceRequiredIn(
+	environment string,
+	newEnvironment string,
+) {
+	for index, env := range file.Env {
+		if containsString(env.RequiredIn, environment) {
+			requiredIn := setMembership(env.RequiredIn, environment, false)
+			if newEnvironment != "" {
+				requiredIn = setMembership(requiredIn, newEnvironment, true)
+			}
+
+			file.Env[index].RequiredIn = requiredIn
+		}
+	}
+
+	for index, f := range file.Files {
+		if containsString(f.RequiredIn, environment) {
+			requiredIn := setMembership(f.RequiredIn, environment, false)
+			if newEnvironment != "" {
+				requiredIn = setMembership(requiredIn, newEnvironment, true)
+			}
+
+			file.Files[index].RequiredIn = requiredIn
+		}
+	}
+}
+
+// setMembership returns `list` with `value` added if `member` is true,
+// or removed otherwise
+func setMembership(list []string, value string, member bool) []string {
+	result := make([]string, 0, len(list)+1)
+
+	for _, item := range list {
+		if item != value {
+			result = append(result, item)
+		}
+	}
+
+	if member {
+		result = append(result, value)
+	}
+
+	if len(result) == 0 {
+		return nil
+	}
+
+	return result
+}
@@ -1,6 +1,8 @@
 package keystonefile
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/wearedevx/keystone/api/pkg/models"
@@ -52,3 +54,78 @@ func TestKeystoneFile(t *testing.T) {
 		utils.CleanTestDir(testDir)
 	})
 }
+
+func TestRequiredIn(t *testing.T) {
+	file := &KeystoneFile{
+		Env: []EnvKey{
+			{Key: "SENTRY_DSN"},
+			{Key: "DATABASE_URL", Strict: true},
+		},
+		Files: []FileKey{
+			{Path: "config.json"},
+		},
+	}
+
+	file.
+		SetEnvRequiredIn("SENTRY_DSN", "prod", true).
+		SetEnvRequiredIn("SENTRY_DSN", "staging", true).
+		SetEnvRequiredIn("SENTRY_DSN", "prod", true).
+		SetFileRequiredIn("config.json", "prod", true)
+
+	sentry, _ := file.GetEnv("SENTRY_DSN")
+	if len(sentry.RequiredIn) != 2 {
+		t.Errorf("expected 2 environments, got %v", sentry.RequiredIn)
+	}
+
+	if !sentry.IsRequiredIn("prod") || sentry.IsRequiredIn("dev") {
+		t.Errorf("SENTRY_DSN should be required in prod only, got %v", sentry.RequiredIn)
+	}
+
+	database, _ := file.GetEnv("DATABASE_URL")
+	if !database.IsRequiredIn("dev") {
+		t.Error("strict variables are required in every environment")
+	}
+
+	if !file.Files[0].IsRequiredIn("prod") || file.Files[0].IsRequiredIn("dev") {
+		t.Errorf("config.json should be required in prod only, got %v", file.Files[0].RequiredIn)
+	}
+
+	file.
+		SetEnvRequiredIn("SENTRY_DSN", "prod", false).
+		SetEnvRequiredIn("SENTRY_DSN", "staging", false)
+
+	sentry, _ = file.GetEnv("SENTRY_DSN")
+	if sentry.RequiredIn != nil {
+		t.Errorf("expected no environment, got %v", sentry.RequiredIn)
+	}
+}
+
+func TestRenders(t *testing.T) {
+	file := new(KeystoneFile).fromYaml([]byte(`
+renders:
+  - template: config/nginx.conf.tmpl
+    output: config/nginx.conf
+  - template: app.yml.tmpl
+    output: app.yml
+`))
+	if err := file.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []RenderKey{
+		{Template: "config/nginx.conf.tmpl", Output: "config/nginx.conf"},
+		{Template: "app.yml.tmpl", Output: "app.yml"},
+	}
+
+	if !reflect.DeepEqual(file.Renders, expected) {
+		t.Errorf("expected %+v, got %+v", expected, file.Renders)
+	}
+
+	if out := string(file.toYaml()); !strings.Contains(out, "output: config/nginx.conf") {
+		t.Errorf("renders are not saved:\n%s", out)
+	}
+
+	if out := string(new(KeystoneFile).toYaml()); strings.Contains(out, "renders") {
+		t.Errorf("empty renders should not be saved:\n%s", out)
+	}
+}
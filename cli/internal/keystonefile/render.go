@@ -0,0 +1,36 @@
+package keystonefile
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Validate method returns an error if the template or the output is not
+// a path inside the project.
+// keystone.yaml is shared, it must not make ks read or write files
+// elsewhere on the machine.
+func (rk RenderKey) Validate() error {
+	for _, p := range []string{rk.Template, rk.Output} {
+		if !isInsideProject(p) {
+			return fmt.Errorf("'%s' is not a path inside the project", p)
+		}
+	}
+
+	return nil
+}
+
+// isInsideProject returns true if `p` is relative to the project root,
+// and does not leave it
+func isInsideProject(p string) bool {
+	if p == "" || filepath.IsAbs(p) || path.IsAbs(filepath.ToSlash(p)) {
+		return false
+	}
+
+	cleaned := path.Clean(filepath.ToSlash(p))
+
+	return cleaned != "." &&
+		cleaned != ".." &&
+		!strings.HasPrefix(cleaned, "../")
+}
@@ -0,0 +1,26 @@
+package keystonefile
+
+import "testing"
+
+func TestRenderKeyValidate(t *testing.T) {
+	tests := []struct {
+		render  RenderKey
+		wantErr bool
+	}{
+		{RenderKey{Template: "config.tmpl", Output: "config.json"}, false},
+		{RenderKey{Template: "tpl/app.tmpl", Output: "build/../app.ini"}, false},
+		{RenderKey{Template: "config.tmpl", Output: "../../.bashrc"}, true},
+		{RenderKey{Template: "config.tmpl", Output: "build/../../x"}, true},
+		{RenderKey{Template: "/etc/passwd", Output: "config.json"}, true},
+		{RenderKey{Template: "config.tmpl", Output: "/tmp/out"}, true},
+		{RenderKey{Template: "config.tmpl", Output: "."}, true},
+		{RenderKey{Template: "", Output: "config.json"}, true},
+	}
+
+	for _, tt := range tests {
+		err := tt.render.Validate()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%+v: expected error %v, got %v", tt.render, tt.wantErr, err)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package keystonefile
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Types a secret value can be constrained to
+const (
+	TypeURL    = "url"
+	TypeInt    = "int"
+	TypeBool   = "bool"
+	TypeBase64 = "base64"
+	TypeJSON   = "json"
+	TypePEM    = "pem"
+)
+
+// ValueTypes lists the types supported by the `type` rule
+var ValueTypes = []string{
+	TypeURL,
+	TypeInt,
+	TypeBool,
+	TypeBase64,
+	TypeJSON,
+	TypePEM,
+}
+
+// RuleError is returned by `EnvKey.Validate` when the rules themselves
+// are malformed, rather than the value
+type RuleError struct {
+	Rule   string
+	Reason string
+}
+
+func (e *RuleError) Error() string {
+	return fmt.Sprintf("invalid `%s` rule: %s", e.Rule, e.Reason)
+}
+
+// HasRules method returns true if any constraint is declared for the
+// variable
+func (ek EnvKey) HasRules() bool {
+	return ek.Type != "" ||
+		ek.Regex != "" ||
+		ek.MinLength > 0 ||
+		ek.MaxLength > 0 ||
+		len(ek.Enum) > 0
+}
+
+// Validate method checks `value` against the constraints declared for the
+// variable.
+// Empty values are not validated: whether a value is needed at all is
+// decided by `Strict`.
+// If a rule is malformed, a `*RuleError` is returned.
+func (ek EnvKey) Validate(value string) error {
+	if value == "" {
+		return nil
+	}
+
+	if err := ek.validateType(value); err != nil {
+		return err
+	}
+
+	if ek.Regex != "" {
+		re, err := regexp.Compile(ek.Regex)
+		if err != nil {
+			return &RuleError{Rule: "regex", Reason: err.Error()}
+		}
+
+		if !re.MatchString(value) {
+			return fmt.Errorf("value does not match `%s`", ek.Regex)
+		}
+	}
+
+	if ek.MinLength > 0 && ek.MaxLength > 0 && ek.MinLength > ek.MaxLength {
+		return &RuleError{
+			Rule:   "min_length",
+			Reason: "min_length is greater than max_length",
+		}
+	}
+
+	length := utf8.RuneCountInString(value)
+
+	if ek.MinLength > 0 && length < ek.MinLength {
+		return fmt.Errorf(
+			"value is %d characters long, expected at least %d",
+			length,
+			ek.MinLength,
+		)
+	}
+
+	if ek.MaxLength > 0 && length > ek.MaxLength {
+		return fmt.Errorf(
+			"value is %d characters long, expected at most %d",
+			length,
+			ek.MaxLength,
+		)
+	}
+
+	if len(ek.Enum) > 0 {
+		found := false
+
+		for _, allowed := range ek.Enum {
+			if value == allowed {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return fmt.Errorf(
+				"value must be one of: %s",
+				strings.Join(ek.Enum, ", "),
+			)
+		}
+	}
+
+	return nil
+}
+
+// validateType checks that `value` is of the declared type, if any
+func (ek EnvKey) validateType(value string) error {
+	var valid bool
+
+	switch ek.Type {
+	case "":
+		return nil
+
+	case TypeURL:
+		u, err := url.Parse(value)
+		valid = err == nil && u.Scheme != "" && u.Host != ""
+
+	case TypeInt:
+		_, err := strconv.ParseInt(value, 10, 64)
+		valid = err == nil
+
+	case TypeBool:
+		_, err := strconv.ParseBool(value)
+		valid = err == nil
+
+	case TypeBase64:
+		_, err := base64.StdEncoding.DecodeString(value)
+		valid = err == nil
+
+	case TypeJSON:
+		valid = json.Valid([]byte(value))
+
+	case TypePEM:
+		block, _ := pem.Decode([]byte(value))
+		valid = block != nil
+
+	default:
+		return &RuleError{
+			Rule: "type",
+			Reason: fmt.Sprintf(
+				"unknown type `%s`, expected one of: %s",
+				ek.Type,
+				strings.Join(ValueTypes, ", "),
+			),
+		}
+	}
+
+	if !valid {
+		return fmt.Errorf("value is not a valid %s", ek.Type)
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package keystonefile
+
+import (
+	"errors"
+	"testing"
+)
+
+const testPEM = `-----BEGIN PUBLIC KEY-----
+MFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAE
+-----END PUBLIC KEY-----`
+
+func TestEnvKeyValidate(t *testing.T) {
+	cases := []struct {
+		name  string
+		key   EnvKey
+		value string
+		valid bool
+	}{
+		{"no rules", EnvKey{}, "anything", true},
+		{"empty value", EnvKey{Type: TypeInt}, "", true},
+		{"url", EnvKey{Type: TypeURL}, "postgres://user@db:5432/app", true},
+		{"invalid url", EnvKey{Type: TypeURL}, "db:5432/app", false},
+		{"int", EnvKey{Type: TypeInt}, "-42", true},
+		{"invalid int", EnvKey{Type: TypeInt}, "4.2", false},
+		{"bool", EnvKey{Type: TypeBool}, "true", true},
+		{"invalid bool", EnvKey{Type: TypeBool}, "yes please", false},
+		{"base64", EnvKey{Type: TypeBase64}, "aGVsbG8=", true},
+		{"invalid base64", EnvKey{Type: TypeBase64}, "hello!", false},
+		{"json", EnvKey{Type: TypeJSON}, `{"a": [1, 2]}`, true},
+		{"invalid json", EnvKey{Type: TypeJSON}, `{"a": }`, false},
+		{"pem", EnvKey{Type: TypePEM}, testPEM, true},
+		{"invalid pem", EnvKey{Type: TypePEM}, "not a key", false},
+		{"regex", EnvKey{Regex: "^sk_"}, "sk_live_123", true},
+		{"invalid regex", EnvKey{Regex: "^sk_"}, "pk_live_123", false},
+		{"min length", EnvKey{MinLength: 3}, "abc", true},
+		{"too short", EnvKey{MinLength: 3}, "ab", false},
+		{"max length", EnvKey{MaxLength: 3}, "abc", true},
+		{"too long", EnvKey{MaxLength: 3}, "abcd", false},
+		{"length counts characters", EnvKey{MaxLength: 2}, "éé", true},
+		{"enum", EnvKey{Enum: []string{"debug", "info"}}, "info", true},
+		{"not in enum", EnvKey{Enum: []string{"debug", "info"}}, "warn", false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.key.Validate(c.value)
+
+			if c.valid && err != nil {
+				t.Errorf("expected %q to be valid, got: %v", c.value, err)
+			}
+
+			if !c.valid && err == nil {
+				t.Errorf("expected %q to be invalid", c.value)
+			}
+
+			var ruleError *RuleError
+			if errors.As(err, &ruleError) {
+				t.Errorf("expected a value error, got a rule error: %v", err)
+			}
+		})
+	}
+}
+
+func TestEnvKeyValidateMalformedRules(t *testing.T) {
+	cases := []struct {
+		name string
+		key  EnvKey
+	}{
+		{"unknown type", EnvKey{Type: "email"}},
+		{"bad regex", EnvKey{Regex: "("}},
+		{"min greater than max", EnvKey{MinLength: 5, MaxLength: 2}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.key.Validate("value")
+
+			var ruleError *RuleError
+			if !errors.As(err, &ruleError) {
+				t.Errorf("expected a rule error, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestSetEnvKeepsRules(t *testing.T) {
+	file := &KeystoneFile{
+		Env: []EnvKey{{Key: "PORT", Strict: true, Type: TypeInt}},
+	}
+
+	file.SetEnv("PORT", false)
+
+	envKey, hasIt := file.GetEnv("PORT")
+	if !hasIt {
+		t.Fatal("expected PORT to exist")
+	}
+
+	if envKey.Strict || envKey.Type != TypeInt {
+		t.Errorf("expected PORT to be optional and keep its type, got %+v", envKey)
+	}
+}
@@ -179,6 +179,32 @@ func (s *messageService) fetchNewMessages(
 	s.err = s.decryptMessages(result)
 }
 
+// decryptPayload decrypts `payload` sent by `sender`, with the first of
+// `privateKeys` that works.
+// The previous public key of the sender is tried too, in case it has
+// rotated its keys since.
+func decryptPayload(
+	privateKeys [][]byte,
+	sender models.Device,
+	payload []byte,
+) (decrypted []byte, err error) {
+	publicKeys := [][]byte{sender.PublicKey}
+	if len(sender.PreviousPublicKey) > 0 {
+		publicKeys = append(publicKeys, sender.PreviousPublicKey)
+	}
+
+	for _, privateKey := range privateKeys {
+		for _, publicKey := range publicKeys {
+			decrypted, err = crypto.DecryptMessage(privateKey, publicKey, payload)
+			if err == nil || err == crypto.ErrorEmptyMessage {
+				return decrypted, err
+			}
+		}
+	}
+
+	return decrypted, err
+}
+
 // decryptMessages decrypts messages
 // Since the payload in GetMessageByEnvironmentResponse is bytes anyway,
 // the decryption is done in place.
@@ -199,6 +225,17 @@ func (s *messageService) decryptMessages(
 		s.log.Printf("[Error] Invalid Private Key length: %d\n", len(privateKey))
 	}
 
+	// Messages sent before the last key rotations of the device are
+	// encrypted for its previous keys
+	previousKeys, e := config.GetPreviousPrivateKeys()
+	if e != nil {
+		return kserrors.CouldNotDecryptMessages(
+			"Failed to get the previous private keys",
+			e,
+		)
+	}
+	privateKeys := append([][]byte{privateKey}, previousKeys...)
+
 	for environmentName, environment := range byEnvironment.Environments {
 		msg := environment.Message
 		if msg.Sender.UserID != "" && len(msg.Payload) > 0 {
@@ -241,11 +278,7 @@ func (s *messageService) decryptMessages(
 			}
 
 			if len(msg.Payload) > 0 {
-				d, e := crypto.DecryptMessage(
-					privateKey,
-					udevice.PublicKey,
-					msg.Payload,
-				)
+				d, e := decryptPayload(privateKeys, udevice, msg.Payload)
 				if e != nil && e != crypto.ErrorEmptyMessage {
 					return kserrors.CouldNotDecryptMessages("Decryption failed", e)
 				}
@@ -434,7 +467,11 @@ func (s *messageService) getCurrentUserInformation() (models.User, []byte) {
 
 	senderPrivateKey, err := config.GetUserPrivateKey()
 	if err != nil {
-		s.err = kserrors.MustBeLoggedIn(nil)
+		if errors.Is(err, config.ErrorNoPrivateKey) {
+			s.err = kserrors.MustBeLoggedIn(nil)
+		} else {
+			s.err = kserrors.CannotUnlockPrivateKey(err)
+		}
 		return currentUser, []byte{}
 	}
 
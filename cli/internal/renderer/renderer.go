@@ -0,0 +1,237 @@
+// Package renderer renders text templates with the secrets and files
+// of an environment, to produce configuration files that embed them
+package renderer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// Data struct is what templates are rendered with.
+// In templates, it is available as `.`: `{{ .Secrets.PORT }}`.
+type Data struct {
+	// Environment is the name of the environment
+	Environment string
+	// Secrets maps secret names to their value
+	Secrets map[string]string
+	// Files maps file paths to their contents
+	Files map[string]string
+}
+
+// MissingValueError is returned when a template uses a secret or a file
+// that does not exist, or a required value that is empty
+type MissingValueError struct {
+	Name string
+}
+
+func (e *MissingValueError) Error() string {
+	return fmt.Sprintf("%s has no value", e.Name)
+}
+
+// Render function renders `text` with `data`.
+// `name` identifies the template in error messages.
+// Referencing a secret that does not exist is an error, rather than
+// the `<no value>` text/template would write, unless its value goes
+// through `default` or `required`.
+func Render(name string, text string, data Data) (string, error) {
+	tmpl, err := template.New(name).
+		Option("missingkey=error").
+		Funcs(funcs(data)).
+		Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil {
+			lenientSecrets(t.Tree, t.Tree.Root)
+		}
+	}
+
+	var out bytes.Buffer
+	if err = tmpl.Execute(&out, data); err != nil {
+		var execError template.ExecError
+		if errors.As(err, &execError) {
+			if match := missingKeyRegex.FindStringSubmatch(err.Error()); match != nil {
+				return "", &MissingValueError{Name: match[1]}
+			}
+		}
+
+		return "", err
+	}
+
+	return out.String(), nil
+}
+
+// missingKeyRegex matches the error text/template returns for a missing
+// key, with `missingkey=error`
+var missingKeyRegex = regexp.MustCompile(`map has no entry for key "([^"]*)"`)
+
+// lenientFuncs handle empty values themselves, a secret that does not
+// exist is passed to them as an empty value
+var lenientFuncs = []string{"default", "required"}
+
+// lenientSecrets replaces, in the pipelines under `node`, the
+// `.Secrets.NAME` commands followed by one of the `lenientFuncs` with
+// `index .Secrets "NAME"`, which is empty for a missing secret
+func lenientSecrets(tree *parse.Tree, node parse.Node) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			lenientSecrets(tree, child)
+		}
+
+	case *parse.ActionNode:
+		lenientPipe(tree, n.Pipe)
+
+	case *parse.IfNode:
+		lenientPipe(tree, n.Pipe)
+		lenientSecrets(tree, n.List)
+		lenientSecrets(tree, n.ElseList)
+
+	case *parse.RangeNode:
+		lenientPipe(tree, n.Pipe)
+		lenientSecrets(tree, n.List)
+		lenientSecrets(tree, n.ElseList)
+
+	case *parse.WithNode:
+		lenientPipe(tree, n.Pipe)
+		lenientSecrets(tree, n.List)
+		lenientSecrets(tree, n.ElseList)
+
+	case *parse.TemplateNode:
+		lenientPipe(tree, n.Pipe)
+	}
+}
+
+func lenientPipe(tree *parse.Tree, pipe *parse.PipeNode) {
+	if pipe == nil {
+		return
+	}
+
+	for index, cmd := range pipe.Cmds {
+		for _, arg := range cmd.Args {
+			if p, ok := arg.(*parse.PipeNode); ok {
+				lenientPipe(tree, p)
+			}
+		}
+
+		if index+1 == len(pipe.Cmds) ||
+			!isLenientCall(pipe.Cmds[index+1]) ||
+			len(cmd.Args) != 1 {
+			continue
+		}
+
+		field, ok := cmd.Args[0].(*parse.FieldNode)
+		if !ok || len(field.Ident) != 2 || field.Ident[0] != "Secrets" {
+			continue
+		}
+
+		name := field.Ident[1]
+		field.Ident = field.Ident[:1]
+
+		cmd.Args = []parse.Node{
+			parse.NewIdentifier("index").SetTree(tree).SetPos(field.Pos),
+			field,
+			&parse.StringNode{
+				NodeType: parse.NodeString,
+				Pos:      field.Pos,
+				Quoted:   strconv.Quote(name),
+				Text:     name,
+			},
+		}
+	}
+}
+
+func isLenientCall(cmd *parse.CommandNode) bool {
+	if len(cmd.Args) == 0 {
+		return false
+	}
+
+	identifier, ok := cmd.Args[0].(*parse.IdentifierNode)
+	if !ok {
+		return false
+	}
+
+	for _, name := range lenientFuncs {
+		if identifier.Ident == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// funcs returns the helper functions available in templates
+func funcs(data Data) template.FuncMap {
+	return template.FuncMap{
+		// {{ secret "PORT" }}
+		"secret": func(name string) (string, error) {
+			value, ok := data.Secrets[name]
+			if !ok {
+				return "", &MissingValueError{Name: name}
+			}
+
+			return value, nil
+		},
+		// {{ file "certs/server.pem" }}
+		"file": func(path string) (string, error) {
+			contents, ok := data.Files[path]
+			if !ok {
+				return "", &MissingValueError{Name: path}
+			}
+
+			return contents, nil
+		},
+		// {{ env }} is the name of the environment
+		"env": func() string {
+			return data.Environment
+		},
+		// {{ .Secrets.API_KEY | required "API_KEY" }}
+		"required": func(name string, value string) (string, error) {
+			if value == "" {
+				return "", &MissingValueError{Name: name}
+			}
+
+			return value, nil
+		},
+		// {{ .Secrets.LOG_LEVEL | default "info" }}
+		"default": func(defaultValue string, value string) string {
+			if value == "" {
+				return defaultValue
+			}
+
+			return value
+		},
+		"base64": func(value string) string {
+			return base64.StdEncoding.EncodeToString([]byte(value))
+		},
+		// quote writes a double quoted string, escaped as in JSON,
+		// which YAML and most configuration formats also read
+		"quote": quote,
+	}
+}
+
+func quote(value string) (string, error) {
+	var out bytes.Buffer
+
+	encoder := json.NewEncoder(&out)
+	encoder.SetEscapeHTML(false)
+
+	if err := encoder.Encode(value); err != nil {
+		return "", err
+	}
+
+	return strings.TrimSuffix(out.String(), "\n"), nil
+}
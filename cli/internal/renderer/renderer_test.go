@@ -0,0 +1,89 @@
+package renderer
+
+import (
+	"errors"
+	"testing"
+)
+
+var data = Data{
+	Environment: "staging",
+	Secrets: map[string]string{
+		"PORT":     "3000",
+		"PASSWORD": `pa"ss\word`,
+		"EMPTY":    "",
+	},
+	Files: map[string]string{
+		"certs/ca.pem": "-----BEGIN CERTIFICATE-----\nabc\n-----END CERTIFICATE-----\n",
+	},
+}
+
+func TestRender(t *testing.T) {
+	cases := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{"fields", `{{ .Environment }}:{{ .Secrets.PORT }}`, "staging:3000"},
+		{"secret", `listen {{ secret "PORT" }};`, "listen 3000;"},
+		{"env", `{{ env }}`, "staging"},
+		{"quote", `{"password": {{ secret "PASSWORD" | quote }}}`, `{"password": "pa\"ss\\word"}`},
+		{"base64", `{{ secret "PORT" | base64 }}`, "MzAwMA=="},
+		{"default", `{{ .Secrets.EMPTY | default "info" }} {{ .Secrets.PORT | default "80" }}`, "info 3000"},
+		{"required", `{{ .Secrets.PORT | required "PORT" }}`, "3000"},
+		{"default undeclared", `{{ .Secrets.UNKNOWN | default "info" }}`, "info"},
+		{"default in if", `{{ if .Environment }}{{ .Secrets.UNKNOWN | default "info" }}{{ end }}`, "info"},
+		{"file", `{{ file "certs/ca.pem" | quote }}`, `"-----BEGIN CERTIFICATE-----\nabc\n-----END CERTIFICATE-----\n"`},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := Render(c.name, c.template, data)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got != c.want {
+				t.Errorf("expected %q, got %q", c.want, got)
+			}
+		})
+	}
+}
+
+func TestRenderMissingValues(t *testing.T) {
+	cases := []struct {
+		template string
+		missing  string
+	}{
+		{`{{ secret "UNKNOWN" }}`, "UNKNOWN"},
+		{`{{ file "missing.pem" }}`, "missing.pem"},
+		{`{{ .Secrets.EMPTY | required "EMPTY" }}`, "EMPTY"},
+		{`{{ .Secrets.UNKNOWN }}`, "UNKNOWN"},
+		{`{{ .Secrets.UNKNOWN | quote }}`, "UNKNOWN"},
+		{`{{ .Secrets.UNKNOWN | required "UNKNOWN" }}`, "UNKNOWN"},
+	}
+
+	for _, c := range cases {
+		_, err := Render("test", c.template, data)
+
+		var missingValue *MissingValueError
+		if !errors.As(err, &missingValue) {
+			t.Errorf("%s: expected a MissingValueError, got %v", c.template, err)
+			continue
+		}
+
+		if missingValue.Name != c.missing {
+			t.Errorf("%s: expected %s to be missing, got %s", c.template, c.missing, missingValue.Name)
+		}
+	}
+}
+
+func TestRenderErrors(t *testing.T) {
+	for _, template := range []string{
+		`{{ .Secrets.PORT `,
+		`{{ unknownFunction }}`,
+	} {
+		if _, err := Render("test", template, data); err == nil {
+			t.Errorf("rendering %q should fail", template)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package runner
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+)
+
+// Run function executes `command` as a child process, with `env` as its
+// environment.
+// Standard input and outputs are inherited, and every signal received
+// by the current process is forwarded to the child.
+// Returns the exit code of the child process.
+func Run(command []string, env []string) (exitCode int, err error) {
+	/* #nosec
+	 * Running an arbitrary command is the whole point
+	 */
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Env = env
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err = cmd.Start(); err != nil {
+		return 1, err
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals)
+
+	go forwardSignals(cmd.Process, signals)
+
+	err = cmd.Wait()
+
+	// No signal must be sent on the channel once it is closed
+	signal.Stop(signals)
+	close(signals)
+
+	return exitCodeFromError(err)
+}
+
+// forwardSignals sends every signal received on `signals` to `process`
+// until the channel is closed
+func forwardSignals(process *os.Process, signals chan os.Signal) {
+	for sig := range signals {
+		if !shouldForward(sig) {
+			continue
+		}
+
+		// The process may already be gone, nothing to do about it
+		_ = process.Signal(sig)
+	}
+}
+
+// exitCodeFromError returns the exit code of a process from the error
+// returned by `cmd.Wait()`.
+// A process killed by a signal exits with 128 + the signal number,
+// the same way shells report it.
+func exitCodeFromError(err error) (int, error) {
+	if err == nil {
+		return 0, nil
+	}
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return 1, err
+	}
+
+	if status, ok := exitErr.Sys().(syscall.WaitStatus); ok &&
+		status.Signaled() {
+		return 128 + int(status.Signal()), nil
+	}
+
+	return exitErr.ExitCode(), nil
+}
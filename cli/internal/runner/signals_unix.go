@@ -0,0 +1,15 @@
+// +build !windows
+
+package runner
+
+import (
+	"os"
+	"syscall"
+)
+
+// shouldForward function tells whether `sig` must be sent to the child.
+// SIGCHLD is about our own child, and SIGURG is used internally by the Go
+// runtime, neither is meant for the child process.
+func shouldForward(sig os.Signal) bool {
+	return sig != syscall.SIGCHLD && sig != syscall.SIGURG
+}
@@ -0,0 +1,10 @@
+// +build windows
+
+package runner
+
+import "os"
+
+// shouldForward function tells whether `sig` must be sent to the child.
+func shouldForward(_ os.Signal) bool {
+	return true
+}
@@ -0,0 +1,133 @@
+// Package scanner looks for secret values in texts, so that they are not
+// committed by mistake
+package scanner
+
+import (
+	"bytes"
+	"math"
+	"sort"
+	"strings"
+)
+
+const (
+	// DefaultEntropyThreshold is the entropy, in bits per character, under
+	// which values are too common to be reported
+	DefaultEntropyThreshold = 3.0
+	// MinLength is the length under which values are too likely to appear
+	// by chance to be reported
+	MinLength = 8
+)
+
+// Needle is a value to look for, with the name it is reported with
+type Needle struct {
+	Name  string
+	Value string
+}
+
+// Leak is a line where a value was found.
+// It never holds the value itself.
+type Leak struct {
+	Path string `json:"path"`
+	Line int    `json:"line"`
+	Name string `json:"name"`
+}
+
+// Scanner finds needles in texts
+type Scanner struct {
+	needles []Needle
+}
+
+// New function returns a scanner for the `needles` whose entropy is at
+// least `threshold`.
+// Values are looked for line by line: multi-line values, such as file
+// contents, are split, and each of their lines is a needle.
+func New(needles []Needle, threshold float64) *Scanner {
+	s := &Scanner{needles: make([]Needle, 0)}
+	seen := make(map[Needle]bool)
+
+	for _, needle := range needles {
+		for _, line := range strings.Split(needle.Value, "\n") {
+			line = strings.TrimSpace(line)
+
+			if len(line) < MinLength || Entropy(line) < threshold {
+				continue
+			}
+
+			candidate := Needle{Name: needle.Name, Value: line}
+			if !seen[candidate] {
+				seen[candidate] = true
+				s.needles = append(s.needles, candidate)
+			}
+		}
+	}
+
+	return s
+}
+
+// Len method returns the number of values the scanner looks for
+func (s *Scanner) Len() int {
+	return len(s.needles)
+}
+
+// Scan method returns the lines of `content` containing a needle,
+// reported as coming from `filePath`.
+// A line containing several values of the same name is reported once.
+func (s *Scanner) Scan(filePath string, content []byte) []Leak {
+	leaks := make([]Leak, 0)
+
+	for index, line := range bytes.Split(content, []byte("\n")) {
+		names := make([]string, 0)
+
+		for _, needle := range s.needles {
+			if !bytes.Contains(line, []byte(needle.Value)) ||
+				containsString(names, needle.Name) {
+				continue
+			}
+
+			names = append(names, needle.Name)
+		}
+
+		sort.Strings(names)
+
+		for _, name := range names {
+			leaks = append(leaks, Leak{
+				Path: filePath,
+				Line: index + 1,
+				Name: name,
+			})
+		}
+	}
+
+	return leaks
+}
+
+// Entropy function returns the Shannon entropy of `value`, in bits per
+// character.
+// Random tokens score high, words and small numbers score low.
+func Entropy(value string) float64 {
+	counts := make(map[rune]int)
+	total := 0
+
+	for _, r := range value {
+		counts[r]++
+		total++
+	}
+
+	entropy := 0.0
+	for _, count := range counts {
+		frequency := float64(count) / float64(total)
+		entropy -= frequency * math.Log2(frequency)
+	}
+
+	return entropy
+}
+
+func containsString(slice []string, value string) bool {
+	for _, s := range slice {
+		if s == value {
+			return true
+		}
+	}
+
+	return false
+}
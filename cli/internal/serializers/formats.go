@@ -0,0 +1,228 @@
+package serializers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/wearedevx/keystone/cli/internal/utils"
+	"gopkg.in/yaml.v2"
+)
+
+// shellSerializer writes `export KEY='value'` lines,
+// meant to be evaluated by a POSIX shell.
+// Nothing is special between single quotes, a single quote is written
+// by closing the quotes, escaping it, and opening them again.
+type shellSerializer struct{}
+
+func (shellSerializer) Serialize(variables []Variable) (string, error) {
+	var sb strings.Builder
+
+	for _, variable := range variables {
+		if err := rejectNUL(variable, FormatShell); err != nil {
+			return "", err
+		}
+
+		sb.WriteString(fmt.Sprintf(
+			"export %s='%s'\n",
+			variable.Name,
+			strings.ReplaceAll(variable.Value, "'", `'\''`),
+		))
+	}
+
+	return sb.String(), nil
+}
+
+// fishSerializer writes `set -gx KEY 'value'` lines, for the fish shell.
+// Between single quotes, fish only unescapes `\\` and `\'`.
+type fishSerializer struct{}
+
+func (fishSerializer) Serialize(variables []Variable) (string, error) {
+	var sb strings.Builder
+
+	for _, variable := range variables {
+		if err := rejectNUL(variable, FormatFish); err != nil {
+			return "", err
+		}
+
+		sb.WriteString(fmt.Sprintf(
+			"set -gx %s '%s'\n",
+			variable.Name,
+			escape(variable.Value, `\'`),
+		))
+	}
+
+	return sb.String(), nil
+}
+
+// powershellSerializer writes `$env:KEY = 'value'` lines.
+// Single quoted strings are verbatim in PowerShell, quotes are doubled.
+// It also takes typographic single quotes for quotes.
+type powershellSerializer struct{}
+
+var powershellQuoteReplacer = strings.NewReplacer(
+	"'", "''",
+	"\u2018", "\u2018\u2018",
+	"\u2019", "\u2019\u2019",
+	"\u201a", "\u201a\u201a",
+	"\u201b", "\u201b\u201b",
+)
+
+func (powershellSerializer) Serialize(variables []Variable) (string, error) {
+	var sb strings.Builder
+
+	for _, variable := range variables {
+		if err := rejectNUL(variable, FormatPowershell); err != nil {
+			return "", err
+		}
+
+		sb.WriteString(fmt.Sprintf(
+			"$env:%s = '%s'\n",
+			variable.Name,
+			powershellQuoteReplacer.Replace(variable.Value),
+		))
+	}
+
+	return sb.String(), nil
+}
+
+// dotEnvSerializer writes `KEY="value"` lines, using the same escaping
+// as the .env files in the keystone cache
+type dotEnvSerializer struct{}
+
+func (dotEnvSerializer) Serialize(variables []Variable) (string, error) {
+	var sb strings.Builder
+
+	for _, variable := range variables {
+		if err := rejectNUL(variable, FormatDotEnv); err != nil {
+			return "", err
+		}
+
+		sb.WriteString(fmt.Sprintf(
+			"%s=\"%s\"\n",
+			variable.Name,
+			utils.DoubleQuoteEscape(variable.Value),
+		))
+	}
+
+	return sb.String(), nil
+}
+
+// jsonSerializer writes a single JSON object
+type jsonSerializer struct{}
+
+func (jsonSerializer) Serialize(variables []Variable) (string, error) {
+	var sb strings.Builder
+
+	sb.WriteString("{")
+
+	for index, variable := range variables {
+		// encoding/json would silently replace invalid bytes
+		if !utf8.ValidString(variable.Value) {
+			return "", cannotSerialize(
+				variable,
+				FormatJSON,
+				"JSON strings cannot hold binary values",
+			)
+		}
+
+		name, err := json.Marshal(variable.Name)
+		if err != nil {
+			return "", err
+		}
+
+		value, err := json.Marshal(variable.Value)
+		if err != nil {
+			return "", err
+		}
+
+		if index > 0 {
+			sb.WriteString(",")
+		}
+
+		sb.WriteString(fmt.Sprintf("\n  %s: %s", name, value))
+	}
+
+	if len(variables) > 0 {
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("}\n")
+
+	return sb.String(), nil
+}
+
+// yamlSerializer writes a YAML mapping.
+// Binary values are written as !!binary, in base64.
+type yamlSerializer struct{}
+
+func (yamlSerializer) Serialize(variables []Variable) (string, error) {
+	mapSlice := make(yaml.MapSlice, 0, len(variables))
+
+	for _, variable := range variables {
+		mapSlice = append(mapSlice, yaml.MapItem{
+			Key:   variable.Name,
+			Value: variable.Value,
+		})
+	}
+
+	out, err := yaml.Marshal(mapSlice)
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
+
+// dockerSerializer writes a file suitable for `docker run --env-file`.
+// Docker takes everything after the `=` verbatim, there is no quoting and
+// no escaping, so values spanning several lines cannot be represented.
+type dockerSerializer struct{}
+
+func (dockerSerializer) Serialize(variables []Variable) (string, error) {
+	var sb strings.Builder
+
+	for _, variable := range variables {
+		if err := rejectNUL(variable, FormatDocker); err != nil {
+			return "", err
+		}
+
+		if strings.ContainsAny(variable.Value, "\r\n") {
+			return "", cannotSerialize(
+				variable,
+				FormatDocker,
+				"docker env-files do not support multi-line values",
+			)
+		}
+
+		sb.WriteString(fmt.Sprintf("%s=%s\n", variable.Name, variable.Value))
+	}
+
+	return sb.String(), nil
+}
+
+// systemdSerializer writes a file suitable for systemd’s `EnvironmentFile=`.
+// Values are double quoted, in which systemd only unescapes `\`, `"`, `$`
+// and backquotes. Newlines are kept as is, systemd allows them between quotes.
+type systemdSerializer struct{}
+
+const systemdSpecialChars = "\\\"$`"
+
+func (systemdSerializer) Serialize(variables []Variable) (string, error) {
+	var sb strings.Builder
+
+	for _, variable := range variables {
+		if err := rejectNUL(variable, FormatSystemd); err != nil {
+			return "", err
+		}
+
+		sb.WriteString(fmt.Sprintf(
+			"%s=\"%s\"\n",
+			variable.Name,
+			escape(variable.Value, systemdSpecialChars),
+		))
+	}
+
+	return sb.String(), nil
+}
@@ -0,0 +1,118 @@
+package serializers
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// A Variable is a secret name and its value,
+// as it should appear in the serialized output
+type Variable struct {
+	Name  string
+	Value string
+}
+
+// Serializer is implemented by every output format supported by
+// `ks source`.
+type Serializer interface {
+	// Serialize turns the variables into the format's text representation.
+	// Variables must be written in the order they are given.
+	Serialize(variables []Variable) (string, error)
+}
+
+// ErrorCannotSerialize is returned when a value cannot be represented
+// in a format (e.g. a multi-line value in a docker env-file)
+var ErrorCannotSerialize = errors.New("value cannot be serialized")
+
+const (
+	FormatShell      = "shell"
+	FormatFish       = "fish"
+	FormatPowershell = "powershell"
+	FormatDotEnv     = "dotenv"
+	FormatJSON       = "json"
+	FormatYAML       = "yaml"
+	FormatDocker     = "docker"
+	FormatSystemd    = "systemd"
+)
+
+var serializers = map[string]Serializer{
+	FormatShell:      shellSerializer{},
+	FormatFish:       fishSerializer{},
+	FormatPowershell: powershellSerializer{},
+	FormatDotEnv:     dotEnvSerializer{},
+	FormatJSON:       jsonSerializer{},
+	FormatYAML:       yamlSerializer{},
+	FormatDocker:     dockerSerializer{},
+	FormatSystemd:    systemdSerializer{},
+}
+
+// Register function makes a serializer available under `format`.
+// It replaces any serializer previously registered for that format.
+func Register(format string, serializer Serializer) {
+	serializers[format] = serializer
+}
+
+// Get function returns the serializer for `format`.
+// The second returned value is false if there is no such format.
+func Get(format string) (Serializer, bool) {
+	serializer, ok := serializers[format]
+
+	return serializer, ok
+}
+
+// Formats function returns the names of all the available formats
+func Formats() []string {
+	formats := make([]string, 0, len(serializers))
+
+	for format := range serializers {
+		formats = append(formats, format)
+	}
+
+	sort.Strings(formats)
+
+	return formats
+}
+
+// cannotSerialize returns an error explaining why the value of
+// `variable` cannot be written in `format`
+func cannotSerialize(variable Variable, format, reason string) error {
+	return fmt.Errorf(
+		"%w: %s cannot be written in the %s format, %s",
+		ErrorCannotSerialize,
+		variable.Name,
+		format,
+		reason,
+	)
+}
+
+// rejectNUL returns an error if the value of `variable` contains a NUL
+// byte, which environment variables and text files cannot hold
+func rejectNUL(variable Variable, format string) error {
+	if strings.ContainsRune(variable.Value, 0) {
+		return cannotSerialize(
+			variable,
+			format,
+			"environment variables cannot hold NUL bytes",
+		)
+	}
+
+	return nil
+}
+
+// escape function prefixes every character of `specialChars` found in
+// `value` with a backslash
+func escape(value string, specialChars string) string {
+	var sb strings.Builder
+
+	for _, c := range value {
+		if strings.ContainsRune(specialChars, c) {
+			sb.WriteRune('\\')
+		}
+
+		sb.WriteRune(c)
+	}
+
+	return sb.String()
+}
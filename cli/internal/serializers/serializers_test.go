@@ -0,0 +1,255 @@
+package serializers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/wearedevx/keystone/cli/internal/envfile"
+	"gopkg.in/yaml.v2"
+)
+
+var variables = []Variable{
+	{Name: "SIMPLE", Value: "value"},
+	{Name: "EMPTY", Value: ""},
+	{Name: "WITH_SPACES", Value: "a value with spaces"},
+	{Name: "WITH_DOLLAR", Value: "pa$$word and $HOME and ${HOME}"},
+	{Name: "WITH_QUOTES", Value: `say "hello" to 'them'`},
+	{Name: "WITH_BACKQUOTES", Value: "`whoami`"},
+	{Name: "WITH_BACKSLASHES", Value: `C:\Users\n\path`},
+	{Name: "WITH_BANG", Value: "hello!"},
+	{Name: "WITH_EQUAL", Value: "a=b"},
+	{Name: "WITH_UNICODE", Value: "héhé ✓"},
+}
+
+var multiLineVariables = []Variable{
+	{Name: "MULTI_LINE", Value: "first line\nsecond line\n  indented"},
+	{Name: "PEM", Value: "-----BEGIN KEY-----\nABC$def\n-----END KEY-----\n"},
+}
+
+// The envfile parser normalizes raw CRLF line breaks, only formats
+// escaping them keep them
+var crlfVariable = Variable{Name: "CRLF", Value: "first line\r\nsecond line\r\n"}
+
+var nulVariable = Variable{Name: "WITH_NUL", Value: "a\x00b"}
+
+func allVariables() []Variable {
+	return append(append([]Variable{}, variables...), multiLineVariables...)
+}
+
+func allVariablesWithCRLF() []Variable {
+	return append(allVariables(), crlfVariable)
+}
+
+func serialize(t *testing.T, format string, vars []Variable) string {
+	serializer, ok := Get(format)
+	if !ok {
+		t.Fatalf("no serializer for format %s", format)
+	}
+
+	out, err := serializer.Serialize(vars)
+	if err != nil {
+		t.Fatalf("failed to serialize as %s: %v", format, err)
+	}
+
+	return out
+}
+
+func expectSameValues(t *testing.T, vars []Variable, parsed map[string]string) {
+	if len(parsed) != len(vars) {
+		t.Errorf("expected %d variables, got %d", len(vars), len(parsed))
+	}
+
+	for _, variable := range vars {
+		value, ok := parsed[variable.Name]
+		if !ok {
+			t.Errorf("%s is missing", variable.Name)
+			continue
+		}
+
+		if value != variable.Value {
+			t.Errorf(
+				"%s: expected %q, got %q",
+				variable.Name,
+				variable.Value,
+				value,
+			)
+		}
+	}
+}
+
+func TestRoundTripWithEnvFileParser(t *testing.T) {
+	for _, format := range []string{FormatDotEnv, FormatSystemd} {
+		format := format
+
+		t.Run(format, func(t *testing.T) {
+			vars := allVariables()
+			if format == FormatDotEnv {
+				vars = allVariablesWithCRLF()
+			}
+
+			out := serialize(t, format, vars)
+
+			parsed, err := envfile.UnmarshalBytes(
+				[]byte(out),
+				envfile.DefaultLoadOptions(),
+			)
+			if err != nil {
+				t.Fatalf("failed to parse:\n%s\n%v", out, err)
+			}
+
+			expectSameValues(t, vars, parsed)
+		})
+	}
+}
+
+// evalInShell runs the serialized variables in `shell`, and reads their
+// values back
+func evalInShell(
+	t *testing.T,
+	format string,
+	shell string,
+	printValue string,
+	vars []Variable,
+) {
+	bin, err := exec.LookPath(shell)
+	if err != nil {
+		t.Skipf("%s is not installed", shell)
+	}
+
+	script := serialize(t, format, vars)
+	for _, variable := range vars {
+		script += fmt.Sprintf(printValue, variable.Name)
+	}
+
+	out, err := exec.Command(bin, "-c", script).Output()
+	if err != nil {
+		t.Fatalf("failed to evaluate:\n%s\n%v", script, err)
+	}
+
+	values := strings.Split(string(out), "\x1f")
+	parsed := map[string]string{}
+	for i, variable := range vars {
+		parsed[variable.Name] = values[i]
+	}
+
+	expectSameValues(t, vars, parsed)
+}
+
+func TestRoundTripShell(t *testing.T) {
+	evalInShell(t, FormatShell, "sh", "printf '%%s\\037' \"$%s\"\n", allVariablesWithCRLF())
+}
+
+func TestRoundTripFish(t *testing.T) {
+	evalInShell(t, FormatFish, "fish", "printf '%%s\\037' \"$%s\"\n", allVariablesWithCRLF())
+}
+
+func TestRoundTripPowershell(t *testing.T) {
+	vars := append(allVariablesWithCRLF(), Variable{Name: "TYPOGRAPHIC", Value: "it\u2019s"})
+
+	evalInShell(
+		t,
+		FormatPowershell,
+		"pwsh",
+		"[Console]::Out.Write($env:%s + [char]0x1f)\n",
+		vars,
+	)
+}
+
+func TestNULIsRejected(t *testing.T) {
+	for _, format := range Formats() {
+		if format == FormatYAML || format == FormatJSON {
+			continue
+		}
+
+		serializer, _ := Get(format)
+
+		_, err := serializer.Serialize([]Variable{nulVariable})
+		if !errors.Is(err, ErrorCannotSerialize) {
+			t.Errorf("%s: expected NUL to be rejected, got %v", format, err)
+		}
+	}
+}
+
+func TestRoundTripJSON(t *testing.T) {
+	out := serialize(t, FormatJSON, allVariablesWithCRLF())
+	parsed := map[string]string{}
+
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("failed to parse:\n%s\n%v", out, err)
+	}
+
+	expectSameValues(t, allVariablesWithCRLF(), parsed)
+
+	if empty := serialize(t, FormatJSON, []Variable{}); empty != "{}\n" {
+		t.Errorf("expected an empty object, got %q", empty)
+	}
+
+	serializer, _ := Get(FormatJSON)
+	binary := Variable{Name: "BINARY", Value: "\xff\xfe"}
+	if _, err := serializer.Serialize([]Variable{binary}); !errors.Is(err, ErrorCannotSerialize) {
+		t.Errorf("expected binary values to be rejected, got %v", err)
+	}
+}
+
+func TestRoundTripYAML(t *testing.T) {
+	vars := append(
+		allVariablesWithCRLF(),
+		nulVariable,
+		Variable{Name: "BINARY", Value: "\xff\xfe\x01"},
+	)
+	out := serialize(t, FormatYAML, vars)
+	parsed := map[string]string{}
+
+	if err := yaml.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("failed to parse:\n%s\n%v", out, err)
+	}
+
+	expectSameValues(t, vars, parsed)
+}
+
+func TestRoundTripDocker(t *testing.T) {
+	out := serialize(t, FormatDocker, variables)
+	parsed := map[string]string{}
+
+	// Docker reads the file line by line,
+	// and splits at the first equal sign
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+		parts := strings.SplitN(line, "=", 2)
+		parsed[parts[0]] = parts[1]
+	}
+
+	expectSameValues(t, variables, parsed)
+
+	for _, variable := range multiLineVariables {
+		serializer, _ := Get(FormatDocker)
+
+		_, err := serializer.Serialize([]Variable{variable})
+		if !errors.Is(err, ErrorCannotSerialize) {
+			t.Errorf("expected %s to be rejected, got %v", variable.Name, err)
+		}
+	}
+}
+
+func TestOrderIsPreserved(t *testing.T) {
+	vars := []Variable{
+		{Name: "B", Value: "1"},
+		{Name: "A", Value: "2"},
+		{Name: "C", Value: "3"},
+	}
+
+	for _, format := range Formats() {
+		out := serialize(t, format, vars)
+
+		b := strings.Index(out, "B")
+		a := strings.Index(out, "A")
+		c := strings.Index(out, "C")
+
+		if !(b < a && a < c) {
+			t.Errorf("%s: order not preserved:\n%s", format, out)
+		}
+	}
+}
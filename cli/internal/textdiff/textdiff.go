@@ -0,0 +1,300 @@
+// Package textdiff compares texts line by line, and writes the result
+// as a unified diff, like `diff -u` does
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+type opKind int
+
+const (
+	opEqual opKind = iota
+	opDelete
+	opInsert
+)
+
+// op is a line kept, removed from `a` or inserted from `b`
+type op struct {
+	kind opKind
+	line string
+	// line numbers in `a` and `b`, starting at 0
+	a, b int
+}
+
+// maxEditDistance is the largest number of lines added or removed that
+// Unified compares line by line.
+// Finding the changes takes memory in the square of their number.
+const maxEditDistance = 2000
+
+// Unified function returns the unified diff turning `a` into `b`,
+// with `context` unchanged lines around each change.
+// `nameA` and `nameB` are the names of the texts in the header.
+// It is empty when the texts are the same.
+// `ok` is false when the texts differ by too many lines to be compared.
+func Unified(
+	nameA, nameB string,
+	a, b string,
+	context int,
+) (diff string, ok bool) {
+	ops, ok := diffLines(splitLines(a), splitLines(b))
+	if !ok {
+		return "", false
+	}
+
+	var out strings.Builder
+
+	for _, hunk := range hunks(ops, context) {
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- %s\n+++ %s\n", nameA, nameB)
+		}
+
+		writeHunk(&out, hunk)
+	}
+
+	return out.String(), true
+}
+
+// splitLines splits `text` after each line feed, so that a missing line
+// feed at the end makes the last line different
+func splitLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// diffLines returns the shortest edit script from `a` to `b`.
+// The lines `a` and `b` start and end with are left out of the search.
+// `ok` is false when more than `maxEditDistance` lines differ.
+func diffLines(a, b []string) (ops []op, ok bool) {
+	n, m := len(a), len(b)
+
+	prefix := 0
+	for prefix < n && prefix < m && a[prefix] == b[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < n-prefix && suffix < m-prefix &&
+		a[n-1-suffix] == b[m-1-suffix] {
+		suffix++
+	}
+
+	changes, ok := editScript(a[prefix:n-suffix], b[prefix:m-suffix])
+	if !ok {
+		return nil, false
+	}
+
+	ops = make([]op, 0, prefix+len(changes)+suffix)
+
+	for i := 0; i < prefix; i++ {
+		ops = append(ops, op{opEqual, a[i], i, i})
+	}
+
+	for _, o := range changes {
+		o.a += prefix
+		o.b += prefix
+		ops = append(ops, o)
+	}
+
+	for i := suffix; i > 0; i-- {
+		ops = append(ops, op{opEqual, a[n-i], n - i, m - i})
+	}
+
+	return ops, true
+}
+
+// editScript returns the shortest edit script from `a` to `b`,
+// using the algorithm of Eugene W. Myers.
+// `ok` is false when more than `maxEditDistance` lines differ.
+func editScript(a, b []string) ([]op, bool) {
+	n, m := len(a), len(b)
+	if n+m == 0 {
+		return nil, true
+	}
+
+	limit := min(n+m, maxEditDistance)
+	offset := limit + 1
+
+	// v[offset+k] is the furthest x reached on the diagonal k
+	v := make([]int, 2*offset+1)
+	// trace[d][d+k] is v[offset+k] before the step d.
+	// Only the diagonals -d to d are read when walking back the step d.
+	trace := make([][]int, 0)
+
+	for d := 0; d <= limit; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+
+			v[offset+k] = x
+
+			if x >= n && y >= m {
+				return walkBack(a, b, trace), true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// walkBack builds the edit script from `a` to `b` by walking the `trace`
+// of editScript back from the end
+func walkBack(a, b []string, trace [][]int) []op {
+	ops := make([]op, 0, len(a)+len(b))
+	x, y := len(a), len(b)
+
+	for d := len(trace) - 1; d > 0; d-- {
+		v := trace[d]
+		k := x - y
+
+		var previousK int
+		if k == -d || (k != d && v[d+k-1] < v[d+k+1]) {
+			previousK = k + 1
+		} else {
+			previousK = k - 1
+		}
+
+		previousX := v[d+previousK]
+		previousY := previousX - previousK
+
+		for x > previousX && y > previousY {
+			x--
+			y--
+			ops = append(ops, op{opEqual, a[x], x, y})
+		}
+
+		if x == previousX {
+			y--
+			ops = append(ops, op{opInsert, b[y], x, y})
+		} else {
+			x--
+			ops = append(ops, op{opDelete, a[x], x, y})
+		}
+	}
+
+	for x > 0 && y > 0 {
+		x--
+		y--
+		ops = append(ops, op{opEqual, a[x], x, y})
+	}
+
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+
+	return ops
+}
+
+// hunks groups the changes with their context.
+// Changes separated by at most twice the context share a hunk.
+func hunks(ops []op, context int) [][]op {
+	result := make([][]op, 0)
+	start, end := -1, -1
+
+	for i, o := range ops {
+		if o.kind == opEqual {
+			continue
+		}
+
+		if start >= 0 && i-context <= end+1 {
+			end = i + context
+			continue
+		}
+
+		if start >= 0 {
+			result = append(result, ops[start:min(end+1, len(ops))])
+		}
+
+		start = max(i-context, 0)
+		end = i + context
+	}
+
+	if start >= 0 {
+		result = append(result, ops[start:min(end+1, len(ops))])
+	}
+
+	return result
+}
+
+func writeHunk(out *strings.Builder, hunk []op) {
+	var countA, countB int
+
+	for _, o := range hunk {
+		if o.kind != opInsert {
+			countA++
+		}
+		if o.kind != opDelete {
+			countB++
+		}
+	}
+
+	fmt.Fprintf(
+		out,
+		"@@ -%s +%s @@\n",
+		hunkRange(hunk[0].a, countA),
+		hunkRange(hunk[0].b, countB),
+	)
+
+	for _, o := range hunk {
+		prefix := " "
+		switch o.kind {
+		case opDelete:
+			prefix = "-"
+		case opInsert:
+			prefix = "+"
+		}
+
+		out.WriteString(prefix + o.line)
+
+		if !strings.HasSuffix(o.line, "\n") {
+			out.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+// hunkRange formats the lines of a hunk in one of the texts:
+// an empty range starts at the line before it
+func hunkRange(start, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	default:
+		return fmt.Sprintf("%d,%d", start+1, count)
+	}
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
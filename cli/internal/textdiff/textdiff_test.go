@@ -0,0 +1,109 @@
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func numberedLines(from, to int) string {
+	var sb strings.Builder
+	for i := from; i <= to; i++ {
+		fmt.Fprintf(&sb, "line %d\n", i)
+	}
+
+	return sb.String()
+}
+
+func TestUnified(t *testing.T) {
+	cases := []struct {
+		name     string
+		a, b     string
+		expected string
+	}{
+		{
+			"same texts",
+			"a\nb\n", "a\nb\n",
+			"",
+		},
+		{
+			"change",
+			"host: localhost\nport: 3000\nuser: admin\n",
+			"host: localhost\nport: 4000\nuser: admin\n",
+			"--- a\n+++ b\n@@ -1,3 +1,3 @@\n host: localhost\n-port: 3000\n+port: 4000\n user: admin\n",
+		},
+		{
+			"from empty",
+			"", "one\ntwo\n",
+			"--- a\n+++ b\n@@ -0,0 +1,2 @@\n+one\n+two\n",
+		},
+		{
+			"to empty",
+			"one\n", "",
+			"--- a\n+++ b\n@@ -1 +0,0 @@\n-one\n",
+		},
+		{
+			"missing newline",
+			"a\nb\n", "a\nb",
+			"--- a\n+++ b\n@@ -1,2 +1,2 @@\n a\n-b\n+b\n\\ No newline at end of file\n",
+		},
+		{
+			"separate hunks",
+			numberedLines(1, 20),
+			strings.Replace(
+				strings.Replace(numberedLines(1, 20), "line 2\n", "line two\n", 1),
+				"line 18\n", "", 1,
+			),
+			"--- a\n+++ b\n" +
+				"@@ -1,5 +1,5 @@\n line 1\n-line 2\n+line two\n line 3\n line 4\n line 5\n" +
+				"@@ -15,6 +15,5 @@\n line 15\n line 16\n line 17\n-line 18\n line 19\n line 20\n",
+		},
+		{
+			"close changes share a hunk",
+			numberedLines(1, 10),
+			strings.Replace(
+				strings.Replace(numberedLines(1, 10), "line 2\n", "", 1),
+				"line 9\n", "", 1,
+			),
+			"--- a\n+++ b\n" +
+				"@@ -1,10 +1,8 @@\n line 1\n-line 2\n line 3\n line 4\n line 5\n line 6\n line 7\n line 8\n-line 9\n line 10\n",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, ok := Unified("a", "b", c.a, c.b, 3)
+			if !ok {
+				t.Fatal("texts should be compared")
+			}
+
+			if got != c.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", c.expected, got)
+			}
+		})
+	}
+}
+
+func TestUnifiedLargeTexts(t *testing.T) {
+	// Few changes in a large text
+	a := numberedLines(1, 100000)
+	b := strings.Replace(a, "line 50000\n", "line fifty thousand\n", 1)
+
+	got, ok := Unified("a", "b", a, b, 1)
+	if !ok {
+		t.Fatal("texts with few changes should be compared")
+	}
+
+	expected := "--- a\n+++ b\n" +
+		"@@ -49999,3 +49999,3 @@\n line 49999\n-line 50000\n+line fifty thousand\n line 50001\n"
+	if got != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+
+	// Rewritten text
+	b = strings.ReplaceAll(numberedLines(1, maxEditDistance), "line", "row")
+
+	if _, ok = Unified("a", "b", a, b, 3); ok {
+		t.Error("texts differing by too many lines should not be compared")
+	}
+}
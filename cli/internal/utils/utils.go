@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"unicode/utf8"
 )
 
 // fileExists checks if a file exists and is not a directory before we
@@ -179,3 +180,22 @@ func DoubleQuoteEscape(line string) string {
 	}
 	return line
 }
+
+// IsBinary function returns true if `value` cannot be written as text:
+// it is not valid UTF-8, or it contains control characters other than
+// tabs and line breaks
+func IsBinary(value string) bool {
+	if !utf8.ValidString(value) {
+		return true
+	}
+
+	for _, r := range value {
+		switch {
+		case r == '\t', r == '\n', r == '\r':
+		case r < 0x20, r == 0x7f:
+			return true
+		}
+	}
+
+	return false
+}
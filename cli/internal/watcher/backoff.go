@@ -0,0 +1,41 @@
+// Package watcher holds the pieces of `ks watch` that do not depend on a
+// project: polling delays, pidfiles, notifications and detaching from the
+// terminal.
+package watcher
+
+import "time"
+
+// Backoff struct computes the delay before the next poll.
+// It starts at Min, doubles after every failure, up to Max,
+// and goes back to Min after a success.
+type Backoff struct {
+	Min   time.Duration
+	Max   time.Duration
+	delay time.Duration
+}
+
+// NewBackoff function returns a Backoff between `min` and `max`
+func NewBackoff(min, max time.Duration) *Backoff {
+	if max < min {
+		max = min
+	}
+
+	return &Backoff{Min: min, Max: max}
+}
+
+// Next method returns the delay to wait before the next poll,
+// given whether the last one failed
+func (b *Backoff) Next(failed bool) time.Duration {
+	switch {
+	case !failed || b.delay == 0:
+		b.delay = b.Min
+	default:
+		b.delay *= 2
+	}
+
+	if b.delay > b.Max {
+		b.delay = b.Max
+	}
+
+	return b.delay
+}
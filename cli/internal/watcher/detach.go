@@ -0,0 +1,42 @@
+package watcher
+
+import (
+	"os"
+	"os/exec"
+)
+
+// Detach function starts the current executable again with `args`,
+// in the background, with its output appended to `logFile`.
+// It returns the pid of the new process.
+func Detach(args []string, logFile string) (pid int, err error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return 0, err
+	}
+
+	/* #nosec
+	 * The log file path is given by the user
+	 */
+	out, err := os.OpenFile(logFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return 0, err
+	}
+	defer out.Close()
+
+	/* #nosec
+	 * We run ourselves
+	 */
+	cmd := exec.Command(executable, args...)
+	cmd.Stdout = out
+	cmd.Stderr = out
+	detachedAttributes(cmd)
+
+	if err = cmd.Start(); err != nil {
+		return 0, err
+	}
+
+	pid = cmd.Process.Pid
+	err = cmd.Process.Release()
+
+	return pid, err
+}
@@ -0,0 +1,31 @@
+package watcher
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// Notifier struct writes one timestamped line per event,
+// so that the output can be read by anything: a terminal,
+// journald, or a log file followed by a desktop notifier.
+type Notifier struct {
+	out io.Writer
+	now func() time.Time
+}
+
+// NewNotifier function returns a Notifier writing to `out`
+func NewNotifier(out io.Writer) *Notifier {
+	return &Notifier{out: out, now: time.Now}
+}
+
+// Notify method writes an event about `project`
+func (n *Notifier) Notify(project string, format string, a ...interface{}) {
+	fmt.Fprintf(
+		n.out,
+		"%s [%s] %s\n",
+		n.now().Format(time.RFC3339),
+		project,
+		fmt.Sprintf(format, a...),
+	)
+}
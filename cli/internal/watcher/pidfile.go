@@ -0,0 +1,46 @@
+package watcher
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ErrorAlreadyRunning is returned when the pidfile belongs to a process
+// that is still running
+var ErrorAlreadyRunning = errors.New("already running")
+
+// WritePidFile function writes the pid of the current process to `path`.
+// It fails if the file belongs to another process that is still running.
+func WritePidFile(path string) error {
+	/* #nosec
+	 * The path is given by the user
+	 */
+	if contents, err := ioutil.ReadFile(path); err == nil {
+		pid, err := strconv.Atoi(strings.TrimSpace(string(contents)))
+		if err == nil && pid != os.Getpid() && processIsRunning(pid) {
+			return fmt.Errorf("%w with pid %d (%s)", ErrorAlreadyRunning, pid, path)
+		}
+	}
+
+	return ioutil.WriteFile(path, []byte(strconv.Itoa(os.Getpid())+"\n"), 0o600)
+}
+
+// RemovePidFile function removes the pidfile if it belongs
+// to the current process
+func RemovePidFile(path string) {
+	/* #nosec
+	 * The path is given by the user
+	 */
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return
+	}
+
+	if strings.TrimSpace(string(contents)) == strconv.Itoa(os.Getpid()) {
+		_ = os.Remove(path)
+	}
+}
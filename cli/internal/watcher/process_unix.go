@@ -0,0 +1,25 @@
+// +build !windows
+
+package watcher
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// processIsRunning function tells whether a process with `pid` exists
+func processIsRunning(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+
+	return process.Signal(syscall.Signal(0)) == nil
+}
+
+// detachedAttributes function makes the child the leader of a new session,
+// so it does not get the signals of the terminal
+func detachedAttributes(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
@@ -0,0 +1,29 @@
+// +build windows
+
+package watcher
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// processIsRunning function tells whether a process with `pid` exists
+func processIsRunning(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+
+	_ = process.Release()
+
+	return true
+}
+
+// detachedAttributes function runs the child in its own process group,
+// so it does not get the signals of the console
+func detachedAttributes(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP,
+	}
+}
@@ -0,0 +1,77 @@
+package watcher
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	b := NewBackoff(10*time.Second, time.Minute)
+
+	steps := []struct {
+		failed bool
+		want   time.Duration
+	}{
+		{false, 10 * time.Second},
+		{true, 20 * time.Second},
+		{true, 40 * time.Second},
+		{true, time.Minute},
+		{true, time.Minute},
+		{false, 10 * time.Second},
+	}
+
+	for i, step := range steps {
+		if got := b.Next(step.failed); got != step.want {
+			t.Errorf("step %d: Next(%v) = %v, want %v", i, step.failed, got, step.want)
+		}
+	}
+}
+
+func TestPidFile(t *testing.T) {
+	pidfile := path.Join(t.TempDir(), "watch.pid")
+
+	if err := WritePidFile(pidfile); err != nil {
+		t.Fatalf("WritePidFile: %v", err)
+	}
+
+	contents, _ := ioutil.ReadFile(pidfile)
+	if want := strconv.Itoa(os.Getpid()) + "\n"; string(contents) != want {
+		t.Errorf("pidfile contains %q, want %q", contents, want)
+	}
+
+	// The pid of a running process, other than ours
+	if err := ioutil.WriteFile(pidfile, []byte(strconv.Itoa(os.Getppid())), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := WritePidFile(pidfile); !errors.Is(err, ErrorAlreadyRunning) {
+		t.Errorf("WritePidFile: got %v, want ErrorAlreadyRunning", err)
+	}
+
+	// Only our own pidfile is removed
+	RemovePidFile(pidfile)
+	if _, err := os.Stat(pidfile); err != nil {
+		t.Errorf("RemovePidFile removed the pidfile of another process")
+	}
+}
+
+func TestNotifier(t *testing.T) {
+	var out bytes.Buffer
+
+	n := NewNotifier(&out)
+	n.now = func() time.Time {
+		return time.Date(2021, 10, 4, 12, 0, 0, 0, time.UTC)
+	}
+
+	n.Notify("my-project", "%s: secret %s updated", "dev", "PORT")
+
+	want := "2021-10-04T12:00:00Z [my-project] dev: secret PORT updated\n"
+	if out.String() != want {
+		t.Errorf("Notify wrote %q, want %q", out.String(), want)
+	}
+}
@@ -0,0 +1,208 @@
+// Package workspacefile handles keystone.workspace.yaml, which lists the
+// Keystone projects of a repository holding several of them.
+package workspacefile
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/wearedevx/keystone/cli/internal/utils"
+	"gopkg.in/yaml.v2"
+)
+
+// FileName is the name of the workspace file, at the root of the workspace
+const FileName = "keystone.workspace.yaml"
+
+// Project struct is a project of the workspace
+type Project struct {
+	// Path of the project directory, relative to the workspace root
+	Path string `yaml:"path"`
+	// Prefix for the secret names of the project, when they are merged
+	// with those of the other projects
+	Prefix string `yaml:"prefix,omitempty"`
+}
+
+// A WorkspaceFile represents the keystone.workspace.yaml file
+type WorkspaceFile struct {
+	Path     string    `yaml:"-"`
+	err      error     `yaml:"-"`
+	Projects []Project `yaml:"projects"`
+}
+
+// Workspace file path for the given directory
+func workspaceFilePath(wd string) string {
+	return path.Join(wd, FileName)
+}
+
+// NewWorkspaceFile function returns a new instance of a WorkspaceFile,
+// without any project
+func NewWorkspaceFile(wd string) *WorkspaceFile {
+	return &WorkspaceFile{
+		Path:     workspaceFilePath(wd),
+		Projects: make([]Project, 0),
+	}
+}
+
+// ExistsWorkspaceFile function checks if `wd` is the root of a workspace
+func ExistsWorkspaceFile(wd string) bool {
+	return utils.FileExists(workspaceFilePath(wd))
+}
+
+// FindRoot function looks for the root of a workspace in `cwd`,
+// then in its parents
+func FindRoot(cwd string) (root string, found bool) {
+	candidate := cwd
+
+	for {
+		if ExistsWorkspaceFile(candidate) {
+			return candidate, true
+		}
+
+		parent := filepath.Dir(candidate)
+		if parent == candidate {
+			return "", false
+		}
+
+		candidate = parent
+	}
+}
+
+// Load method reads a workspace file from disk
+func (file *WorkspaceFile) Load(wd string) *WorkspaceFile {
+	file.Path = workspaceFilePath(wd)
+
+	/* #nosec
+	 * We generate the file path, and its content is about to be parsed
+	 */
+	bytes, err := ioutil.ReadFile(file.Path)
+	if err != nil {
+		file.err = err
+		return file
+	}
+
+	if err = yaml.Unmarshal(bytes, file); err != nil {
+		file.err = fmt.Errorf("parsing error: %w", err)
+	}
+
+	return file
+}
+
+// Save method writes the workspace file to disk
+func (file *WorkspaceFile) Save() *WorkspaceFile {
+	if file.Err() != nil {
+		return file
+	}
+
+	bytes, err := yaml.Marshal(file)
+	if err != nil {
+		file.err = fmt.Errorf("could not serialize workspace file (%w)", err)
+		return file
+	}
+
+	if err = ioutil.WriteFile(file.Path, bytes, 0o600); err != nil {
+		file.err = fmt.Errorf("could not write `%s` (%w)", FileName, err)
+	}
+
+	return file
+}
+
+// Err method returns the last error that occurred
+func (file *WorkspaceFile) Err() error {
+	return file.err
+}
+
+// Root method returns the directory of the workspace
+func (file *WorkspaceFile) Root() string {
+	return path.Dir(file.Path)
+}
+
+// AddProject method adds a project to the workspace,
+// or updates its prefix if it is already there
+func (file *WorkspaceFile) AddProject(project Project) *WorkspaceFile {
+	if file.Err() != nil {
+		return file
+	}
+
+	project.Path = cleanProjectPath(project.Path)
+
+	for index, p := range file.Projects {
+		if p.Path == project.Path {
+			file.Projects[index].Prefix = project.Prefix
+			return file
+		}
+	}
+
+	file.Projects = append(file.Projects, project)
+
+	return file
+}
+
+// RemoveProject method removes the project at `projectPath`
+// from the workspace
+func (file *WorkspaceFile) RemoveProject(projectPath string) *WorkspaceFile {
+	if file.Err() != nil {
+		return file
+	}
+
+	projectPath = cleanProjectPath(projectPath)
+	projects := make([]Project, 0, len(file.Projects))
+
+	for _, p := range file.Projects {
+		if p.Path != projectPath {
+			projects = append(projects, p)
+		}
+	}
+
+	file.Projects = projects
+
+	return file
+}
+
+// GetProject method returns the project at `projectPath`
+func (file *WorkspaceFile) GetProject(projectPath string) (Project, bool) {
+	projectPath = cleanProjectPath(projectPath)
+
+	for _, p := range file.Projects {
+		if p.Path == projectPath {
+			return p, true
+		}
+	}
+
+	return Project{}, false
+}
+
+// Dir method returns the absolute path of the project directory
+func (file *WorkspaceFile) Dir(project Project) string {
+	return path.Join(file.Root(), project.Path)
+}
+
+var nonAlphanumeric = regexp.MustCompile(`[^A-Z0-9]+`)
+
+// SecretPrefix method returns the prefix added to the secret names of
+// `project`. It defaults to the name of its directory, in upper case:
+// the secrets of `services/api` are prefixed with `API_`.
+// A project at the root of the workspace is named after the workspace
+// directory.
+func (file *WorkspaceFile) SecretPrefix(project Project) string {
+	if project.Prefix != "" {
+		return project.Prefix
+	}
+
+	dir := project.Path
+	if cleanProjectPath(dir) == "." {
+		dir = file.Root()
+	}
+
+	name := strings.ToUpper(path.Base(filepath.ToSlash(dir)))
+	name = strings.Trim(nonAlphanumeric.ReplaceAllString(name, "_"), "_")
+
+	return name + "_"
+}
+
+func cleanProjectPath(projectPath string) string {
+	return path.Clean(filepath.ToSlash(projectPath))
+}
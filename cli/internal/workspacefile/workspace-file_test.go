@@ -0,0 +1,85 @@
+package workspacefile
+
+import (
+	"os"
+	"path"
+	"reflect"
+	"testing"
+)
+
+func TestSaveAndLoad(t *testing.T) {
+	root := t.TempDir()
+
+	err := NewWorkspaceFile(root).
+		AddProject(Project{Path: "./api"}).
+		AddProject(Project{Path: "cli", Prefix: "KS_"}).
+		AddProject(Project{Path: "api/", Prefix: "BACK_"}).
+		Save().
+		Err()
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	file := new(WorkspaceFile).Load(root)
+	if err := file.Err(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	want := []Project{
+		{Path: "api", Prefix: "BACK_"},
+		{Path: "cli", Prefix: "KS_"},
+	}
+	if !reflect.DeepEqual(file.Projects, want) {
+		t.Errorf("Projects = %+v, want %+v", file.Projects, want)
+	}
+
+	file.RemoveProject("./api")
+	if _, ok := file.GetProject("api"); ok {
+		t.Errorf("GetProject(api) found a removed project")
+	}
+	if got := file.Dir(file.Projects[0]); got != path.Join(root, "cli") {
+		t.Errorf("Dir = %s, want %s", got, path.Join(root, "cli"))
+	}
+}
+
+func TestFindRoot(t *testing.T) {
+	root := t.TempDir()
+	nested := path.Join(root, "services", "api", "src")
+
+	if err := os.MkdirAll(nested, 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, found := FindRoot(nested); found {
+		t.Fatalf("FindRoot found a workspace before it was created")
+	}
+
+	if err := NewWorkspaceFile(root).Save().Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	got, found := FindRoot(nested)
+	if !found || got != root {
+		t.Errorf("FindRoot = %s, %v, want %s, true", got, found, root)
+	}
+}
+
+func TestSecretPrefix(t *testing.T) {
+	workspace := NewWorkspaceFile("/home/dev/my-repo")
+
+	tests := []struct {
+		project Project
+		want    string
+	}{
+		{Project{Path: "api"}, "API_"},
+		{Project{Path: "services/web-app"}, "WEB_APP_"},
+		{Project{Path: "cli", Prefix: "KS_"}, "KS_"},
+		{Project{Path: "."}, "MY_REPO_"},
+	}
+
+	for _, tt := range tests {
+		if got := workspace.SecretPrefix(tt.project); got != tt.want {
+			t.Errorf("SecretPrefix(%s) = %s, want %s", tt.project.Path, got, tt.want)
+		}
+	}
+}
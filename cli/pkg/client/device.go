@@ -34,3 +34,18 @@ func (c *Devices) Revoke(uid string) error {
 	}
 	return err
 }
+
+// RotateKey method replaces the public key of the device with UID `uid`
+// with `publicKey`
+func (c *Devices) RotateKey(uid string, publicKey []byte) (models.Device, error) {
+	var result models.Device
+
+	err := c.r.put(
+		"/devices/"+uid+"/public-key",
+		models.RotateDeviceKeyPayload{PublicKey: publicKey},
+		&result,
+		nil,
+	)
+
+	return result, err
+}
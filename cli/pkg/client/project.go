@@ -129,6 +129,71 @@ func (p *Project) GetAccessibleEnvironments() ([]models.Environment, error) {
 	return result.Environments, err
 }
 
+// AddEnvironment method creates a new environment in the project.
+// `environmentType` is one of dev, staging or prod, and decides who has
+// access to the new environment.
+func (p *Project) AddEnvironment(
+	name string,
+	environmentType string,
+) (models.Environment, error) {
+	var result models.Environment
+
+	payload := models.EnvironmentPayload{
+		Name:            name,
+		EnvironmentType: environmentType,
+	}
+
+	p.log.Printf("Add environment %+v\n", payload)
+
+	err := p.r.post(
+		fmt.Sprintf("/projects/%s/environments", p.id),
+		payload,
+		&result,
+		nil,
+	)
+
+	return result, err
+}
+
+// RenameEnvironment method changes the name of the environment
+// identified by `environmentID`
+func (p *Project) RenameEnvironment(
+	environmentID string,
+	name string,
+) (models.Environment, error) {
+	var result models.Environment
+
+	payload := models.EnvironmentPayload{
+		Name: name,
+	}
+
+	p.log.Printf("Rename environment %s to %s\n", environmentID, name)
+
+	err := p.r.put(
+		fmt.Sprintf("/environments/%s", environmentID),
+		payload,
+		&result,
+		nil,
+	)
+
+	return result, err
+}
+
+// RemoveEnvironment method deletes the environment identified by
+// `environmentID`, along with its messages
+func (p *Project) RemoveEnvironment(environmentID string) error {
+	var result models.Environment
+
+	p.log.Printf("Remove environment %s\n", environmentID)
+
+	return p.r.del(
+		fmt.Sprintf("/environments/%s", environmentID),
+		nil,
+		&result,
+		nil,
+	)
+}
+
 // Destroys the project, its environments, environments versions,
 // project members, messages, etc. Permanently
 func (p *Project) Destroy() (err error) {
@@ -21,7 +21,7 @@ var EnvList EnvNameList = []EnvName{
 
 // String method formats the envlist for display
 func (el EnvNameList) String() string {
-	l := make([]string, 0, len(el))
+	l := make([]string, len(el))
 
 	for i, e := range el {
 		l[i] = string(e)
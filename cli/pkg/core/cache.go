@@ -0,0 +1,267 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/wearedevx/keystone/cli/internal/cachefile"
+	"github.com/wearedevx/keystone/cli/internal/config"
+	"github.com/wearedevx/keystone/cli/internal/crypto"
+	"github.com/wearedevx/keystone/cli/internal/envfile"
+	kserrors "github.com/wearedevx/keystone/cli/internal/errors"
+	"github.com/wearedevx/keystone/cli/internal/history"
+	"github.com/wearedevx/keystone/cli/internal/keystonefile"
+	"github.com/wearedevx/keystone/cli/internal/utils"
+)
+
+// cacheMigratedMarker is created in the cache directory once its files
+// have been encrypted. It holds the fingerprint of the key they are
+// encrypted with.
+const cacheMigratedMarker = ".encrypted"
+
+// cacheKey encrypts the cache with a key derived from the private key
+// of the user, so that secrets are not stored in clear on the disk
+type cacheKey []byte
+
+func deriveCacheKey(privateKey []byte) cacheKey {
+	sum := sha256.Sum256(append([]byte("keystone cache\x00"), privateKey...))
+
+	return cacheKey(sum[:])
+}
+
+func (key cacheKey) Encrypt(data []byte) ([]byte, error) {
+	return crypto.EncryptWithKey(key, data)
+}
+
+func (key cacheKey) Decrypt(data []byte) ([]byte, error) {
+	return crypto.DecryptWithKey(key, data)
+}
+
+// fingerprint identifies the key without revealing it
+func (key cacheKey) fingerprint() string {
+	sum := sha256.Sum256(append([]byte("keystone cache fingerprint\x00"), key...))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// NewCacheCodec function returns the codec that encrypts the cache
+// for the owner of `privateKey`.
+// Files encrypted with one of the `previousKeys` of the device can still
+// be read.
+func NewCacheCodec(
+	privateKey []byte,
+	previousKeys ...[]byte,
+) cachefile.Codec {
+	previous := make([]cachefile.Cipher, 0, len(previousKeys))
+	for _, key := range previousKeys {
+		previous = append(previous, deriveCacheKey(key))
+	}
+
+	return cachefile.New(
+		cachefile.Fallback(deriveCacheKey(privateKey), previous...),
+	)
+}
+
+// userKeys returns the private key of the user, and the previous ones
+// of the device whose grace period has not ended
+func userKeys() (privateKey []byte, previousKeys [][]byte, err error) {
+	if privateKey, err = config.GetUserPrivateKey(); err != nil {
+		return nil, nil, err
+	}
+
+	// Without them, only the files encrypted with the current key are read
+	previousKeys, _ = config.GetPreviousPrivateKeys()
+
+	return privateKey, previousKeys, nil
+}
+
+// cacheCodec returns the codec used to read and write the cache.
+// The first time, files that are in plaintext, or encrypted with a
+// previous key of the device, are encrypted with the current key.
+func (ctx *Context) cacheCodec() (cachefile.Codec, *kserrors.Error) {
+	if ctx.cache != nil {
+		return *ctx.cache, nil
+	}
+
+	privateKey, previousKeys, err := userKeys()
+	if err != nil {
+		return cachefile.Codec{}, kserrors.FailedToReadCache(ctx.cacheDirPath(), err)
+	}
+
+	codec := NewCacheCodec(privateKey, previousKeys...)
+
+	if !ctx.cacheIsUpToDate(privateKey) {
+		if e := ctx.RekeyCache(privateKey, previousKeys).Err(); e != nil {
+			return codec, e
+		}
+	}
+
+	ctx.cache = &codec
+
+	return codec, nil
+}
+
+// cacheIsUpToDate returns true if the files of the cache are encrypted
+// with `privateKey`
+func (ctx *Context) cacheIsUpToDate(privateKey []byte) bool {
+	cacheDir := ctx.cacheDirPath()
+	if !utils.DirExists(cacheDir) {
+		return true
+	}
+
+	/* #nosec */
+	content, err := ioutil.ReadFile(path.Join(cacheDir, cacheMigratedMarker))
+
+	return err == nil &&
+		string(content) == deriveCacheKey(privateKey).fingerprint()
+}
+
+// isCachedHistory returns true if `p` is the history of an environment.
+// Histories are encrypted line by line already.
+func (ctx *Context) isCachedHistory(p string) bool {
+	return filepath.Base(p) == "history" &&
+		filepath.Dir(filepath.Dir(p)) == filepath.Clean(ctx.cacheDirPath())
+}
+
+// RekeyCache method encrypts the cache and the histories again with
+// `privateKey`. They can be in plaintext, or encrypted with it or with
+// one of the `previousKeys` of the device.
+// Each file is replaced once all of them have been encrypted, and the
+// marker is only updated at the end, so an interrupted rekey is resumed
+// on the next read.
+func (ctx *Context) RekeyCache(
+	privateKey []byte,
+	previousKeys [][]byte,
+) *Context {
+	if ctx.Err() != nil {
+		return ctx
+	}
+
+	cacheDir := ctx.cacheDirPath()
+	marker := path.Join(cacheDir, cacheMigratedMarker)
+
+	if !utils.DirExists(cacheDir) {
+		return ctx
+	}
+
+	codec := NewCacheCodec(privateKey, previousKeys...)
+
+	count, err := codec.Rekey(
+		cacheDir,
+		codec,
+		func(p string) bool {
+			return p == marker || ctx.isCachedHistory(p)
+		},
+	)
+	if err != nil {
+		return ctx.setError(kserrors.FailedToEncryptCache(cacheDir, err))
+	}
+
+	ctx.cache = nil
+
+	entries, err := ioutil.ReadDir(cacheDir)
+	if err != nil {
+		return ctx.setError(kserrors.FailedToEncryptCache(cacheDir, err))
+	}
+
+	for _, entry := range entries {
+		historyPath := path.Join(cacheDir, entry.Name(), "history")
+		if !entry.IsDir() || !utils.FileExists(historyPath) {
+			continue
+		}
+
+		err = history.New(historyPath, historyCipher(privateKey, previousKeys)).
+			Rekey(historyCipher(privateKey, nil)).
+			Err()
+		if err != nil {
+			return ctx.setError(kserrors.FailedToWriteHistory(historyPath, err))
+		}
+	}
+
+	err = ioutil.WriteFile(
+		marker,
+		[]byte(deriveCacheKey(privateKey).fingerprint()),
+		0o600,
+	)
+	if err != nil {
+		return ctx.setError(kserrors.FailedToEncryptCache(cacheDir, err))
+	}
+
+	ctx.log.Printf("Encrypted %d files in %s\n", count, cacheDir)
+
+	return ctx
+}
+
+// loadCachedDotEnv loads the encrypted .env file at `dotEnvPath`.
+// Values are only decrypted in memory.
+func (ctx *Context) loadCachedDotEnv(dotEnvPath string) *envfile.EnvFile {
+	dotEnv := new(envfile.EnvFile)
+
+	codec, e := ctx.cacheCodec()
+	if e != nil {
+		return dotEnv.SetError("failed to get the cache key (%w)", e.Cause())
+	}
+
+	return dotEnv.Load(dotEnvPath, &envfile.LoadOptions{Cipher: codec})
+}
+
+// ReadCachedFile method returns the decrypted content of the file
+// at `cachedPath`, in cache
+func (ctx *Context) ReadCachedFile(cachedPath string) ([]byte, error) {
+	codec, e := ctx.cacheCodec()
+	if e != nil {
+		return nil, e
+	}
+
+	return codec.ReadFile(cachedPath)
+}
+
+// writeCachedFile encrypts `content` and writes it at `cachedPath`,
+// in cache
+func (ctx *Context) writeCachedFile(cachedPath string, content []byte) error {
+	codec, e := ctx.cacheCodec()
+	if e != nil {
+		return e
+	}
+
+	return codec.WriteFile(cachedPath, content)
+}
+
+// CopyFromCache method writes the decrypted content of the file at
+// `cachedPath` to `destination`, outside of the cache
+func (ctx *Context) CopyFromCache(cachedPath, destination string) error {
+	return ctx.CopyFromCacheWithMode(
+		cachedPath,
+		destination,
+		keystonefile.DefaultFileMode,
+	)
+}
+
+// CopyFromCacheWithMode method writes the decrypted content of the file
+// at `cachedPath` to `destination`, outside of the cache, with the
+// permissions `mode`
+func (ctx *Context) CopyFromCacheWithMode(
+	cachedPath, destination string,
+	mode os.FileMode,
+) error {
+	content, err := ctx.ReadCachedFile(cachedPath)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(destination), 0o700); err != nil {
+		return err
+	}
+
+	if err = ioutil.WriteFile(destination, content, mode); err != nil {
+		return err
+	}
+
+	// The mode of existing files is kept by WriteFile,
+	// and the umask applies to new ones
+	return os.Chmod(destination, mode)
+}
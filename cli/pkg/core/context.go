@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 
 	"github.com/wearedevx/keystone/api/pkg/models"
+	"github.com/wearedevx/keystone/cli/internal/cachefile"
 	"github.com/wearedevx/keystone/cli/internal/config"
 	"github.com/wearedevx/keystone/cli/internal/environmentsfile"
 	kserrors "github.com/wearedevx/keystone/cli/internal/errors"
@@ -23,6 +24,8 @@ type Context struct {
 	TmpDir                 string
 	ConfigDir              string
 	AccessibleEnvironments []models.Environment
+	// cache is set the first time the cache is read or written
+	cache *cachefile.Codec
 }
 
 const (
@@ -46,8 +49,7 @@ const (
 func New(flag string) *Context {
 	var cwd string
 	var err error
-	context := new(Context)
-	context.log = log.New(log.Writer(), "[Context] ", 0)
+	context := newContext()
 
 	if cwd, err = os.Getwd(); err != nil {
 		return context.setError(kserrors.NoWorkingDirectory(err))
@@ -58,10 +60,10 @@ func New(flag string) *Context {
 	case CTX_INIT:
 		context.Wd = cwd
 	case CTX_RESOLVE:
-		wd, err := resolveKeystoneRootDir(cwd)
+		wd, e := resolveProjectDir(cwd, "")
 
-		if err != nil {
-			return context.setError(kserrors.NotAKeystoneProject(cwd, err))
+		if e != nil {
+			return context.setError(e)
 		} else {
 			context.Wd = wd
 		}
@@ -71,6 +73,40 @@ func New(flag string) *Context {
 
 	context.log.Printf("Context working directory: %s", context.Wd)
 
+	return context
+}
+
+// NewForProject function creates a new execution context for the project
+// in the `project` directory, which may be relative to the current
+// directory or to the root of the workspace.
+// When `project` is empty, it behaves like `New(CTX_RESOLVE)`.
+func NewForProject(project string) *Context {
+	if project == "" {
+		return New(CTX_RESOLVE)
+	}
+
+	context := newContext()
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return context.setError(kserrors.NoWorkingDirectory(err))
+	}
+
+	wd, e := resolveProjectDir(cwd, project)
+	if e != nil {
+		return context.setError(e)
+	}
+
+	context.Wd = wd
+	context.log.Printf("Context working directory: %s", context.Wd)
+
+	return context
+}
+
+func newContext() *Context {
+	context := new(Context)
+	context.log = log.New(log.Writer(), "[Context] ", 0)
+
 	// Get a temporary directory
 	tmpDir := os.TempDir()
 	context.TmpDir = tmpDir
@@ -125,9 +161,7 @@ func (c *Context) CachedEnvironmentPath(environmentName string) string {
 	c.mustEnvironmentNameBeValid(environmentName)
 
 	if !c.fileBelongsToContext(p) {
-		kserrors.
-			EnvironmentDoesntExist(environmentName, "dev, staging, prod", nil).
-			Print()
+		kserrors.BadEnvironmentName(environmentName, nil).Print()
 		os.Exit(1)
 	}
 
@@ -140,6 +174,18 @@ func (c *Context) CachedEnvironmentDotEnvPath(environmentName string) string {
 	return path.Join(c.CachedEnvironmentPath(environmentName), ".env")
 }
 
+// CachedEnvironmentHistoryPath method returns the path to the history
+// of secret changes for the given environment in cache
+func (c *Context) CachedEnvironmentHistoryPath(environmentName string) string {
+	return path.Join(c.CachedEnvironmentPath(environmentName), "history")
+}
+
+// CachedEnvironmentFileModesPath method returns the path to the modes
+// of the files for the given environment in cache
+func (c *Context) CachedEnvironmentFileModesPath(environmentName string) string {
+	return path.Join(c.CachedEnvironmentPath(environmentName), "modes")
+}
+
 // CachedEnvironmentFilesPath method returns the path to the files dir
 // for the given environment in cache
 func (c *Context) CachedEnvironmentFilesPath(environmentName string) string {
@@ -0,0 +1,264 @@
+package core
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path"
+	"sort"
+
+	kserrors "github.com/wearedevx/keystone/cli/internal/errors"
+	"github.com/wearedevx/keystone/cli/internal/textdiff"
+	"github.com/wearedevx/keystone/cli/internal/utils"
+)
+
+// DiffKind tells how a secret or a file differs between two environments
+type DiffKind string
+
+const (
+	// Present in one environment only
+	DiffMissing DiffKind = "missing"
+	// Present in both environments, but empty in one of them
+	DiffEmpty DiffKind = "empty"
+	// Present in both environments, with different values
+	DiffDifferent DiffKind = "different"
+)
+
+// SecretDiff describes a secret whose value differs between two environments
+type SecretDiff struct {
+	Name   string   `json:"name"`
+	Kind   DiffKind `json:"kind"`
+	InA    bool     `json:"in_a"`
+	InB    bool     `json:"in_b"`
+	ValueA string   `json:"value_a,omitempty"`
+	ValueB string   `json:"value_b,omitempty"`
+}
+
+// FileDiff describes a file whose content differs between two environments.
+// Contents are compared using their sha256 hash.
+type FileDiff struct {
+	Path  string   `json:"path"`
+	Kind  DiffKind `json:"kind"`
+	HashA string   `json:"hash_a,omitempty"`
+	HashB string   `json:"hash_b,omitempty"`
+}
+
+// EnvironmentsDiff lists everything that differs between two environments
+type EnvironmentsDiff struct {
+	EnvironmentA string       `json:"environment_a"`
+	EnvironmentB string       `json:"environment_b"`
+	Secrets      []SecretDiff `json:"secrets"`
+	Files        []FileDiff   `json:"files"`
+}
+
+// HasDrift method tells if the environments differ in any way
+func (d EnvironmentsDiff) HasDrift() bool {
+	return len(d.Secrets) > 0 || len(d.Files) > 0
+}
+
+// DiffEnvironments method compares the secrets and the files in cache
+// for the environments `environmentA` and `environmentB`
+func (ctx *Context) DiffEnvironments(
+	environmentA, environmentB string,
+) EnvironmentsDiff {
+	diff := EnvironmentsDiff{
+		EnvironmentA: environmentA,
+		EnvironmentB: environmentB,
+		Secrets:      make([]SecretDiff, 0),
+		Files:        make([]FileDiff, 0),
+	}
+
+	if ctx.Err() != nil {
+		return diff
+	}
+
+	diff.Secrets = diffSecrets(
+		ctx.GetAllSecrets(environmentA),
+		ctx.GetAllSecrets(environmentB),
+	)
+	if ctx.Err() != nil {
+		return diff
+	}
+
+	filesA := ctx.hashCachedFiles(environmentA)
+	filesB := ctx.hashCachedFiles(environmentB)
+	if ctx.Err() != nil {
+		return diff
+	}
+
+	diff.Files = diffFiles(filesA, filesB)
+
+	return diff
+}
+
+func diffSecrets(secretsA, secretsB map[string]string) []SecretDiff {
+	diffs := make([]SecretDiff, 0)
+
+	for _, name := range unionOfKeys(secretsA, secretsB) {
+		valueA, inA := secretsA[name]
+		valueB, inB := secretsB[name]
+		secretDiff := SecretDiff{
+			Name:   name,
+			InA:    inA,
+			InB:    inB,
+			ValueA: valueA,
+			ValueB: valueB,
+		}
+
+		switch {
+		case !inA || !inB:
+			secretDiff.Kind = DiffMissing
+		case valueA == valueB:
+			continue
+		case valueA == "" || valueB == "":
+			secretDiff.Kind = DiffEmpty
+		default:
+			secretDiff.Kind = DiffDifferent
+		}
+
+		diffs = append(diffs, secretDiff)
+	}
+
+	return diffs
+}
+
+func diffFiles(filesA, filesB map[string]string) []FileDiff {
+	diffs := make([]FileDiff, 0)
+
+	for _, filePath := range unionOfKeys(filesA, filesB) {
+		hashA, inA := filesA[filePath]
+		hashB, inB := filesB[filePath]
+		fileDiff := FileDiff{
+			Path:  filePath,
+			HashA: hashA,
+			HashB: hashB,
+		}
+
+		switch {
+		case !inA || !inB:
+			fileDiff.Kind = DiffMissing
+		case hashA == hashB:
+			continue
+		case hashA == emptyHash || hashB == emptyHash:
+			fileDiff.Kind = DiffEmpty
+		default:
+			fileDiff.Kind = DiffDifferent
+		}
+
+		diffs = append(diffs, fileDiff)
+	}
+
+	return diffs
+}
+
+var emptyHash = hashContent([]byte{})
+
+func hashContent(content []byte) string {
+	sum := sha256.Sum256(content)
+
+	return hex.EncodeToString(sum[:])
+}
+
+// hashCachedFiles returns the sha256 of every file in cache for the
+// environment `environmentName`, by file path.
+// It sets an error when one of them cannot be read or decrypted.
+func (ctx *Context) hashCachedFiles(environmentName string) map[string]string {
+	hashes := make(map[string]string)
+
+	if !ctx.HasEnvironment(environmentName) {
+		return hashes
+	}
+
+	cachePath := ctx.CachedEnvironmentFilesPath(environmentName)
+	if _, err := os.Stat(cachePath); os.IsNotExist(err) {
+		return hashes
+	}
+
+	for _, file := range ctx.ListCachedFilesForEnvironment(environmentName) {
+		content, err := ctx.ReadCachedFile(path.Join(cachePath, file.Path))
+		if err != nil {
+			ctx.setError(kserrors.FailedToReadCache(cachePath, err))
+			return hashes
+		}
+
+		hashes[file.Path] = hashContent(content)
+	}
+
+	return hashes
+}
+
+// unionOfKeys returns the keys found in either maps, sorted
+func unionOfKeys(a, b map[string]string) []string {
+	keys := make([]string, 0, len(a)+len(b))
+
+	for key := range a {
+		keys = append(keys, key)
+	}
+
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// LocalFileDiff describes how the local version of a file differs from
+// its content in cache for an environment
+type LocalFileDiff struct {
+	Path        string
+	Environment string
+	Modified    bool
+	// Binary files are compared as a whole, they have no Diff
+	Binary bool
+	// Files with too many changes have no Diff either
+	TooDifferent bool
+	// Unified diff from the cached content to the local one
+	Diff string
+}
+
+// DiffLocalFile method compares the local version of the file `filePath`
+// with its content for `environmentName`, inherited from its parents
+// if needed.
+// A file that is missing on either side is compared as an empty one.
+func (ctx *Context) DiffLocalFile(
+	filePath string,
+	environmentName string,
+) LocalFileDiff {
+	diff := LocalFileDiff{
+		Path:        filePath,
+		Environment: environmentName,
+	}
+
+	if ctx.Err() != nil {
+		return diff
+	}
+
+	// Errors mean the file does not exist, or is empty
+	cached, _ := ctx.GetFileContents(filePath, environmentName)
+	local, _ := ctx.GetLocalFileContents(filePath)
+
+	diff.Modified = !bytes.Equal(cached, local)
+	diff.Binary = utils.IsBinary(string(cached)) ||
+		utils.IsBinary(string(local))
+
+	if !diff.Modified || diff.Binary {
+		return diff
+	}
+
+	var compared bool
+	diff.Diff, compared = textdiff.Unified(
+		path.Join("a", filePath),
+		path.Join("b", filePath),
+		string(cached),
+		string(local),
+		3,
+	)
+	diff.TooDifferent = !compared
+
+	return diff
+}
@@ -15,10 +15,24 @@ type (
 )
 
 type Secret struct {
-	Name      string
-	Required  bool
-	Values    map[EnvironmentName]SecretValue
-	FromCache bool
+	Name          string
+	Required      bool
+	Values        map[EnvironmentName]SecretValue
+	FromCache     bool
+	Documentation keystonefile.EnvDocumentation
+	// Environments whose value is inherited, and the environment
+	// it comes from
+	InheritedFrom map[EnvironmentName]EnvironmentName
+	// Environments the secret is required in, when not `Required`
+	RequiredIn []string
+	// Values are not expanded, see `Context.MarkSecretVerbatim`
+	Verbatim bool
+}
+
+// IsRequiredIn method returns true if the secret must have a value
+// in `environment`
+func (s Secret) IsRequiredIn(environment string) bool {
+	return s.Required || Contains(s.RequiredIn, environment)
 }
 
 type SecretStrictFlag int
@@ -44,6 +58,15 @@ func (ctx *Context) AddSecret(
 	var err error
 	var e *kserrors.Error
 	var ksfile keystonefile.KeystoneFile
+
+	// Refuse values that do not satisfy the rules of keystone.yaml,
+	// before anything is written
+	for env, value := range secretValue {
+		if ctx.ValidateSecret(env, secretName, value).Err() != nil {
+			return ctx
+		}
+	}
+
 	// Add new env key to keystone.yaml
 	if err = ksfile.
 		Load(ctx.Wd).
@@ -61,6 +84,14 @@ func (ctx *Context) AddSecret(
 			return ctx.setError(e)
 		}
 		ctx.log.Printf("Cached %s for env %s as %s\n", secretName, env, value)
+
+		if ctx.recordLocalSecretChange(env, Change{
+			Name: secretName,
+			To:   value,
+			Type: ChangeTypeSecretAdd,
+		}).Err() != nil {
+			return ctx
+		}
 	}
 
 	// Copy the new .env for the current environment to .keystone/cache/.env
@@ -100,8 +131,7 @@ func generateEnvFileInCache(
 
 	envFilePath := path.Join(cachePath, ".env")
 
-	if err = new(envfile.EnvFile).
-		Load(envFilePath, nil).
+	if err = ctx.loadCachedDotEnv(envFilePath).
 		Set(secretName, value).
 		Dump().
 		Err(); err != nil {
@@ -159,9 +189,9 @@ func (ctx *Context) purgeSecret(secretName string) *Context {
 	for _, environment := range environments {
 		dir := ctx.CachedEnvironmentPath(environment)
 		dotEnvPath := path.Join(dir, ".env")
-		dotEnv := new(envfile.EnvFile)
+		dotEnv := ctx.loadCachedDotEnv(dotEnvPath)
 
-		if err = dotEnv.Load(dotEnvPath, nil).Err(); err != nil {
+		if err = dotEnv.Err(); err != nil {
 			return ctx.setError(kserrors.FailedToReadDotEnv(dotEnvPath, err))
 		}
 
@@ -217,9 +247,9 @@ func (ctx *Context) PurgeSecrets() *Context {
 	for _, environment := range environments {
 		dir := ctx.CachedEnvironmentPath(environment)
 		dotEnvPath := path.Join(dir, ".env")
-		dotEnv := new(envfile.EnvFile)
+		dotEnv := ctx.loadCachedDotEnv(dotEnvPath)
 
-		if err = dotEnv.Load(dotEnvPath, nil).Err(); err != nil {
+		if err = dotEnv.Err(); err != nil {
 			return ctx.setError(kserrors.FailedToReadDotEnv(dotEnvPath, err))
 		}
 
@@ -265,25 +295,41 @@ func (ctx *Context) SetSecret(
 	secretName string,
 	secretValue string,
 ) *Context {
-	if ctx.Err() != nil {
+	if ctx.ValidateSecret(envName, secretName, secretValue).Err() != nil {
 		return ctx
 	}
 
 	dotEnvPath := ctx.CachedEnvironmentDotEnvPath(envName)
-	dotEnv := new(
-		envfile.EnvFile,
-	).Load(ctx.CachedEnvironmentDotEnvPath(envName), nil)
+	dotEnv := ctx.loadCachedDotEnv(dotEnvPath)
 
 	if err := dotEnv.Err(); err != nil {
 		return ctx.setError(kserrors.FailedToReadDotEnv(dotEnvPath, err))
 	}
 
+	previousValue, existed := dotEnv.Get(secretName)
+
 	dotEnv.Set(secretName, secretValue).Dump()
 
 	if err := dotEnv.Err(); err != nil {
 		return ctx.setError(kserrors.FailedToUpdateDotEnv(dotEnvPath, err))
 	}
 
+	if !existed || previousValue != secretValue {
+		change := Change{
+			Name: secretName,
+			From: previousValue,
+			To:   secretValue,
+			Type: ChangeTypeSecretChange,
+		}
+		if !existed {
+			change.Type = ChangeTypeSecretAdd
+		}
+
+		if ctx.recordLocalSecretChange(envName, change).Err() != nil {
+			return ctx
+		}
+	}
+
 	ctx.log.Printf("Set secret %s to %s in env %s\n", secretName, secretValue, envName)
 
 	return ctx
@@ -308,7 +354,7 @@ func (ctx *Context) GetSecrets() map[string]string {
 	var err error
 	var env map[string]string
 
-	dotEnv := new(envfile.EnvFile).Load(ctx.CachedDotEnvPath(), nil)
+	dotEnv := ctx.loadCachedDotEnv(ctx.CachedDotEnvPath())
 
 	if err = dotEnv.Err(); err != nil {
 		ctx.setError(kserrors.FailedToUpdateDotEnv(ctx.CachedDotEnvPath(), err))
@@ -349,30 +395,29 @@ func (ctx *Context) GetSecret(secretName string) *Secret {
 		return secret
 	}
 
-	environmentValuesMap := map[string]map[string]string{}
-	for _, environment := range ctx.ListEnvironments() {
-		dotEnvPath := ctx.CachedEnvironmentDotEnvPath(environment)
-		dotEnv := new(envfile.EnvFile).Load(dotEnvPath, nil)
-
-		environmentValuesMap[environment] = dotEnv.GetData()
+	cachedValues := ctx.cachedEnvironmentValues()
+	if ctx.Err() != nil {
+		return secret
 	}
 
+	environmentValuesMap, inheritedFrom := ctx.inheritValues(cachedValues)
+
 	for _, envKey := range ksfile.Env {
 		name := envKey.Key
 
 		if name == secretName {
 			required := envKey.Strict
-			values := map[EnvironmentName]SecretValue{}
-
-			for environment, secrets := range environmentValuesMap {
-				values[EnvironmentName(environment)] = SecretValue(
-					secrets[name],
-				)
-			}
 
 			secret.Name = name
 			secret.Required = required
-			secret.Values = values
+			secret.Values, secret.InheritedFrom = secretValues(
+				name,
+				environmentValuesMap,
+				inheritedFrom,
+			)
+			secret.Documentation = envKey.EnvDocumentation
+			secret.RequiredIn = envKey.RequiredIn
+			secret.Verbatim = envKey.Verbatim
 
 			break
 		}
@@ -403,7 +448,12 @@ func (ctx *Context) ListSecretsFromCache() []Secret {
 
 	for _, environment := range ctx.ListEnvironments() {
 		dotEnvPath := ctx.CachedEnvironmentDotEnvPath(environment)
-		dotEnv := new(envfile.EnvFile).Load(dotEnvPath, nil)
+		dotEnv := ctx.loadCachedDotEnv(dotEnvPath)
+
+		if err = dotEnv.Err(); err != nil {
+			ctx.setError(kserrors.FailedToReadDotEnv(dotEnvPath, err))
+			return secrets
+		}
 
 		environmentValuesMap[environment] = dotEnv.GetData()
 		for label := range dotEnv.GetData() {
@@ -452,28 +502,115 @@ func (ctx *Context) ListSecrets() []Secret {
 		return secrets
 	}
 
+	cachedValues := ctx.cachedEnvironmentValues()
+	if ctx.Err() != nil {
+		return secrets
+	}
+
+	environmentValuesMap, inheritedFrom := ctx.inheritValues(cachedValues)
+
+	for _, envKey := range ksfile.Env {
+		name := envKey.Key
+		required := envKey.Strict
+		values, from := secretValues(name, environmentValuesMap, inheritedFrom)
+
+		secrets = append(secrets, Secret{
+			Name:          name,
+			Required:      required,
+			Values:        values,
+			Documentation: envKey.EnvDocumentation,
+			InheritedFrom: from,
+			RequiredIn:    envKey.RequiredIn,
+			Verbatim:      envKey.Verbatim,
+		})
+	}
+
+	return secrets
+}
+
+// cachedEnvironmentValues returns the values stored in cache for every
+// environment, by environment name.
+// It sets an error when one of them cannot be read or decrypted.
+func (ctx *Context) cachedEnvironmentValues() map[string]map[string]string {
 	environmentValuesMap := map[string]map[string]string{}
+
 	for _, environment := range ctx.ListEnvironments() {
 		dotEnvPath := ctx.CachedEnvironmentDotEnvPath(environment)
-		dotEnv := new(envfile.EnvFile).Load(dotEnvPath, nil)
+		dotEnv := ctx.loadCachedDotEnv(dotEnvPath)
+
+		if err := dotEnv.Err(); err != nil {
+			ctx.setError(kserrors.FailedToReadDotEnv(dotEnvPath, err))
+			return map[string]map[string]string{}
+		}
 
 		environmentValuesMap[environment] = dotEnv.GetData()
 	}
 
-	for _, envKey := range ksfile.Env {
-		name := envKey.Key
-		required := envKey.Strict
-		values := map[EnvironmentName]SecretValue{}
+	return environmentValuesMap
+}
 
-		for environment, secrets := range environmentValuesMap {
-			values[EnvironmentName(environment)] = SecretValue(secrets[name])
+// secretValues returns the value of the secret `name` in every environment,
+// and the environment each inherited value comes from
+func secretValues(
+	name string,
+	environmentValuesMap map[string]map[string]string,
+	inheritedFrom map[string]map[string]string,
+) (map[EnvironmentName]SecretValue, map[EnvironmentName]EnvironmentName) {
+	values := map[EnvironmentName]SecretValue{}
+	from := map[EnvironmentName]EnvironmentName{}
+
+	for environment, secrets := range environmentValuesMap {
+		values[EnvironmentName(environment)] = SecretValue(secrets[name])
+
+		if parent, ok := inheritedFrom[environment][name]; ok {
+			from[EnvironmentName(environment)] = EnvironmentName(parent)
 		}
+	}
 
-		secrets = append(secrets, Secret{
-			Name:     name,
-			Required: required,
-			Values:   values,
-		})
+	return values, from
+}
+
+// ListExpandedSecrets method returns the same secrets as `ListSecrets`,
+// with the references to other secrets (`${OTHER_SECRET}`) resolved in
+// their value for `envName`.
+// Verbatim and binary values are not expanded.
+// Values stored in cache are left untouched.
+func (ctx *Context) ListExpandedSecrets(envName string) []Secret {
+	secrets := ctx.ListSecrets()
+
+	if ctx.Err() != nil {
+		return secrets
+	}
+
+	values := make(map[string]string)
+	literals := make([]string, 0)
+	for _, secret := range secrets {
+		value := string(secret.Values[EnvironmentName(envName)])
+		values[secret.Name] = value
+
+		// Files, PEM keys or binary blobs must come out as they went in
+		if secret.Verbatim || utils.IsBinary(value) {
+			literals = append(literals, secret.Name)
+		}
+	}
+
+	expanded, err := envfile.Expand(values, literals...)
+	if err != nil {
+		ctx.setError(kserrors.SecretReferenceCycle(envName, err))
+		return secrets
+	}
+
+	for index, secret := range secrets {
+		// Copy, so that other environments are not altered
+		secretValues := make(map[EnvironmentName]SecretValue)
+		for environment, value := range secret.Values {
+			secretValues[environment] = value
+		}
+
+		secretValues[EnvironmentName(envName)] = SecretValue(
+			expanded[secret.Name],
+		)
+		secrets[index].Values = secretValues
 	}
 
 	return secrets
@@ -520,7 +657,7 @@ func (ctx *Context) MissingSecretsForEnvironment(
 	secrets := ctx.ListSecrets()
 
 	for _, secret := range secrets {
-		if secret.Required {
+		if secret.IsRequiredIn(environmentName) {
 			value, ok := secret.Values[EnvironmentName(environmentName)]
 
 			if !ok || value == "" {
@@ -557,6 +694,61 @@ func (ctx *Context) SecretIsRequired(secretName string) bool {
 	return required
 }
 
+// SecretIsRequiredIn method tells if `secretName` is required
+// in `environmentName`.
+func (ctx *Context) SecretIsRequiredIn(
+	secretName string,
+	environmentName string,
+) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	ksfile := keystonefile.LoadKeystoneFile(ctx.Wd)
+	if err := ksfile.Err(); err != nil {
+		ctx.setError(kserrors.FailedToReadKeystoneFile(ksfile.Path, err))
+		return false
+	}
+
+	envKey, _ := ksfile.GetEnv(secretName)
+
+	return envKey.IsRequiredIn(environmentName)
+}
+
+// MarkSecretRequiredIn method changes the required status of a secret
+// for `environmentName` only.
+// Making a secret that is required everywhere optional in one environment
+// makes it required in all the others.
+func (ctx *Context) MarkSecretRequiredIn(
+	secretName string,
+	environmentName string,
+	required bool,
+) *Context {
+	if ctx.Err() != nil {
+		return ctx
+	}
+
+	ksfile := new(keystonefile.KeystoneFile).Load(ctx.Wd)
+	envKey, _ := ksfile.GetEnv(secretName)
+
+	if !required && envKey.Strict {
+		ksfile.SetEnv(secretName, false)
+
+		for _, environment := range ctx.ListEnvironments() {
+			ksfile.SetEnvRequiredIn(secretName, environment, true)
+		}
+	}
+
+	if err := ksfile.
+		SetEnvRequiredIn(secretName, environmentName, required).
+		Save().
+		Err(); err != nil {
+		return ctx.setError(kserrors.FailedToUpdateKeystoneFile(err))
+	}
+
+	return ctx
+}
+
 // MarkSecretRequired method changes the required status of a secret
 func (ctx *Context) MarkSecretRequired(
 	secretName string,
@@ -566,7 +758,16 @@ func (ctx *Context) MarkSecretRequired(
 		return ctx
 	}
 
-	if err := keystonefile.LoadKeystoneFile(ctx.Wd).
+	ksfile := keystonefile.LoadKeystoneFile(ctx.Wd)
+
+	// Optional everywhere
+	if envKey, _ := ksfile.GetEnv(secretName); !required {
+		for _, environment := range envKey.RequiredIn {
+			ksfile.SetEnvRequiredIn(secretName, environment, false)
+		}
+	}
+
+	if err := ksfile.
 		SetEnv(secretName, required).
 		Save().
 		Err(); err != nil {
@@ -576,6 +777,46 @@ func (ctx *Context) MarkSecretRequired(
 	return ctx
 }
 
+// DescribeSecret method replaces the description, owner and documentation
+// link of a secret in keystone.yaml
+func (ctx *Context) DescribeSecret(
+	secretName string,
+	documentation keystonefile.EnvDocumentation,
+) *Context {
+	if ctx.Err() != nil {
+		return ctx
+	}
+
+	if err := new(keystonefile.KeystoneFile).
+		Load(ctx.Wd).
+		SetEnvDocumentation(secretName, documentation).
+		Save().
+		Err(); err != nil {
+		return ctx.setError(kserrors.FailedToUpdateKeystoneFile(err))
+	}
+
+	return ctx
+}
+
+// MarkSecretVerbatim method marks a secret as verbatim in keystone.yaml:
+// its values were read from a file or from the standard input, and must
+// be output as they are, without expanding references in them
+func (ctx *Context) MarkSecretVerbatim(secretName string) *Context {
+	if ctx.Err() != nil {
+		return ctx
+	}
+
+	if err := new(keystonefile.KeystoneFile).
+		Load(ctx.Wd).
+		SetEnvVerbatim(secretName).
+		Save().
+		Err(); err != nil {
+		return ctx.setError(kserrors.FailedToUpdateKeystoneFile(err))
+	}
+
+	return ctx
+}
+
 // Returns an array of secrets that are in the first list, an not in the second
 func FilterSecretsFromCache(
 	secretsFromCache []Secret,
@@ -3,13 +3,14 @@ package core
 import (
 	"io/ioutil"
 	"os"
+	"regexp"
 	"strings"
 
 	"github.com/wearedevx/keystone/api/pkg/models"
 
-	"github.com/wearedevx/keystone/cli/internal/envfile"
 	"github.com/wearedevx/keystone/cli/internal/environmentsfile"
 	kserrors "github.com/wearedevx/keystone/cli/internal/errors"
+	"github.com/wearedevx/keystone/cli/internal/keystonefile"
 	"github.com/wearedevx/keystone/cli/internal/utils"
 	"github.com/wearedevx/keystone/cli/pkg/constants"
 )
@@ -34,24 +35,17 @@ func (ctx *Context) CurrentEnvironment() string {
 	return environmentsfile.Current
 }
 
-func (ctx *Context) mustEnvironmentNameBeValid(name string) {
-	valid := false
+var environmentNameRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9\-\_]{0,62}$`)
 
-	switch constants.EnvName(name) {
-	case constants.DEV:
-		valid = true
-	case constants.STAGING:
-		valid = true
-	case constants.PROD:
-		valid = true
-	}
+// IsValidEnvironmentName function returns true if `name` can be used
+// as an environment name
+func IsValidEnvironmentName(name string) bool {
+	return environmentNameRegex.MatchString(name)
+}
 
-	if !valid {
-		kserrors.EnvironmentDoesntExist(
-			name,
-			constants.EnvList.String(),
-			nil,
-		).Print()
+func (ctx *Context) mustEnvironmentNameBeValid(name string) {
+	if !IsValidEnvironmentName(name) {
+		kserrors.BadEnvironmentName(name, nil).Print()
 
 		os.Exit(1)
 	}
@@ -177,8 +171,7 @@ func (ctx *Context) SetAllSecrets(
 	if ctx.HasEnvironment(name) {
 		dotEnvPath := ctx.CachedEnvironmentDotEnvPath(name)
 
-		if err := new(envfile.EnvFile).
-			Load(dotEnvPath, nil).
+		if err := ctx.loadCachedDotEnv(dotEnvPath).
 			SetData(secrets).
 			Dump().
 			Err(); err != nil {
@@ -198,7 +191,8 @@ func (ctx *Context) SetAllSecrets(
 	return ctx
 }
 
-// GetAllSecrets method returns all the secrets and thei value for the given environment
+// GetAllSecrets method returns all the secrets and thei value for the given environment.
+// Values that are not set in the environment are inherited from its parents.
 func (ctx *Context) GetAllSecrets(envName string) map[string]string {
 	emptyMap := map[string]string{}
 
@@ -207,16 +201,30 @@ func (ctx *Context) GetAllSecrets(envName string) map[string]string {
 	}
 
 	if ctx.HasEnvironment(envName) {
-		dotEnvPath := ctx.CachedEnvironmentDotEnvPath(envName)
+		valuesByEnvironment := map[string]map[string]string{}
+
+		// Unset values are inherited from the parents
+		environments := append([]string{envName}, ctx.EnvironmentParents(envName)...)
+		for _, environment := range environments {
+			if !ctx.HasEnvironment(environment) {
+				continue
+			}
+
+			dotEnvPath := ctx.CachedEnvironmentDotEnvPath(environment)
 
-		envFile := new(envfile.EnvFile).Load(dotEnvPath, nil)
+			envFile := ctx.loadCachedDotEnv(dotEnvPath)
 
-		if err := envFile.Err(); err != nil {
-			ctx.setError(kserrors.FailedToReadDotEnv(dotEnvPath, err))
-			return emptyMap
+			if err := envFile.Err(); err != nil {
+				ctx.setError(kserrors.FailedToReadDotEnv(dotEnvPath, err))
+				return emptyMap
+			}
+
+			valuesByEnvironment[environment] = envFile.GetData()
 		}
 
-		return envFile.GetData()
+		resolved, _ := ctx.inheritValues(valuesByEnvironment)
+
+		return resolved[envName]
 	} else {
 		ctx.setError(kserrors.EnvironmentDoesntExist(envName, strings.Join(ctx.ListEnvironments(), ", "), nil))
 	}
@@ -350,3 +358,236 @@ func (ctx *Context) RemoveForbiddenEnvironments(
 		}
 	}
 }
+
+// SyncEnvironments method brings the local environments in line with
+// the ones the user has access to on the server:
+// renamed environments are renamed in the cache, new ones are created
+// (with an empty version, so that their secrets get fetched), and the
+// ones that are no longer accessible are removed.
+// If the current environment disappeared, dev becomes the current one.
+func (ctx *Context) SyncEnvironments(
+	accessibleEnvironments []models.Environment,
+) *Context {
+	if ctx.Err() != nil {
+		return ctx
+	}
+
+	environmentsFile := ctx.LoadEnvironmentsFile()
+	if err := environmentsFile.Err(); err != nil {
+		return ctx.setError(
+			kserrors.CannotReadEnvironment(environmentsFile.Path(), err),
+		)
+	}
+
+	for _, environment := range accessibleEnvironments {
+		local := environmentsFile.GetByID(environment.EnvironmentID)
+
+		switch {
+		case local == nil:
+			// An environment with the same name may have been removed,
+			// then created again
+			environment.VersionID = ""
+			environmentsFile.
+				RemoveEnvironment(environment.Name).
+				Replace(environment)
+
+		case local.Name != environment.Name:
+			if e := ctx.renameEnvironmentCache(
+				local.Name,
+				environment.Name,
+			); e != nil {
+				return ctx.setError(e)
+			}
+
+			environmentsFile.Rename(local.Name, environment.Name)
+		}
+
+		if err := ctx.initEnvironmentCache(environment.Name); err != nil {
+			return ctx.setError(kserrors.InitFailed(err))
+		}
+	}
+
+	accessibleNames := make([]string, 0, len(accessibleEnvironments))
+	for _, environment := range accessibleEnvironments {
+		accessibleNames = append(accessibleNames, environment.Name)
+	}
+
+	for _, local := range environmentsFile.Environments {
+		if !Contains(accessibleNames, local.Name) {
+			environmentsFile.RemoveEnvironment(local.Name)
+		}
+	}
+
+	if !Contains(accessibleNames, environmentsFile.Current) &&
+		len(accessibleNames) > 0 {
+		fallback := accessibleNames[0]
+		if Contains(accessibleNames, string(constants.DEV)) {
+			fallback = string(constants.DEV)
+		}
+
+		environmentsFile.SetCurrent(fallback)
+	}
+
+	if err := environmentsFile.Save().Err(); err != nil {
+		return ctx.setError(kserrors.FailedToUpdateKeystoneFile(err))
+	}
+
+	ctx.RemoveForbiddenEnvironments(accessibleEnvironments)
+
+	return ctx
+}
+
+// AddLocalEnvironment method creates the cache for a new environment,
+// and registers it in the environments file
+func (ctx *Context) AddLocalEnvironment(environment models.Environment) *Context {
+	if ctx.Err() != nil {
+		return ctx
+	}
+
+	if err := ctx.initEnvironmentCache(environment.Name); err != nil {
+		return ctx.setError(kserrors.InitFailed(err))
+	}
+
+	ctx.AccessibleEnvironments = append(ctx.AccessibleEnvironments, environment)
+
+	return ctx.UpdateEnvironment(environment)
+}
+
+// RenameLocalEnvironment method renames the cache of an environment,
+// its entry in the environments file, and its references in keystone.yaml
+func (ctx *Context) RenameLocalEnvironment(name string, newName string) *Context {
+	if ctx.Err() != nil {
+		return ctx
+	}
+
+	if e := ctx.renameEnvironmentCache(name, newName); e != nil {
+		return ctx.setError(e)
+	}
+
+	environmentsFile := ctx.LoadEnvironmentsFile()
+	if err := environmentsFile.
+		Rename(name, newName).
+		Save().
+		Err(); err != nil {
+		return ctx.setError(kserrors.FailedToUpdateKeystoneFile(err))
+	}
+
+	if err := keystonefile.LoadKeystoneFile(ctx.Wd).
+		RenameEnvironment(name, newName).
+		Save().
+		Err(); err != nil {
+		return ctx.setError(kserrors.FailedToUpdateKeystoneFile(err))
+	}
+
+	for index, environment := range ctx.AccessibleEnvironments {
+		if environment.Name == name {
+			ctx.AccessibleEnvironments[index].Name = newName
+		}
+	}
+
+	return ctx
+}
+
+// CheckEnvironmentRemovable method sets an error if other environments
+// inherit from `name` in keystone.yaml
+func (ctx *Context) CheckEnvironmentRemovable(name string) *Context {
+	if ctx.Err() != nil {
+		return ctx
+	}
+
+	ksfile := keystonefile.LoadKeystoneFile(ctx.Wd)
+	if err := ksfile.Err(); err != nil {
+		return ctx.setError(kserrors.FailedToReadKeystoneFile(ksfile.Path, err))
+	}
+
+	if children := ksfile.Children(name); len(children) > 0 {
+		return ctx.setError(
+			kserrors.EnvironmentIsInherited(
+				name,
+				strings.Join(children, ", "),
+				nil,
+			),
+		)
+	}
+
+	return ctx
+}
+
+// RemoveLocalEnvironment method removes the cache of an environment,
+// its entry in the environments file, and its settings in keystone.yaml.
+// If it is the current environment, dev becomes the current one.
+// Environments inheriting from it must have been changed first,
+// see `Context.CheckEnvironmentRemovable`.
+func (ctx *Context) RemoveLocalEnvironment(name string) *Context {
+	if ctx.CheckEnvironmentRemovable(name).Err() != nil {
+		return ctx
+	}
+
+	if ctx.CurrentEnvironment() == name {
+		ctx.SetCurrent(string(constants.DEV))
+	}
+
+	ctx.RemoveEnvironment(name)
+
+	environmentsFile := ctx.LoadEnvironmentsFile()
+	if err := environmentsFile.
+		RemoveEnvironment(name).
+		Save().
+		Err(); err != nil {
+		return ctx.setError(kserrors.FailedToUpdateKeystoneFile(err))
+	}
+
+	if err := keystonefile.LoadKeystoneFile(ctx.Wd).
+		RemoveEnvironment(name).
+		Save().
+		Err(); err != nil {
+		return ctx.setError(kserrors.FailedToUpdateKeystoneFile(err))
+	}
+
+	accessibleEnvironments := make([]models.Environment, 0)
+	for _, environment := range ctx.AccessibleEnvironments {
+		if environment.Name != name {
+			accessibleEnvironments = append(accessibleEnvironments, environment)
+		}
+	}
+	ctx.AccessibleEnvironments = accessibleEnvironments
+
+	return ctx
+}
+
+// initEnvironmentCache creates the cache directory of an environment,
+// with an empty .env and a directory for files
+func (ctx *Context) initEnvironmentCache(name string) error {
+	if err := utils.CreateDirIfNotExist(
+		ctx.CachedEnvironmentPath(name),
+	); err != nil {
+		return err
+	}
+
+	if err := utils.CreateFileIfNotExists(
+		ctx.CachedEnvironmentDotEnvPath(name),
+		"",
+	); err != nil {
+		return err
+	}
+
+	return utils.CreateDirIfNotExist(ctx.CachedEnvironmentFilesPath(name))
+}
+
+// renameEnvironmentCache moves the cache directory of an environment
+// when it gets renamed
+func (ctx *Context) renameEnvironmentCache(
+	name string,
+	newName string,
+) *kserrors.Error {
+	oldPath := ctx.CachedEnvironmentPath(name)
+	newPath := ctx.CachedEnvironmentPath(newName)
+
+	if utils.DirExists(oldPath) && !utils.DirExists(newPath) {
+		if err := os.Rename(oldPath, newPath); err != nil {
+			return kserrors.CopyFailed(oldPath, newPath, err)
+		}
+	}
+
+	return nil
+}
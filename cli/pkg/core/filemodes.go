@@ -0,0 +1,135 @@
+package core
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+
+	kserrors "github.com/wearedevx/keystone/cli/internal/errors"
+	"github.com/wearedevx/keystone/cli/internal/keystonefile"
+	"github.com/wearedevx/keystone/cli/internal/utils"
+)
+
+// CachedFileModes method returns the modes of the files of
+// `environmentName`, by path, as they were when their content was set
+func (ctx *Context) CachedFileModes(
+	environmentName string,
+) map[string]keystonefile.FileMode {
+	modes := make(map[string]keystonefile.FileMode)
+	if ctx.Err() != nil {
+		return modes
+	}
+
+	modesPath := ctx.CachedEnvironmentFileModesPath(environmentName)
+	if !utils.FileExists(modesPath) {
+		return modes
+	}
+
+	content, err := ctx.ReadCachedFile(modesPath)
+	if err != nil {
+		ctx.setError(kserrors.FailedToReadCache(modesPath, err))
+		return modes
+	}
+
+	if err = json.Unmarshal(content, &modes); err != nil {
+		ctx.setError(kserrors.FailedToReadCache(modesPath, err))
+	}
+
+	return modes
+}
+
+// setCachedFileModes method replaces the modes of the files of
+// `environmentName` in cache
+func (ctx *Context) setCachedFileModes(
+	environmentName string,
+	modes map[string]keystonefile.FileMode,
+) error {
+	content, err := json.Marshal(modes)
+	if err != nil {
+		return err
+	}
+
+	return ctx.writeCachedFile(
+		ctx.CachedEnvironmentFileModesPath(environmentName),
+		content,
+	)
+}
+
+// setCachedFileMode method records the mode of the file `filePath` for
+// `environmentName`.
+// A zero mode removes it.
+func (ctx *Context) setCachedFileMode(
+	environmentName string,
+	filePath string,
+	mode keystonefile.FileMode,
+) error {
+	modes := ctx.CachedFileModes(environmentName)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if modes[filePath] == mode {
+		return nil
+	}
+
+	if mode == 0 {
+		delete(modes, filePath)
+	} else {
+		modes[filePath] = mode
+	}
+
+	return ctx.setCachedFileModes(environmentName, modes)
+}
+
+// FileMode method returns the permissions the file `filePath` is written
+// with for `environmentName`: the ones of the local file it was set from,
+// otherwise the ones given in the keystone file,
+// or `keystonefile.DefaultFileMode`.
+func (ctx *Context) FileMode(
+	filePath string,
+	environmentName string,
+) os.FileMode {
+	_, fromEnvironment := ctx.CachedFilePathForEnvironment(
+		environmentName,
+		filePath,
+	)
+
+	if mode := ctx.CachedFileModes(fromEnvironment)[filePath]; mode != 0 {
+		return mode.Perm()
+	}
+
+	for _, file := range ctx.ListFiles() {
+		if file.Path == filePath || file.Contains(filePath) {
+			return file.Mode.Perm()
+		}
+	}
+
+	return keystonefile.DefaultFileMode
+}
+
+// localFileMode returns the permissions of the local version of the file
+// `filePath`, or 0 if it does not exist
+func (ctx *Context) localFileMode(filePath string) keystonefile.FileMode {
+	info, err := os.Stat(path.Join(ctx.Wd, filePath))
+	if err != nil {
+		return 0
+	}
+
+	return keystonefile.FileMode(info.Mode().Perm())
+}
+
+// WorldReadableFiles method returns the files of `environmentName` whose
+// local version anyone can read
+func (ctx *Context) WorldReadableFiles(environmentName string) []string {
+	files := make([]string, 0)
+
+	for _, file := range ctx.ListExpandedFiles(environmentName) {
+		mode := ctx.localFileMode(file.Path)
+
+		if mode != 0 && mode.IsWorldReadable() {
+			files = append(files, file.Path)
+		}
+	}
+
+	return files
+}
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -34,17 +35,25 @@ type FileDescriptor struct {
 	Path      string
 }
 
+// fileKeyToFileDescriptor returns a FileDescriptor for the file, or for
+// each file a directory entry contains
 func (ctx *Context) fileKeyToFileDescriptor(
 	file keystonefile.FileKey,
 	environmentName string,
 	asAvailable bool,
-) FileDescriptor {
-	return FileDescriptor{
-		Path:      file.Path,
-		Required:  file.Strict,
-		Modified:  ctx.IsFileModified(file.Path, environmentName),
-		Available: asAvailable,
+) []FileDescriptor {
+	descriptors := make([]FileDescriptor, 0)
+
+	for _, f := range ctx.ExpandFileKey(file, environmentName) {
+		descriptors = append(descriptors, FileDescriptor{
+			Path:      f.Path,
+			Required:  f.IsRequiredIn(environmentName),
+			Modified:  ctx.IsFileModified(f.Path, environmentName),
+			Available: asAvailable,
+		})
 	}
+
+	return descriptors
 }
 
 /// Returns a list of display friendly FileDescriptors,
@@ -63,7 +72,7 @@ func (ctx *Context) ListAllFiles(environmentName string) []FileDescriptor {
 	for _, file := range filesInKeystoneFile {
 		result = append(
 			result,
-			ctx.fileKeyToFileDescriptor(file, environmentName, false),
+			ctx.fileKeyToFileDescriptor(file, environmentName, false)...,
 		)
 	}
 
@@ -76,7 +85,7 @@ func (ctx *Context) ListAllFiles(environmentName string) []FileDescriptor {
 				filepath.Join(ctx.Wd, cachedFile.Path),
 			)
 
-			if fileAbs == cacheFileAbs {
+			if fileAbs == cacheFileAbs || file.Contains(cachedFile.Path) {
 				used = true
 				break
 			}
@@ -85,7 +94,7 @@ func (ctx *Context) ListAllFiles(environmentName string) []FileDescriptor {
 		if !used {
 			result = append(
 				result,
-				ctx.fileKeyToFileDescriptor(cachedFile, environmentName, true),
+				ctx.fileKeyToFileDescriptor(cachedFile, environmentName, true)...,
 			)
 		}
 	}
@@ -110,6 +119,93 @@ func (ctx *Context) ListFiles() []keystonefile.FileKey {
 	return ksfile.Files
 }
 
+// ListExpandedFiles method returns the files in the keystone file,
+// with directory entries replaced by the files they contain
+// for `environmentName`
+func (ctx *Context) ListExpandedFiles(
+	environmentName string,
+) []keystonefile.FileKey {
+	files := make([]keystonefile.FileKey, 0)
+
+	for _, file := range ctx.ListFiles() {
+		files = append(files, ctx.ExpandFileKey(file, environmentName)...)
+	}
+
+	return files
+}
+
+// ExpandFileKey method returns the files a directory entry contains
+// in cache for `environmentName`, or inherits from its parents.
+// A single file entry is returned as is.
+func (ctx *Context) ExpandFileKey(
+	file keystonefile.FileKey,
+	environmentName string,
+) []keystonefile.FileKey {
+	if ctx.Err() != nil || !file.Directory {
+		return []keystonefile.FileKey{file}
+	}
+
+	environments := append(
+		[]string{environmentName},
+		ctx.EnvironmentParents(environmentName)...,
+	)
+	filePaths := make([]string, 0)
+
+	for _, environment := range environments {
+		if !ctx.HasEnvironment(environment) {
+			continue
+		}
+
+		filePaths = append(
+			filePaths,
+			filesUnder(ctx.CachedEnvironmentFilesPath(environment), file.Path)...,
+		)
+	}
+
+	return file.Expand(filePaths)
+}
+
+// TracksFile method returns true if `filePath` is in the keystone file,
+// or is contained in one of its directory entries
+func (ctx *Context) TracksFile(filePath string) bool {
+	if ctx.HasFile(filePath) {
+		return true
+	}
+
+	for _, file := range ctx.ListFiles() {
+		if file.Contains(filePath) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// filesUnder returns the paths of the files under `root/directory`,
+// relative to `root`
+func filesUnder(root string, directory string) []string {
+	filePaths := make([]string, 0)
+
+	_ = filepath.Walk(
+		filepath.Join(root, directory),
+		func(p string, info os.FileInfo, err error) error {
+			// Unreadable or missing directories have no files
+			if err != nil || info.IsDir() {
+				return nil
+			}
+
+			relativePath, err := filepath.Rel(root, p)
+			if err == nil {
+				filePaths = append(filePaths, filepath.ToSlash(relativePath))
+			}
+
+			return nil
+		},
+	)
+
+	return filePaths
+}
+
 // ListCachedFilesForEnvironment method returns a list of all the files
 // present in the cache for the given environment
 func (ctx *Context) ListCachedFilesForEnvironment(
@@ -232,6 +328,11 @@ func (ctx *Context) AddFile(
 		return ctx
 	}
 
+	// Files are written with the permissions of the local one
+	if !file.Directory && file.Mode == 0 {
+		file.Mode = ctx.localFileMode(file.Path)
+	}
+
 	// Add file path to the keystone file
 	if err := new(keystonefile.KeystoneFile).Load(ctx.Wd).
 		AddFile(file).
@@ -240,18 +341,27 @@ func (ctx *Context) AddFile(
 		return ctx.setError(kserrors.FailedToUpdateKeystoneFile(err))
 	}
 
+	if file.Directory {
+		return ctx.addDirectory(file)
+	}
+
 	environments := ctx.ListEnvironments()
 	current := ctx.CurrentEnvironment()
 
 	// Use current content for current environment.
 	src := path.Join(ctx.Wd, file.Path)
 	dest := path.Join(ctx.CachedEnvironmentFilesPath(current), file.Path)
-	dir := filepath.Dir(dest)
-	if err := os.MkdirAll(dir, 0o700); err != nil {
+	/* #nosec */
+	content, err := ioutil.ReadFile(src)
+	if err != nil {
+		return ctx.setError(kserrors.CopyFailed(file.Path, dest, err))
+	}
+
+	if err := ctx.writeCachedFile(dest, content); err != nil {
 		return ctx.setError(kserrors.CopyFailed(file.Path, dest, err))
 	}
 
-	if err := utils.CopyFile(src, dest); err != nil {
+	if err := ctx.setCachedFileMode(current, file.Path, file.Mode); err != nil {
 		return ctx.setError(kserrors.CopyFailed(file.Path, dest, err))
 	}
 
@@ -261,38 +371,70 @@ func (ctx *Context) AddFile(
 			ctx.CachedEnvironmentFilesPath(environment),
 			file.Path,
 		)
-		parentDir := filepath.Dir(dest) + string(os.PathSeparator)
-
-		if err := os.MkdirAll(parentDir, 0o700); err != nil {
-			ctx.setError(kserrors.CannotCreateDirectory(parentDir, err))
-			return ctx
+		content, ok := envContentMap[environment]
+		if !ok && utils.FileExists(dest) {
+			continue
 		}
 
 		/* #nosec
 		 * As long as the `current` values is checked to be
 		 * a valid environment name
 		 */
-		destFile, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE, 0o644)
-		if err == nil {
-			defer closeFile(destFile)
-
-			_, err = destFile.Write(envContentMap[environment])
-		}
-
-		if err != nil {
+		if err := ctx.writeCachedFile(dest, content); err != nil {
 			println(fmt.Sprintf("Failed to write %s (%s)", dest, err.Error()))
 			os.Exit(1)
 		}
+
+		if err := ctx.setCachedFileMode(environment, file.Path, file.Mode); err != nil {
+			return ctx.setError(kserrors.CopyFailed(file.Path, dest, err))
+		}
 	}
 
 	return ctx
 }
 
-func closeFile(file *os.File) {
-	err := file.Close()
-	if err != nil {
-		panic(err)
+// addDirectory method copies the local files a directory entry contains
+// in cache for the current environment.
+// Other environments get the same content, unless they already have some.
+func (ctx *Context) addDirectory(file keystonefile.FileKey) *Context {
+	cachePath := ctx.CachedEnvironmentFilesPath(ctx.CurrentEnvironment())
+
+	if ctx.SetDirectory(file.Path).Err() != nil {
+		return ctx
+	}
+
+	for _, f := range file.Expand(filesUnder(cachePath, file.Path)) {
+		src := path.Join(cachePath, f.Path)
+
+		content, err := ctx.ReadCachedFile(src)
+		if err != nil {
+			return ctx.setError(kserrors.CopyFailed(f.Path, src, err))
+		}
+
+		for _, environment := range ctx.ListEnvironments() {
+			dest := path.Join(
+				ctx.CachedEnvironmentFilesPath(environment),
+				f.Path,
+			)
+			if utils.FileExists(dest) {
+				continue
+			}
+
+			if err := ctx.writeCachedFile(dest, content); err != nil {
+				return ctx.setError(kserrors.CopyFailed(f.Path, dest, err))
+			}
+
+			if err := ctx.setCachedFileMode(
+				environment,
+				f.Path,
+				ctx.localFileMode(f.Path),
+			); err != nil {
+				return ctx.setError(kserrors.CopyFailed(f.Path, dest, err))
+			}
+		}
 	}
+
+	return ctx
 }
 
 func (ctx *Context) fileBelongsToContext(filePath string) (belong bool) {
@@ -310,7 +452,8 @@ func (ctx *Context) fileBelongsToContext(filePath string) (belong bool) {
 	return strings.HasPrefix(fp, absWd)
 }
 
-// SetFile method changes the files content
+// SetFile method changes the files content.
+// The permissions of the local file, if any, are kept as well.
 func (ctx *Context) SetFile(filePath string, content []byte) *Context {
 	if ctx.Err() != nil {
 		return ctx
@@ -334,28 +477,65 @@ func (ctx *Context) SetFile(filePath string, content []byte) *Context {
 		return ctx
 	}
 
-	parentDir := path.Dir(dest)
+	if err := ctx.writeCachedFile(dest, content); err != nil {
+		println(fmt.Sprintf("Failed to write %s (%s)", dest, err.Error()))
+		os.Exit(1)
+	}
+
+	if mode := ctx.localFileMode(filePath); mode != 0 {
+		if err := ctx.setCachedFileMode(currentEnvironment, filePath, mode); err != nil {
+			return ctx.setError(kserrors.CannotSetFile(filePath, err))
+		}
+	}
 
-	if err := os.MkdirAll(parentDir, 0o700); err != nil {
-		ctx.setError(kserrors.CannotCreateDirectory(parentDir, err))
+	return ctx
+}
+
+// SetDirectory method replaces the files of the directory entry
+// `directoryPath` in cache for the current environment with the local ones:
+// files that have been removed locally are removed from the cache too.
+func (ctx *Context) SetDirectory(directoryPath string) *Context {
+	if ctx.Err() != nil {
 		return ctx
 	}
 
-	/* #nosec */
-	destFile, err := os.OpenFile(
-		dest,
-		os.O_WRONLY|os.O_TRUNC|os.O_CREATE,
-		0o644,
-	)
-	if err == nil {
-		defer closeFile(destFile)
+	var directory keystonefile.FileKey
+	found := false
 
-		_, err = destFile.Write(content)
+	for _, file := range ctx.ListFiles() {
+		if file.Directory && file.Path == directoryPath {
+			directory = file
+			found = true
+		}
 	}
 
-	if err != nil {
-		println(fmt.Sprintf("Failed to write %s (%s)", dest, err.Error()))
-		os.Exit(1)
+	if !found {
+		return ctx.setError(kserrors.CannotSetFile(
+			directoryPath,
+			errors.New("directory not added to project"),
+		))
+	}
+
+	cachePath := ctx.CachedEnvironmentFilesPath(ctx.CurrentEnvironment())
+	localFiles := directory.Expand(filesUnder(ctx.Wd, directory.Path))
+
+	for _, file := range directory.Expand(filesUnder(cachePath, directory.Path)) {
+		if !utils.FileExists(path.Join(ctx.Wd, file.Path)) {
+			if err := os.Remove(path.Join(cachePath, file.Path)); err != nil {
+				return ctx.setError(kserrors.CannotSetFile(file.Path, err))
+			}
+		}
+	}
+
+	for _, file := range localFiles {
+		content, err := ctx.GetLocalFileContents(file.Path)
+		if err != nil {
+			return ctx.setError(kserrors.CannotSetFile(file.Path, err))
+		}
+
+		if ctx.SetFile(file.Path, content).Err() != nil {
+			return ctx
+		}
 	}
 
 	return ctx
@@ -371,7 +551,7 @@ func (ctx *Context) LocallyModifiedFiles(
 		return []keystonefile.FileKey{}
 	}
 
-	files := ctx.ListFiles()
+	files := ctx.ListExpandedFiles(envname)
 	modified := make([]keystonefile.FileKey, 0)
 
 	for _, fileKey := range files {
@@ -395,14 +575,12 @@ func (ctx *Context) IsFileModified(
 		return false
 	}
 	var localPath, cachedPath string
-	var localReader, cachedReader *os.File
+	var localReader *os.File
+	var cachedContent []byte
 	var err error
 
 	localPath = path.Join(ctx.Wd, filePath)
-	cachedPath = path.Join(
-		ctx.CachedEnvironmentFilesPath(environment),
-		filePath,
-	)
+	cachedPath, _ = ctx.CachedFilePathForEnvironment(environment, filePath)
 
 	if !ctx.fileBelongsToContext(localPath) {
 		kserrors.FileNotInWorkingDirectory(localPath, ctx.Wd, nil).Print()
@@ -419,8 +597,7 @@ func (ctx *Context) IsFileModified(
 	if err != nil {
 		return false
 	}
-	/* #nosec */
-	cachedReader, err = os.Open(cachedPath)
+	cachedContent, err = ctx.ReadCachedFile(cachedPath)
 	if err != nil {
 		ui.PrintStdErr(
 			ui.RenderTemplate(
@@ -440,20 +617,25 @@ func (ctx *Context) IsFileModified(
 	}
 
 	comparator := equalfile.New(nil, equalfile.Options{})
-	sameContent, err := comparator.CompareReader(localReader, cachedReader)
+	sameContent, err := comparator.CompareReader(
+		localReader,
+		bytes.NewReader(cachedContent),
+	)
 	if err != nil {
 		ctx.setError(kserrors.CannotCopyFile(localPath, cachedPath, err))
 		return false
 	}
 
 	utils.Close(localReader)
-	utils.Close(cachedReader)
 
 	return !sameContent
 }
 
 // FilesUseEnvironment creates copies of files found in the project’s
 // keystone.yaml file, from the environment `targetEnvironment` in cache.
+// Files that are not set in `targetEnvironment` are taken from its parents.
+// Files of directory entries that `targetEnvironment` does not have are
+// removed, unless they have been locally modified.
 func (ctx *Context) FilesUseEnvironment(
 	currentEnvironment string,
 	targetEnvironment string,
@@ -469,68 +651,163 @@ func (ctx *Context) FilesUseEnvironment(
 		return ctx.setError(kserrors.FailedToReadKeystoneFile(ksfile.Path, err))
 	}
 
-	cachePath := ctx.CachedEnvironmentFilesPath(targetEnvironment)
 	files := ksfile.Files
 
 	for _, file := range files {
-		cachedFilePath := path.Join(cachePath, file.Path)
+		if !file.Directory {
+			ctx.fileUseEnvironment(
+				file,
+				currentEnvironment,
+				targetEnvironment,
+				forceCopy,
+			)
+		} else {
+			ctx.directoryUseEnvironment(
+				file,
+				currentEnvironment,
+				targetEnvironment,
+				forceCopy,
+			)
+		}
+
+		if ctx.Err() != nil {
+			return ctx
+		}
+
+		gitignorehelper.GitIgnore(ctx.Wd, file.Path)
+	}
+
+	return ctx
+}
+
+// directoryUseEnvironment method copies the files of a directory entry
+// from the environment `targetEnvironment` in cache
+func (ctx *Context) directoryUseEnvironment(
+	directory keystonefile.FileKey,
+	currentEnvironment string,
+	targetEnvironment string,
+	forceCopy bool,
+) *Context {
+	targetFiles := ctx.ExpandFileKey(directory, targetEnvironment)
+
+	for _, file := range ctx.ExpandFileKey(directory, currentEnvironment) {
+		if containsFileKey(targetFiles, file.Path) {
+			continue
+		}
+
 		localPath := path.Join(ctx.Wd, file.Path)
 
-		if !utils.FileExists(cachedFilePath) {
-			if file.Strict {
-				return ctx.setError(
-					kserrors.FileNotInEnvironment(
-						file.Path,
-						targetEnvironment,
-						nil,
-					),
-				)
+		if utils.FileExists(localPath) &&
+			(forceCopy || !ctx.IsFileModified(file.Path, currentEnvironment)) {
+			if err := os.Remove(localPath); err != nil {
+				return ctx.setError(kserrors.UnkownError(err))
 			}
-			ui.PrintStdErr("File \"%s\" not in environment\n", file.Path)
 		}
+	}
+
+	for _, file := range targetFiles {
+		if ctx.fileUseEnvironment(
+			file,
+			currentEnvironment,
+			targetEnvironment,
+			forceCopy,
+		).Err() != nil {
+			return ctx
+		}
+	}
+
+	return ctx
+}
 
-		if ctx.IsFileModified(file.Path, currentEnvironment) &&
-			!forceCopy {
-			ui.PrintStdErr(ui.RenderTemplate(
-				"modified file",
-				`{{ "Warning!" | yellow }} File '{{ .Path }}' has been locally modified.
+// fileUseEnvironment method copies a file from the environment
+// `targetEnvironment` in cache, or from its parents
+func (ctx *Context) fileUseEnvironment(
+	file keystonefile.FileKey,
+	currentEnvironment string,
+	targetEnvironment string,
+	forceCopy bool,
+) *Context {
+	// Content missing in the target environment is inherited
+	// from its parents
+	cachedFilePath, _ := ctx.CachedFilePathForEnvironment(
+		targetEnvironment,
+		file.Path,
+	)
+	localPath := path.Join(ctx.Wd, file.Path)
+
+	if !utils.FileExists(cachedFilePath) {
+		if file.IsRequiredIn(targetEnvironment) {
+			return ctx.setError(
+				kserrors.FileNotInEnvironment(
+					file.Path,
+					targetEnvironment,
+					nil,
+				),
+			)
+		}
+		ui.PrintStdErr("File \"%s\" not in environment\n", file.Path)
+	}
+
+	if ctx.IsFileModified(file.Path, currentEnvironment) &&
+		!forceCopy {
+		ui.PrintStdErr(ui.RenderTemplate(
+			"modified file",
+			`{{ "Warning!" | yellow }} File '{{ .Path }}' has been locally modified.
 {{ "Warning!" | yellow }}     To discard local changes, run 'ks file reset {{ .Path }}'.
 {{ "Warning!" | yellow }}     To validate them and share them with all members, run 'ks file set {{ .Path }}'`,
-				file,
-			))
-		} else {
-			if utils.FileExists(localPath) {
-				if err := os.Remove(localPath); err != nil {
-					return ctx.
-						setError(
-							kserrors.
-								CannotCopyFile(file.Path, cachedFilePath, err),
-						)
-				}
-			}
+			file,
+		))
 
-			parentDir := filepath.Dir(localPath)
+		return ctx
+	}
 
-			if err := os.MkdirAll(parentDir, 0o700); err != nil {
-				return ctx.setError(kserrors.CannotCopyFile(file.Path, cachedFilePath, err))
-			}
+	if utils.FileExists(localPath) {
+		if err := os.Remove(localPath); err != nil {
+			return ctx.
+				setError(
+					kserrors.
+						CannotCopyFile(file.Path, cachedFilePath, err),
+				)
+		}
+	}
 
-			if utils.FileExists(cachedFilePath) {
-				if err := utils.CopyFile(cachedFilePath, localPath); err != nil {
-					return ctx.setError(kserrors.CannotCopyFile(file.Path, cachedFilePath, err))
-				}
-			} else {
-				if err := utils.CreateFileIfNotExists(localPath, ""); err != nil {
-					return ctx.setError(kserrors.CannotCopyFile(file.Path, cachedFilePath, err))
-				}
-			}
+	parentDir := filepath.Dir(localPath)
+
+	if err := os.MkdirAll(parentDir, 0o700); err != nil {
+		return ctx.setError(kserrors.CannotCopyFile(file.Path, cachedFilePath, err))
+	}
+
+	mode := ctx.FileMode(file.Path, targetEnvironment)
+
+	if utils.FileExists(cachedFilePath) {
+		if err := ctx.CopyFromCacheWithMode(cachedFilePath, localPath, mode); err != nil {
+			return ctx.setError(kserrors.CannotCopyFile(file.Path, cachedFilePath, err))
+		}
+	} else {
+		if err := utils.CreateFileIfNotExists(localPath, ""); err != nil {
+			return ctx.setError(kserrors.CannotCopyFile(file.Path, cachedFilePath, err))
+		}
+
+		if err := os.Chmod(localPath, mode); err != nil {
+			return ctx.setError(kserrors.CannotCopyFile(file.Path, cachedFilePath, err))
 		}
-		gitignorehelper.GitIgnore(ctx.Wd, file.Path)
 	}
 
 	return ctx
 }
 
+// containsFileKey returns true if one of the `files` has the path
+// `filePath`
+func containsFileKey(files []keystonefile.FileKey, filePath string) bool {
+	for _, file := range files {
+		if file.Path == filePath {
+			return true
+		}
+	}
+
+	return false
+}
+
 // RemoveFile method removes a file from the keystonefile.
 // `purge` also removes the file from the cache
 func (ctx *Context) RemoveFile(
@@ -552,11 +829,13 @@ func (ctx *Context) RemoveFile(
 
 	filteredFiles := make([]keystonefile.FileKey, 0)
 	found := false
+	isDirectory := false
 	for _, file := range ksfile.Files {
 		if file.Path != filePath {
 			filteredFiles = append(filteredFiles, file)
 		} else {
 			found = true
+			isDirectory = file.Directory
 		}
 	}
 	if !found {
@@ -574,6 +853,10 @@ func (ctx *Context) RemoveFile(
 
 	dest := path.Join(ctx.Wd, filePath)
 
+	if isDirectory {
+		return ctx.removeDirectory(filePath, force, purge, accessibleEnvironments)
+	}
+
 	if force {
 		fmt.Println("Force remove file on filesystem.")
 		if err := os.Remove(dest); err != nil {
@@ -588,7 +871,11 @@ func (ctx *Context) RemoveFile(
 			return ctx.setError(kserrors.UnkownError(err))
 		}
 
-		if err := utils.CopyFile(currentCached, dest); err != nil {
+		if err := ctx.CopyFromCacheWithMode(
+			currentCached,
+			dest,
+			ctx.FileMode(filePath, currentEnvironment),
+		); err != nil {
 			return ctx.setError(kserrors.CopyFailed(currentCached, dest, err))
 		}
 	}
@@ -615,6 +902,42 @@ func (ctx *Context) RemoveFile(
 	return ctx
 }
 
+// removeDirectory method removes the files of a directory entry that
+// has been removed from the keystone file.
+// Local files are kept, unless `force` is true.
+func (ctx *Context) removeDirectory(
+	directoryPath string,
+	force bool,
+	purge bool,
+	accessibleEnvironments []models.Environment,
+) *Context {
+	if force {
+		fmt.Println("Force remove directory on filesystem.")
+		if err := os.RemoveAll(path.Join(ctx.Wd, directoryPath)); err != nil {
+			return ctx.setError(kserrors.UnkownError(err))
+		}
+	}
+
+	if purge {
+		for _, environment := range accessibleEnvironments {
+			cachedDirectoryPath := path.Join(
+				ctx.CachedEnvironmentFilesPath(environment.Name),
+				directoryPath,
+			)
+
+			if err := os.RemoveAll(cachedDirectoryPath); err != nil {
+				return ctx.setError(kserrors.UnkownError(err))
+			}
+		}
+	}
+
+	if err := gitignorehelper.GitUnignore(ctx.Wd, directoryPath); err != nil {
+		ctx.setError(kserrors.UnkownError(err))
+	}
+
+	return ctx
+}
+
 // Returns a boolean indicating wether the file `fileName`
 // exists in the local files
 func (ctx *Context) HasFile(fileName string) bool {
@@ -650,7 +973,20 @@ func (ctx *Context) MarkFileRequired(
 		return ctx
 	}
 
-	if err := new(keystonefile.KeystoneFile).Load(ctx.Wd).
+	ksfile := new(keystonefile.KeystoneFile).Load(ctx.Wd)
+
+	// Optional everywhere
+	if !required {
+		for _, file := range ksfile.Files {
+			if file.Path == filePath {
+				for _, environment := range file.RequiredIn {
+					ksfile.SetFileRequiredIn(filePath, environment, false)
+				}
+			}
+		}
+	}
+
+	if err := ksfile.
 		SetFileRequired(filePath, required).
 		Save().
 		Err(); err != nil {
@@ -660,8 +996,43 @@ func (ctx *Context) MarkFileRequired(
 	return ctx
 }
 
+// MarkFileRequiredIn method changes the required status of a file
+// for `environmentName` only.
+// Making a file that is required everywhere optional in one environment
+// makes it required in all the others.
+func (ctx *Context) MarkFileRequiredIn(
+	filePath string,
+	environmentName string,
+	required bool,
+) *Context {
+	if ctx.Err() != nil {
+		return ctx
+	}
+
+	ksfile := new(keystonefile.KeystoneFile).Load(ctx.Wd)
+
+	for _, file := range ksfile.Files {
+		if file.Path == filePath && file.Strict && !required {
+			ksfile.SetFileRequired(filePath, false)
+
+			for _, environment := range ctx.ListEnvironments() {
+				ksfile.SetFileRequiredIn(filePath, environment, true)
+			}
+		}
+	}
+
+	if err := ksfile.
+		SetFileRequiredIn(filePath, environmentName, required).
+		Save().
+		Err(); err != nil {
+		return ctx.setError(kserrors.FailedToUpdateKeystoneFile(err))
+	}
+
+	return ctx
+}
+
 // GetFileContents returns the file contents for the given envsrionment
-// as a slice of bytes.
+// as a slice of bytes, inherited from its parents if the file is not set.
 // It returns an error if reading the file fails (Pemission denied, no exists…)
 // or if the file is empty (content length equals 0)
 func (ctx *Context) GetFileContents(
@@ -671,11 +1042,9 @@ func (ctx *Context) GetFileContents(
 		return nil, ctx.Err()
 	}
 
-	cachePath := ctx.CachedEnvironmentFilesPath(environmentName)
-	filePath := path.Join(cachePath, fileName)
+	filePath, _ := ctx.CachedFilePathForEnvironment(environmentName, fileName)
 
-	/* #nosec */
-	contents, err = ioutil.ReadFile(filePath)
+	contents, err = ctx.ReadCachedFile(filePath)
 	if err != nil {
 		return nil, err
 	}
@@ -716,10 +1085,17 @@ func (ctx *Context) MissingFilesForEnvironment(
 	missing := []string{}
 	hasMissing := false
 
-	files := ctx.ListFiles()
+	for _, file := range ctx.ListFiles() {
+		// A required directory must have at least one file
+		if file.Directory && file.IsRequiredIn(environmentName) &&
+			len(ctx.ExpandFileKey(file, environmentName)) == 0 {
+			hasMissing = true
+			missing = append(missing, file.Path)
+		}
+	}
 
-	for _, file := range files {
-		if file.Strict {
+	for _, file := range ctx.ListExpandedFiles(environmentName) {
+		if file.IsRequiredIn(environmentName) {
 			if _, err := ctx.GetFileContents(file.Path, environmentName); err != nil {
 				hasMissing = true
 				missing = append(missing, file.Path)
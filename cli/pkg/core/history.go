@@ -0,0 +1,176 @@
+package core
+
+import (
+	"crypto/sha256"
+	"time"
+
+	"github.com/wearedevx/keystone/cli/internal/cachefile"
+	"github.com/wearedevx/keystone/cli/internal/config"
+	"github.com/wearedevx/keystone/cli/internal/crypto"
+	kserrors "github.com/wearedevx/keystone/cli/internal/errors"
+	"github.com/wearedevx/keystone/cli/internal/history"
+)
+
+// historyKey encrypts history entries with a key derived from the
+// private key of the user, so that only they can read their local history
+type historyKey []byte
+
+func deriveHistoryKey(privateKey []byte) historyKey {
+	sum := sha256.Sum256(append([]byte("keystone history\x00"), privateKey...))
+
+	return historyKey(sum[:])
+}
+
+func (key historyKey) Encrypt(data []byte) ([]byte, error) {
+	return crypto.EncryptWithKey(key, data)
+}
+
+func (key historyKey) Decrypt(data []byte) ([]byte, error) {
+	return crypto.DecryptWithKey(key, data)
+}
+
+// historyCipher returns the cipher of the histories of the owner of
+// `privateKey`, which also reads the entries encrypted with one of the
+// `previousKeys` of the device.
+func historyCipher(privateKey []byte, previousKeys [][]byte) history.Cipher {
+	previous := make([]cachefile.Cipher, 0, len(previousKeys))
+	for _, key := range previousKeys {
+		previous = append(previous, deriveHistoryKey(key))
+	}
+
+	return cachefile.Fallback(deriveHistoryKey(privateKey), previous...)
+}
+
+// journal returns the history of `envName`, not loaded yet
+func (ctx *Context) journal(envName string) (*history.Journal, *kserrors.Error) {
+	historyPath := ctx.CachedEnvironmentHistoryPath(envName)
+
+	privateKey, previousKeys, err := userKeys()
+	if err != nil {
+		return nil, kserrors.FailedToReadHistory(historyPath, err)
+	}
+
+	return history.New(historyPath, historyCipher(privateKey, previousKeys)), nil
+}
+
+// SecretHistory method returns the recorded values of `secretName`
+// in `envName`, oldest first
+func (ctx *Context) SecretHistory(
+	envName string,
+	secretName string,
+) []history.Entry {
+	if ctx.Err() != nil {
+		return []history.Entry{}
+	}
+
+	journal, e := ctx.journal(envName)
+	if e != nil {
+		ctx.setError(e)
+		return []history.Entry{}
+	}
+
+	if err := journal.Load().Err(); err != nil {
+		ctx.setError(
+			kserrors.FailedToReadHistory(
+				ctx.CachedEnvironmentHistoryPath(envName),
+				err,
+			),
+		)
+		return []history.Entry{}
+	}
+
+	return journal.For(secretName)
+}
+
+// recordSecretChanges method appends the secret changes applied
+// to `envName` to its history, on behalf of `sender`.
+// The first time a secret that already had a value changes, that value
+// is recorded first, so that it can be rolled back to.
+// Other kinds of changes are ignored.
+func (ctx *Context) recordSecretChanges(
+	envName string,
+	sender string,
+	changes []Change,
+) *Context {
+	if ctx.Err() != nil {
+		return ctx
+	}
+
+	journal, e := ctx.journal(envName)
+	if e != nil {
+		return ctx.setError(e)
+	}
+
+	if err := journal.Load().Err(); err != nil {
+		return ctx.setError(
+			kserrors.FailedToReadHistory(
+				ctx.CachedEnvironmentHistoryPath(envName),
+				err,
+			),
+		)
+	}
+
+	now := time.Now().UTC()
+	entries := make([]history.Entry, 0, len(changes))
+
+	for _, change := range changes {
+		if (change.Type == ChangeTypeSecretChange ||
+			change.Type == ChangeTypeSecretDelete) &&
+			len(journal.For(change.Name)) == 0 {
+			entries = append(entries, history.Entry{
+				Secret: change.Name,
+				Value:  change.From,
+				Type:   history.EntryInitial,
+				Date:   now,
+			})
+		}
+
+		entry := history.Entry{
+			Secret: change.Name,
+			Value:  change.To,
+			Sender: sender,
+			Date:   now,
+		}
+
+		switch change.Type {
+		case ChangeTypeSecretAdd:
+			entry.Type = history.EntryAdd
+		case ChangeTypeSecretChange:
+			entry.Type = history.EntryChange
+		case ChangeTypeSecretDelete:
+			entry.Type = history.EntryDelete
+		default:
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if len(entries) == 0 {
+		return ctx
+	}
+
+	if err := journal.Append(entries...).Err(); err != nil {
+		return ctx.setError(
+			kserrors.FailedToWriteHistory(
+				ctx.CachedEnvironmentHistoryPath(envName),
+				err,
+			),
+		)
+	}
+
+	ctx.log.Printf("Recorded %d change(s) in the history of %s\n", len(entries), envName)
+
+	return ctx
+}
+
+// recordLocalSecretChange method appends a change made by the current
+// user to the history of `envName`
+func (ctx *Context) recordLocalSecretChange(
+	envName string,
+	change Change,
+) *Context {
+	user, _ := config.GetCurrentAccount()
+
+	return ctx.recordSecretChanges(envName, user.UserID, []Change{change})
+}
@@ -1,13 +1,54 @@
 package core
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
 	"os/exec"
+	"path"
 	"strings"
+	"text/template"
 
+	kserrors "github.com/wearedevx/keystone/cli/internal/errors"
+	"github.com/wearedevx/keystone/cli/internal/githelper"
 	"github.com/wearedevx/keystone/cli/ui"
 )
 
+// GitPreCommitHook is the name of the git hook that scans staged files
+const GitPreCommitHook = "pre-commit"
+
+// gitHookMarker tells the hooks installed by Keystone apart
+const gitHookMarker = "# Installed by Keystone"
+
+// localGitHookSuffix is added to the name of a hook that was there before
+// Keystone installed its own, which runs it
+const localGitHookSuffix = ".local"
+
+// gitPreCommitHookScript stops commits that contain secret values.
+// It runs the hook that was there before first, if any.
+// Git runs hooks at the root of the working tree, the script moves to the
+// project, which can be in a subdirectory, after that.
+const gitPreCommitHookScript = `#!/bin/sh
+` + gitHookMarker + `: stops commits that contain secret values.
+# To see what is found: ks scan --staged
+# The hook that was there before is kept in ` + GitPreCommitHook + localGitHookSuffix + `, and runs first.
+local_hook="$(dirname "$0")/` + GitPreCommitHook + localGitHookSuffix + `"
+if [ -x "$local_hook" ]; then
+	"$local_hook" "$@" || exit $?
+fi
+{{ if . }}cd {{ quote (print "./" .) }} || exit 1
+{{ end }}exec ks scan --staged
+`
+
+// quoteShellArgument returns `s` between single quotes, for a POSIX shell.
+// Nothing is special between single quotes, a single quote is written
+// by closing the quotes, escaping it, and opening them again.
+func quoteShellArgument(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
 type Hook struct {
 	ctx     *Context
 	Command string
@@ -42,3 +83,101 @@ func (h *Hook) Run() (err error) {
 
 	return err
 }
+
+// GitPreCommitHookPath method returns the path to the pre-commit hook of
+// the git repository the project is in
+func (ctx *Context) GitPreCommitHookPath() string {
+	if ctx.Err() != nil {
+		return ""
+	}
+
+	hooksPath, err := githelper.HooksPath(ctx.Wd)
+	if err != nil {
+		ctx.setError(kserrors.CannotInstallGitHook(GitPreCommitHook, err))
+		return ""
+	}
+
+	return path.Join(hooksPath, GitPreCommitHook)
+}
+
+// IsKeystoneGitHook function returns true if the hook at `hookPath` was
+// installed by Keystone
+func IsKeystoneGitHook(hookPath string) bool {
+	/* #nosec */
+	content, err := ioutil.ReadFile(hookPath)
+
+	return err == nil && bytes.Contains(content, []byte(gitHookMarker))
+}
+
+// KeepLocalGitHook method moves the hook at `hookPath`, which was not
+// installed by Keystone, next to it so that the Keystone hook runs it.
+// It returns its new path.
+// It is an error if a hook was already kept there.
+func (ctx *Context) KeepLocalGitHook(hookPath string) string {
+	if ctx.Err() != nil {
+		return ""
+	}
+
+	localHookPath := hookPath + localGitHookSuffix
+
+	if _, err := os.Lstat(localHookPath); err == nil {
+		ctx.setError(kserrors.CannotInstallGitHook(
+			GitPreCommitHook,
+			fmt.Errorf(
+				"%s would replace %s, which exists already",
+				hookPath,
+				localHookPath,
+			),
+		))
+		return ""
+	}
+
+	if err := os.Rename(hookPath, localHookPath); err != nil {
+		ctx.setError(kserrors.CannotInstallGitHook(GitPreCommitHook, err))
+		return ""
+	}
+
+	return localHookPath
+}
+
+// InstallGitPreCommitHook method writes the hook that runs `ks scan --staged`
+// before each commit to `hookPath`, replacing any existing one
+func (ctx *Context) InstallGitPreCommitHook(hookPath string) *Context {
+	if ctx.Err() != nil {
+		return ctx
+	}
+
+	if err := os.MkdirAll(path.Dir(hookPath), 0o755); err != nil {
+		return ctx.setError(kserrors.CannotInstallGitHook(GitPreCommitHook, err))
+	}
+
+	prefix, err := githelper.Prefix(ctx.Wd)
+	if err != nil {
+		return ctx.setError(kserrors.CannotInstallGitHook(GitPreCommitHook, err))
+	}
+
+	var script bytes.Buffer
+	err = template.Must(
+		template.New("hook").
+			Funcs(template.FuncMap{"quote": quoteShellArgument}).
+			Parse(gitPreCommitHookScript),
+	).Execute(&script, prefix)
+	if err != nil {
+		return ctx.setError(kserrors.CannotInstallGitHook(GitPreCommitHook, err))
+	}
+
+	/* #nosec
+	 * Git hooks must be executable
+	 */
+	err = ioutil.WriteFile(hookPath, script.Bytes(), 0o755)
+	if err == nil {
+		// WriteFile keeps the mode of existing files
+		err = os.Chmod(hookPath, 0o755)
+	}
+
+	if err != nil {
+		return ctx.setError(kserrors.CannotInstallGitHook(GitPreCommitHook, err))
+	}
+
+	return ctx
+}
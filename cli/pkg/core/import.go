@@ -0,0 +1,168 @@
+package core
+
+import (
+	"sort"
+
+	kserrors "github.com/wearedevx/keystone/cli/internal/errors"
+	"github.com/wearedevx/keystone/cli/internal/keystonefile"
+)
+
+// ImportStrategy tells what to do with a secret whose imported value
+// differs from the one in cache
+type ImportStrategy string
+
+const (
+	// Keep the value in cache, the secret is still added to the project
+	ImportKeep ImportStrategy = "keep"
+	// Use the imported value
+	ImportOverwrite ImportStrategy = "overwrite"
+	// Leave the secret out of the import
+	ImportSkip ImportStrategy = "skip"
+)
+
+// ImportStrategies lists all the valid import strategies
+var ImportStrategies = []ImportStrategy{ImportKeep, ImportOverwrite, ImportSkip}
+
+// IsValid method tells if the strategy is one of `ImportStrategies`
+func (s ImportStrategy) IsValid() bool {
+	for _, strategy := range ImportStrategies {
+		if s == strategy {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SecretImport describes a secret about to be imported
+type SecretImport struct {
+	Name     string
+	Current  string
+	Imported string
+	// Known is true if the secret is in the keystone.yaml file or in cache
+	Known    bool
+	Strategy ImportStrategy
+}
+
+// IsNew method tells if the secret does not exist yet
+func (si SecretImport) IsNew() bool {
+	return !si.Known
+}
+
+// IsConflict method tells if the secret exists with a different value
+func (si SecretImport) IsConflict() bool {
+	return si.Known && si.Current != si.Imported
+}
+
+// PrepareSecretsImport method compares `values` with the secrets in cache
+// for the environment `environmentName`.
+// New secrets and secrets with the same value are to be imported as is,
+// conflicting ones get the `ImportKeep` strategy until told otherwise.
+// The result is sorted by secret name.
+func (ctx *Context) PrepareSecretsImport(
+	environmentName string,
+	values map[string]string,
+) []SecretImport {
+	imports := make([]SecretImport, 0, len(values))
+
+	if ctx.Err() != nil {
+		return imports
+	}
+
+	cachedSecrets := ctx.ListSecretsFromCache()
+
+	for name, value := range values {
+		secretImport := SecretImport{
+			Name:     name,
+			Imported: value,
+			Known:    ctx.HasSecret(name),
+			Strategy: ImportOverwrite,
+		}
+
+		for _, cached := range cachedSecrets {
+			if cached.Name == name {
+				secretImport.Known = true
+				secretImport.Current = string(
+					cached.Values[EnvironmentName(environmentName)],
+				)
+				break
+			}
+		}
+
+		if secretImport.IsConflict() {
+			secretImport.Strategy = ImportKeep
+		}
+
+		imports = append(imports, secretImport)
+	}
+
+	sort.Slice(imports, func(i, j int) bool {
+		return imports[i].Name < imports[j].Name
+	})
+
+	return imports
+}
+
+// ImportSecrets method writes the imported secrets to the keystone.yaml file
+// and to the cache of the environment `environmentName`, following
+// each secret’s strategy.
+// Secrets that are new to the project are marked as required depending
+// on `flag`: they would then need a value in every environment.
+func (ctx *Context) ImportSecrets(
+	environmentName string,
+	imports []SecretImport,
+	flag SecretStrictFlag,
+) *Context {
+	if ctx.Err() != nil {
+		return ctx
+	}
+
+	// Refuse the whole import if any value does not satisfy the rules
+	// of keystone.yaml, so that nothing is half-imported
+	for _, secretImport := range imports {
+		if secretImport.Strategy != ImportOverwrite {
+			continue
+		}
+
+		if ctx.ValidateSecret(
+			environmentName,
+			secretImport.Name,
+			secretImport.Imported,
+		).Err() != nil {
+			return ctx
+		}
+	}
+
+	ksfile := new(keystonefile.KeystoneFile).Load(ctx.Wd)
+
+	for _, secretImport := range imports {
+		if secretImport.Strategy == ImportSkip {
+			ctx.log.Printf("Skipping %s\n", secretImport.Name)
+			continue
+		}
+
+		if hasIt, _ := ksfile.HasEnv(secretImport.Name); !hasIt {
+			ksfile.SetEnv(secretImport.Name, flag == S_REQUIRED)
+		}
+
+		if secretImport.Strategy == ImportOverwrite {
+			if err := ctx.
+				SetSecret(
+					environmentName,
+					secretImport.Name,
+					secretImport.Imported,
+				).
+				Err(); err != nil {
+				return ctx
+			}
+		}
+
+		ctx.log.Printf("Imported %s\n", secretImport.Name)
+	}
+
+	if err := ksfile.Save().Err(); err != nil {
+		return ctx.setError(kserrors.FailedToUpdateKeystoneFile(err))
+	}
+
+	return ctx
+}
@@ -0,0 +1,150 @@
+package core
+
+import (
+	"errors"
+	"os"
+	"path"
+
+	kserrors "github.com/wearedevx/keystone/cli/internal/errors"
+	"github.com/wearedevx/keystone/cli/internal/keystonefile"
+	"github.com/wearedevx/keystone/cli/pkg/constants"
+)
+
+// EnvironmentParents method returns the environments `envName` inherits
+// values from, nearest first, as declared in keystone.yaml:
+//
+//	environments:
+//	  staging:
+//	    inherits: dev
+//	  prod:
+//	    inherits: staging
+func (ctx *Context) EnvironmentParents(envName string) []string {
+	if ctx.Err() != nil {
+		return []string{}
+	}
+
+	ksfile := keystonefile.LoadKeystoneFile(ctx.Wd)
+	if err := ksfile.Err(); err != nil {
+		ctx.setError(kserrors.FailedToReadKeystoneFile(ksfile.Path, err))
+		return []string{}
+	}
+
+	parents, err := ksfile.Parents(envName, ctx.knownEnvironments())
+
+	var unknownParentError *keystonefile.UnknownParentEnvironmentError
+	switch {
+	case errors.As(err, &unknownParentError):
+		ctx.setError(kserrors.UnknownParentEnvironment(envName, err))
+		return []string{}
+
+	case err != nil:
+		ctx.setError(kserrors.EnvironmentInheritanceCycle(envName, err))
+		return []string{}
+	}
+
+	return parents
+}
+
+// knownEnvironments returns the names of the default environments,
+// and of the ones in the environments file
+func (ctx *Context) knownEnvironments() []string {
+	known := make([]string, 0)
+
+	for _, name := range constants.EnvList {
+		known = append(known, string(name))
+	}
+
+	for _, environment := range ctx.LoadEnvironmentsFile().Environments {
+		if !Contains(known, environment.Name) {
+			known = append(known, environment.Name)
+		}
+	}
+
+	return known
+}
+
+// inheritValues returns the values of every environment in
+// `valuesByEnvironment` (environment name → secret name → value),
+// where values that are missing or empty are taken from the nearest
+// parent environment that has one.
+// The second value tells, for every environment, which environment each
+// inherited value comes from.
+// `valuesByEnvironment` is left untouched.
+func (ctx *Context) inheritValues(
+	valuesByEnvironment map[string]map[string]string,
+) (
+	resolved map[string]map[string]string,
+	inheritedFrom map[string]map[string]string,
+) {
+	resolved = make(map[string]map[string]string)
+	inheritedFrom = make(map[string]map[string]string)
+
+	for environment, values := range valuesByEnvironment {
+		environmentValues := make(map[string]string)
+		for name, value := range values {
+			environmentValues[name] = value
+		}
+
+		from := make(map[string]string)
+
+		for _, parent := range ctx.EnvironmentParents(environment) {
+			for name, value := range valuesByEnvironment[parent] {
+				current, ok := environmentValues[name]
+
+				if !ok || (current == "" && value != "") {
+					environmentValues[name] = value
+
+					if value != "" {
+						from[name] = parent
+					}
+				}
+			}
+		}
+
+		resolved[environment] = environmentValues
+		inheritedFrom[environment] = from
+	}
+
+	return resolved, inheritedFrom
+}
+
+// CachedFilePathForEnvironment method returns the path to the content
+// of `filePath` in cache for `envName`.
+// When the file is missing or empty in that environment, the content of
+// the nearest parent environment that has some is used instead.
+// The second value is the environment the content comes from.
+func (ctx *Context) CachedFilePathForEnvironment(
+	envName string,
+	filePath string,
+) (cachedPath string, fromEnvironment string) {
+	cachedPath = path.Join(ctx.CachedEnvironmentFilesPath(envName), filePath)
+	fromEnvironment = envName
+
+	if ctx.Err() != nil || fileHasContent(cachedPath) {
+		return cachedPath, fromEnvironment
+	}
+
+	for _, parent := range ctx.EnvironmentParents(envName) {
+		if !ctx.HasEnvironment(parent) {
+			continue
+		}
+
+		parentPath := path.Join(
+			ctx.CachedEnvironmentFilesPath(parent),
+			filePath,
+		)
+
+		if fileHasContent(parentPath) {
+			return parentPath, parent
+		}
+	}
+
+	return cachedPath, fromEnvironment
+}
+
+// fileHasContent returns true if the file at `p` exists and is not empty
+func fileHasContent(p string) bool {
+	info, err := os.Stat(p)
+
+	return err == nil && !info.IsDir() && info.Size() > 0
+}
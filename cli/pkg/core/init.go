@@ -20,10 +20,9 @@ import (
 // - .keystone/environment
 // - .keystone/cache/
 // - .keystone/cache/.env
-// - .keystone/cache/dev/
-// - .keystone/cache/ci/
-// - .keystone/cache/staging/
-// - .keystone/cache/prod/
+// - .keystone/cache/<environment>/, for each environment of the project
+//
+// If the project has no environments, dev, staging and prod are created.
 //
 // It adds .keystone to .gitignore, creating
 // it if does not exist
@@ -63,55 +62,29 @@ func (ctx *Context) Init(project models.Project) *Context {
 		func() error {
 			return utils.CreateFileIfNotExists(ctx.CachedDotEnvPath(), "")
 		},
-		func() error {
-			return utils.CreateDirIfNotExist(ctx.CachedEnvironmentPath(string(constants.DEV)))
-		},
-		func() error {
-			return utils.CreateDirIfNotExist(
-				ctx.CachedEnvironmentPath(string(constants.STAGING)),
-			)
-		},
-		func() error {
-			return utils.CreateDirIfNotExist(ctx.CachedEnvironmentPath(string(constants.PROD)))
-		},
-		func() error {
-			return utils.CreateFileIfNotExists(
-				ctx.CachedEnvironmentDotEnvPath(string(constants.DEV)),
-				"",
-			)
-		},
-		func() error {
-			return utils.CreateFileIfNotExists(
-				ctx.CachedEnvironmentDotEnvPath(string(constants.STAGING)),
-				"",
-			)
-		},
-		func() error {
-			return utils.CreateFileIfNotExists(
-				ctx.CachedEnvironmentDotEnvPath(string(constants.PROD)),
-				"",
-			)
-		},
-		func() error {
-			return utils.CreateDirIfNotExist(
-				ctx.CachedEnvironmentFilesPath(string(constants.DEV)),
-			)
-		},
-		func() error {
-			return utils.CreateDirIfNotExist(
-				ctx.CachedEnvironmentFilesPath(string(constants.STAGING)),
-			)
-		},
-		func() error {
-			return utils.CreateDirIfNotExist(
-				ctx.CachedEnvironmentFilesPath(string(constants.PROD)),
-			)
-		},
 		func() error {
 			return gitignorehelper.GitIgnore(ctx.Wd, dotKeystone)
 		},
 	}
 
+	environmentNames := make([]string, 0, len(project.Environments))
+	for _, environment := range project.Environments {
+		environmentNames = append(environmentNames, environment.Name)
+	}
+
+	if len(environmentNames) == 0 {
+		for _, name := range constants.EnvList {
+			environmentNames = append(environmentNames, string(name))
+		}
+	}
+
+	for _, name := range environmentNames {
+		name := name
+		ops = append(ops, func() error {
+			return ctx.initEnvironmentCache(name)
+		})
+	}
+
 	for _, op := range ops {
 		if err = op(); err != nil {
 			return ctx.setError(kserrors.InitFailed(err))
@@ -6,7 +6,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io/ioutil"
 	"os"
 	"path"
 	"strings"
@@ -14,6 +13,7 @@ import (
 	"github.com/udhos/equalfile"
 	"github.com/wearedevx/keystone/cli/internal/envfile"
 	kserrors "github.com/wearedevx/keystone/cli/internal/errors"
+	"github.com/wearedevx/keystone/cli/internal/keystonefile"
 	"github.com/wearedevx/keystone/cli/internal/utils"
 
 	"github.com/wearedevx/keystone/api/pkg/models"
@@ -26,6 +26,8 @@ const (
 	ChangeTypeSecretChange ChangeType = "change"
 	ChangeTypeSecretDelete ChangeType = "delete"
 	ChangeTypeFile         ChangeType = "file"
+	// A file of a directory entry has been removed
+	ChangeTypeFileDelete ChangeType = "file_delete"
 	// This one happens when environment version changed
 	// but there is no messages along with it
 	ChangeTypeVersion ChangeType = "version"
@@ -56,7 +58,12 @@ func (c Change) IsSecretDelete() bool {
 
 // IsFile method tells if the change is about a file
 func (c Change) IsFile() bool {
-	return c.Type == ChangeTypeFile
+	return c.Type == ChangeTypeFile || c.Type == ChangeTypeFileDelete
+}
+
+// IsFileDelete method tells if the change is removing a file
+func (c Change) IsFileDelete() bool {
+	return c.Type == ChangeTypeFileDelete
 }
 
 type Changes []Change
@@ -80,6 +87,17 @@ func (changes Changes) IsEmpty() bool {
 // ChangesByEnvironment struct is a list of changes grouped by environment
 type ChangesByEnvironment struct {
 	Environments map[string]Changes
+	// Values that were received, but not saved
+	Rejected []RejectedSecret
+}
+
+// RejectedSecret struct is a value sent by another member that does not
+// satisfy the rules of keystone.yaml.
+// The local value is kept instead.
+type RejectedSecret struct {
+	Environment string
+	Name        string
+	Err         *kserrors.Error
 }
 
 /// Returns a list of all environments that have a different
@@ -108,6 +126,9 @@ func (ctx *Context) SaveMessages(
 
 	ctx.log.Println("Saving Messages")
 
+	// Every payload is read and checked before anything is written
+	payloads := make(map[string]models.MessagePayload)
+
 	for environmentName, environment := range MessageByEnvironments.Environments {
 		// ——— Preparation work ———
 		PayloadContent := models.MessagePayload{}
@@ -141,6 +162,28 @@ func (ctx *Context) SaveMessages(
 			return changes
 		}
 
+		// Values sent by other members must satisfy the rules
+		// of keystone.yaml too
+		changes.Rejected = append(
+			changes.Rejected,
+			ctx.rejectInvalidSecrets(
+				&PayloadContent,
+				environmentName,
+				cachedLocalSecrets,
+			)...,
+		)
+		if ctx.Err() != nil {
+			return changes
+		}
+
+		payloads[environmentName] = PayloadContent
+	}
+
+	for environmentName, PayloadContent := range payloads {
+		environment := MessageByEnvironments.Environments[environmentName]
+
+		ctx.log.Printf("-- Saving environment %s\n", environmentName)
+
 		// ——— Handle files ———
 		environmentChanges := make([]Change, 0)
 		fileChanges := ctx.getFilesChanges(
@@ -154,6 +197,11 @@ func (ctx *Context) SaveMessages(
 			return changes
 		}
 
+		if err := ctx.saveFileModes(PayloadContent.Files, environmentName); err != nil {
+			ctx.err = kserrors.CannotSaveFiles(err.Error(), err)
+			return changes
+		}
+
 		// ——— Handle secrets ———
 
 		// We need the local values for every secret for `environment`
@@ -169,6 +217,15 @@ func (ctx *Context) SaveMessages(
 			return changes
 		}
 
+		// Keep the previous values around, in case the new ones are wrong
+		if err := ctx.recordSecretChanges(
+			environmentName,
+			environment.Message.Sender.UserID,
+			secretChanges,
+		).Err(); err != nil {
+			return changes
+		}
+
 		environmentChanges = append(environmentChanges, fileChanges...)
 		environmentChanges = append(environmentChanges, secretChanges...)
 
@@ -182,6 +239,60 @@ func (ctx *Context) SaveMessages(
 	return changes
 }
 
+// rejectInvalidSecrets removes from `payload` the values that do not
+// satisfy the rules of keystone.yaml, and returns them.
+// The local value of a rejected secret is put back in the payload, so
+// that it is kept. A rejected secret that is new is left out.
+func (ctx *Context) rejectInvalidSecrets(
+	payload *models.MessagePayload,
+	environmentName string,
+	cachedLocalSecrets []Secret,
+) []RejectedSecret {
+	rejected := make([]RejectedSecret, 0)
+
+	ksfile := keystonefile.LoadKeystoneFile(ctx.Wd)
+	if err := ksfile.Err(); err != nil {
+		ctx.setError(kserrors.FailedToReadKeystoneFile(ksfile.Path, err))
+		return rejected
+	}
+
+	secrets := make([]models.SecretVal, 0, len(payload.Secrets))
+
+	for _, secret := range payload.Secrets {
+		envKey, hasIt := ksfile.GetEnv(secret.Label)
+
+		// Values referencing other secrets are checked once expanded
+		if !hasIt || envfile.HasReferences(secret.Value) {
+			secrets = append(secrets, secret)
+			continue
+		}
+
+		e := validateSecretValue(envKey, environmentName, secret.Value)
+		if e == nil {
+			secrets = append(secrets, secret)
+			continue
+		}
+
+		rejected = append(rejected, RejectedSecret{
+			Environment: environmentName,
+			Name:        secret.Label,
+			Err:         e,
+		})
+
+		for _, local := range cachedLocalSecrets {
+			if local.Name == secret.Label {
+				secret.Value = string(local.Values[EnvironmentName(environmentName)])
+				secrets = append(secrets, secret)
+				break
+			}
+		}
+	}
+
+	payload.Secrets = secrets
+
+	return rejected
+}
+
 func secretsForEnvironment(
 	cachedLocalSecrets []Secret,
 	environmentName string,
@@ -232,6 +343,18 @@ func (ctx *Context) handleFileChanges(
 		filesDir := ctx.CachedEnvironmentFilesPath(environmentName)
 		filePath := path.Join(filesDir, fileChange.Name)
 
+		// The local copy of a removed file goes too,
+		// unless it has been modified
+		if fileChange.IsFileDelete() &&
+			environmentName == ctx.CurrentEnvironment() &&
+			!ctx.IsFileModified(fileChange.Name, environmentName) {
+			localPath := path.Join(ctx.Wd, fileChange.Name)
+
+			if err := utils.RemoveFile(localPath); err != nil {
+				ctx.err = kserrors.CannotRemoveDirectoryContents(localPath, err)
+			}
+		}
+
 		err := utils.RemoveFile(filePath)
 		if err != nil {
 			ctx.err = kserrors.CannotRemoveDirectoryContents(filePath, err)
@@ -257,8 +380,7 @@ func (ctx *Context) handleSecretChanges(
 
 	if len(PayloadContent.Secrets) > 0 {
 		envFilePath := ctx.CachedEnvironmentDotEnvPath(environmentName)
-		envFile := new(envfile.EnvFile)
-		envFile.Load(envFilePath, nil)
+		envFile := ctx.loadCachedDotEnv(envFilePath)
 
 		for key := range envFile.GetData() {
 			found := false
@@ -346,7 +468,7 @@ func GetSecretsChanges(
 
 /// fileHasChanges returns true if the content of file at `pathToExistingFile` is different
 // from `candidateContent`, meaning the file contents have changed.
-func fileHasChanges(
+func (ctx *Context) fileHasChanges(
 	pathToExistingFile string,
 	candidateContent []byte,
 ) (sameContent bool, err error) {
@@ -355,7 +477,7 @@ func fileHasChanges(
 	 * pathToExistingFile must be checked befor call
 	 * to ensure that it belongs de ctx.Wd
 	 */
-	currentFileReader, err := os.Open(pathToExistingFile)
+	currentContent, err := ctx.ReadCachedFile(pathToExistingFile)
 	if err != nil {
 		if os.IsNotExist(err) {
 			// Not really an error, just create the file
@@ -368,7 +490,7 @@ func fileHasChanges(
 	comparator := equalfile.New(nil, equalfile.Options{})
 
 	sameContent, err = comparator.CompareReader(
-		currentFileReader,
+		bytes.NewReader(currentContent),
 		candidateReader,
 	)
 	if err == nil {
@@ -403,7 +525,7 @@ func (ctx *Context) getFilesChanges(
 			return []Change{}
 		}
 
-		fileHasChanges, err := fileHasChanges(filePath, fileContent)
+		fileHasChanges, err := ctx.fileHasChanges(filePath, fileContent)
 		if err != nil {
 			kserrors.FailedCheckingChanges(filePath, err).Print()
 			continue
@@ -417,9 +539,54 @@ func (ctx *Context) getFilesChanges(
 			})
 		}
 	}
+
+	return append(changes, ctx.getDeletedFiles(files, environmentName)...)
+}
+
+// getDeletedFiles method lists the files of directory entries that are
+// in cache for the environment, but not in the message anymore:
+// they have been removed from the directory by the sender
+func (ctx *Context) getDeletedFiles(
+	files []models.File,
+	environmentName string,
+) (changes []Change) {
+	changes = make([]Change, 0)
+	cachePath := ctx.CachedEnvironmentFilesPath(environmentName)
+
+	for _, directory := range ctx.ListFiles() {
+		if !directory.Directory {
+			continue
+		}
+
+		for _, cached := range directory.Expand(
+			filesUnder(cachePath, directory.Path),
+		) {
+			if messageHasFile(files, cached.Path) {
+				continue
+			}
+
+			changes = append(changes, Change{
+				Type: ChangeTypeFileDelete,
+				Name: cached.Path,
+			})
+		}
+	}
+
 	return changes
 }
 
+// messageHasFile returns true if one of the `files` of a message has
+// the path `filePath`
+func messageHasFile(files []models.File, filePath string) bool {
+	for _, file := range files {
+		if path.Clean(file.Path) == filePath {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (ctx *Context) saveFilesChanges(
 	changes []Change,
 	environmentName string,
@@ -428,15 +595,13 @@ func (ctx *Context) saveFilesChanges(
 	cacheDir := ctx.CachedEnvironmentFilesPath(environmentName)
 
 	for _, change := range changes {
-		cachedFilePath := path.Join(cacheDir, change.Name)
-
-		err = utils.CreateFileIfNotExists(cachedFilePath, change.To)
-		if err != nil {
-			errorList = append(errorList, err.Error())
+		if change.IsFileDelete() {
 			continue
 		}
 
-		if err = ioutil.WriteFile(cachedFilePath, []byte(change.To), 0o600); err != nil {
+		cachedFilePath := path.Join(cacheDir, change.Name)
+
+		if err = ctx.writeCachedFile(cachedFilePath, []byte(change.To)); err != nil {
 			errorList = append(errorList, err.Error())
 			continue
 		}
@@ -453,6 +618,36 @@ func (ctx *Context) saveFilesChanges(
 	return nil
 }
 
+// saveFileModes method records the modes of the files of a message.
+// Files sent without one, by older versions, keep theirs.
+func (ctx *Context) saveFileModes(
+	files []models.File,
+	environmentName string,
+) error {
+	cachedModes := ctx.CachedFileModes(environmentName)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	modes := make(map[string]keystonefile.FileMode)
+
+	for _, file := range files {
+		filePath := path.Clean(file.Path)
+
+		if file.Mode != 0 {
+			modes[filePath] = keystonefile.FileMode(file.Mode)
+		} else if mode, ok := cachedModes[filePath]; ok {
+			modes[filePath] = mode
+		}
+	}
+
+	if len(modes) == 0 && len(cachedModes) == 0 {
+		return nil
+	}
+
+	return ctx.setCachedFileModes(environmentName, modes)
+}
+
 // Return PayloadContent, with secrets and files of current environment.
 func (ctx *Context) PrepareMessagePayload(
 	environment models.Environment,
@@ -466,7 +661,12 @@ func (ctx *Context) PrepareMessagePayload(
 
 	errors := make([]string, 0)
 
-	for _, secret := range ctx.ListSecretsFromCache() {
+	cachedSecrets := ctx.ListSecretsFromCache()
+	if ctx.Err() != nil {
+		return PayloadContent, ctx.Err()
+	}
+
+	for _, secret := range cachedSecrets {
 		PayloadContent.Secrets = append(
 			PayloadContent.Secrets,
 			models.SecretVal{
@@ -477,11 +677,11 @@ func (ctx *Context) PrepareMessagePayload(
 	}
 
 	envCachePath := ctx.CachedEnvironmentFilesPath(environment.Name)
+	modes := ctx.CachedFileModes(environment.Name)
 
 	for _, file := range ctx.ListCachedFilesForEnvironment(environment.Name) {
 		filePath := path.Join(envCachePath, file.Path)
-		/* #nosec */
-		fileContent, err := ioutil.ReadFile(filePath)
+		fileContent, err := ctx.ReadCachedFile(filePath)
 		if err != nil {
 			errors = append(errors, err.Error())
 		}
@@ -489,6 +689,7 @@ func (ctx *Context) PrepareMessagePayload(
 		PayloadContent.Files = append(PayloadContent.Files, models.File{
 			Path:  file.Path,
 			Value: base64.StdEncoding.EncodeToString(fileContent),
+			Mode:  uint32(modes[file.Path]),
 		})
 	}
 
@@ -0,0 +1,150 @@
+package core
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+
+	kserrors "github.com/wearedevx/keystone/cli/internal/errors"
+	"github.com/wearedevx/keystone/cli/internal/gitignorehelper"
+	"github.com/wearedevx/keystone/cli/internal/keystonefile"
+	"github.com/wearedevx/keystone/cli/internal/renderer"
+)
+
+// RenderTemplate method returns the template at `templatePath`, rendered
+// with the secrets and files of `environmentName`
+func (ctx *Context) RenderTemplate(
+	templatePath string,
+	environmentName string,
+) string {
+	if ctx.Err() != nil {
+		return ""
+	}
+
+	/* #nosec
+	 * The template is only parsed, and has no access to the system
+	 */
+	text, err := ioutil.ReadFile(templatePath)
+	if err != nil {
+		ctx.setError(kserrors.CannotRenderTemplate(templatePath, err))
+		return ""
+	}
+
+	data := ctx.renderData(environmentName)
+	if ctx.Err() != nil {
+		return ""
+	}
+
+	out, err := renderer.Render(templatePath, string(text), data)
+	if err != nil {
+		var missingValue *renderer.MissingValueError
+		if errors.As(err, &missingValue) {
+			ctx.setError(kserrors.MissingTemplateValue(
+				templatePath,
+				missingValue.Name,
+				err,
+			))
+		} else {
+			ctx.setError(kserrors.CannotRenderTemplate(templatePath, err))
+		}
+
+		return ""
+	}
+
+	return out
+}
+
+// RenderTemplateTo method renders the template at `templatePath` with the
+// values of `environmentName`, and writes it to `outputPath`.
+// The output holds secrets, only the user can read it.
+func (ctx *Context) RenderTemplateTo(
+	templatePath string,
+	outputPath string,
+	environmentName string,
+) *Context {
+	out := ctx.RenderTemplate(templatePath, environmentName)
+	if ctx.Err() != nil {
+		return ctx
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0o700); err != nil {
+		return ctx.setError(kserrors.CannotRenderTemplate(templatePath, err))
+	}
+
+	if err := ioutil.WriteFile(outputPath, []byte(out), 0o600); err != nil {
+		return ctx.setError(kserrors.CannotRenderTemplate(templatePath, err))
+	}
+
+	return ctx
+}
+
+// ListRenders method returns the templates listed in keystone.yaml.
+// Templates and outputs outside of the project are refused.
+func (ctx *Context) ListRenders() []keystonefile.RenderKey {
+	if ctx.Err() != nil {
+		return make([]keystonefile.RenderKey, 0)
+	}
+
+	ksfile := keystonefile.LoadKeystoneFile(ctx.Wd)
+	if err := ksfile.Err(); err != nil {
+		ctx.setError(kserrors.FailedToReadKeystoneFile(ksfile.Path, err))
+		return make([]keystonefile.RenderKey, 0)
+	}
+
+	for _, render := range ksfile.Renders {
+		if err := render.Validate(); err != nil {
+			ctx.setError(kserrors.CannotRenderTemplate(render.Template, err))
+			return make([]keystonefile.RenderKey, 0)
+		}
+	}
+
+	return ksfile.Renders
+}
+
+// RenderAll method renders every template listed in keystone.yaml with
+// the values of `environmentName`.
+// Outputs are added to .gitignore, they hold secrets.
+func (ctx *Context) RenderAll(environmentName string) *Context {
+	for _, render := range ctx.ListRenders() {
+		ctx.RenderTemplateTo(
+			path.Join(ctx.Wd, render.Template),
+			path.Join(ctx.Wd, render.Output),
+			environmentName,
+		)
+		if ctx.Err() != nil {
+			return ctx
+		}
+
+		gitignorehelper.GitIgnore(ctx.Wd, render.Output)
+	}
+
+	return ctx
+}
+
+// renderData returns the secrets and files of `environmentName`, as
+// templates see them: values are inherited and references are expanded
+func (ctx *Context) renderData(environmentName string) renderer.Data {
+	data := renderer.Data{
+		Environment: environmentName,
+		Secrets:     make(map[string]string),
+		Files:       make(map[string]string),
+	}
+
+	for _, secret := range ctx.ListExpandedSecrets(environmentName) {
+		data.Secrets[secret.Name] = string(
+			secret.Values[EnvironmentName(environmentName)],
+		)
+	}
+
+	for _, file := range ctx.ListExpandedFiles(environmentName) {
+		// Files without contents are missing for templates
+		contents, err := ctx.GetFileContents(file.Path, environmentName)
+		if err == nil {
+			data.Files[file.Path] = string(contents)
+		}
+	}
+
+	return data
+}
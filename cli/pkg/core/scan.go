@@ -0,0 +1,175 @@
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	kserrors "github.com/wearedevx/keystone/cli/internal/errors"
+	"github.com/wearedevx/keystone/cli/internal/githelper"
+	"github.com/wearedevx/keystone/cli/internal/keystonefile"
+	"github.com/wearedevx/keystone/cli/internal/scanner"
+)
+
+// ScanFiles method searches the files under `paths`, relative to the
+// project root, for the secret values and file contents of every
+// environment in cache whose entropy is at least `threshold`.
+// With `staged`, the content in the git index is searched instead of the
+// working tree.
+// Secret files themselves are not searched.
+func (ctx *Context) ScanFiles(
+	paths []string,
+	staged bool,
+	threshold float64,
+) []scanner.Leak {
+	leaks := make([]scanner.Leak, 0)
+	if ctx.Err() != nil {
+		return leaks
+	}
+
+	s := scanner.New(ctx.scanNeedles(), threshold)
+	if ctx.Err() != nil || s.Len() == 0 {
+		return leaks
+	}
+
+	filePaths, err := ctx.filesToScan(paths, staged)
+	if err != nil {
+		ctx.setError(kserrors.CannotScanFiles(err))
+		return leaks
+	}
+
+	secretFiles := ctx.ListFiles()
+
+	for _, filePath := range filePaths {
+		if isKeystonePath(filePath) || isSecretFile(secretFiles, filePath) {
+			continue
+		}
+
+		var content []byte
+		if staged {
+			content, err = githelper.StagedFileContents(ctx.Wd, filePath)
+		} else {
+			/* #nosec
+			 * The file is only read
+			 */
+			content, err = ioutil.ReadFile(path.Join(ctx.Wd, filePath))
+		}
+
+		if err != nil {
+			// Tracked files can be removed from the working tree
+			if os.IsNotExist(err) {
+				continue
+			}
+
+			ctx.setError(kserrors.CannotScanFiles(err))
+			return leaks
+		}
+
+		leaks = append(leaks, s.Scan(filePath, content)...)
+	}
+
+	return leaks
+}
+
+// scanNeedles returns the secret values, and the file contents, of every
+// environment in cache
+func (ctx *Context) scanNeedles() []scanner.Needle {
+	needles := make([]scanner.Needle, 0)
+
+	for _, values := range ctx.cachedEnvironmentValues() {
+		for name, value := range values {
+			needles = append(needles, scanner.Needle{Name: name, Value: value})
+		}
+	}
+
+	for _, environment := range ctx.ListEnvironments() {
+		for _, file := range ctx.ListExpandedFiles(environment) {
+			// Errors mean the file has no content for this environment
+			content, err := ctx.GetFileContents(file.Path, environment)
+			if err != nil {
+				continue
+			}
+
+			needles = append(needles, scanner.Needle{
+				Name:  file.Path,
+				Value: string(content),
+			})
+		}
+	}
+
+	return needles
+}
+
+// filesToScan returns the files under `paths` that can be committed:
+// the ones git does not ignore, or every file when the project is not in
+// a git repository.
+// With `staged`, only the files staged for the next commit.
+func (ctx *Context) filesToScan(paths []string, staged bool) ([]string, error) {
+	if staged {
+		return githelper.StagedFiles(ctx.Wd, paths)
+	}
+
+	if githelper.IsRepository(ctx.Wd) {
+		return githelper.ListFiles(ctx.Wd, paths)
+	}
+
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+
+	filePaths := make([]string, 0)
+	for _, root := range paths {
+		err := filepath.Walk(
+			filepath.Join(ctx.Wd, root),
+			func(p string, info os.FileInfo, err error) error {
+				if err != nil {
+					return err
+				}
+
+				if info.IsDir() {
+					if info.Name() == ".git" || info.Name() == dotKeystone {
+						return filepath.SkipDir
+					}
+					return nil
+				}
+
+				if !info.Mode().IsRegular() {
+					return nil
+				}
+
+				relativePath, err := filepath.Rel(ctx.Wd, p)
+				if err != nil {
+					return err
+				}
+
+				filePaths = append(filePaths, filepath.ToSlash(relativePath))
+				return nil
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return filePaths, nil
+}
+
+// isKeystonePath returns true if `filePath` is in the .keystone folder
+func isKeystonePath(filePath string) bool {
+	return filePath == dotKeystone ||
+		strings.HasPrefix(filePath, dotKeystone+"/")
+}
+
+// isSecretFile returns true if `filePath` is one of the `secretFiles`,
+// or is in one of the directories among them
+func isSecretFile(secretFiles []keystonefile.FileKey, filePath string) bool {
+	for _, file := range secretFiles {
+		if path.Clean(file.Path) == filePath || file.Contains(filePath) {
+			return true
+		}
+	}
+
+	return false
+}
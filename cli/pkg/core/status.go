@@ -0,0 +1,123 @@
+package core
+
+import (
+	"github.com/wearedevx/keystone/api/pkg/models"
+	kserrors "github.com/wearedevx/keystone/cli/internal/errors"
+	"github.com/wearedevx/keystone/cli/internal/keystonefile"
+)
+
+// EnvironmentStatus struct is the state of an environment,
+// locally and compared to the server
+type EnvironmentStatus struct {
+	Name           string   `json:"name"`
+	LocalVersion   string   `json:"local_version"`
+	ServerVersion  string   `json:"server_version,omitempty"`
+	Outdated       bool     `json:"outdated"`
+	PendingMessage bool     `json:"pending_message"`
+	MissingSecrets []string `json:"missing_secrets"`
+	MissingFiles   []string `json:"missing_files"`
+}
+
+// Status struct is an overview of the local state of a project
+type Status struct {
+	ProjectName        string              `json:"project_name"`
+	ProjectID          string              `json:"project_id"`
+	CurrentEnvironment string              `json:"current_environment"`
+	Online             bool                `json:"online"`
+	PendingMessages    int                 `json:"pending_messages"`
+	Environments       []EnvironmentStatus `json:"environments"`
+	ModifiedFiles      []string            `json:"modified_files"`
+	WorldReadableFiles []string            `json:"world_readable_files"`
+	CiServices         []string            `json:"ci_services"`
+}
+
+// NeedsAction method returns true when something must be done before
+// working on the project: fetching, sending or filling in values.
+func (s Status) NeedsAction() bool {
+	if s.PendingMessages > 0 || len(s.ModifiedFiles) > 0 {
+		return true
+	}
+
+	for _, environment := range s.Environments {
+		if environment.Outdated ||
+			len(environment.MissingSecrets) > 0 ||
+			len(environment.MissingFiles) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Status method computes the status of the project for the environments
+// the user can access.
+// `server` is the response of the server for the pending messages of the
+// project, which carries the current version of each environment.
+// When `online` is false, it is ignored.
+func (ctx *Context) Status(
+	currentEnvironment string,
+	server models.GetMessageByEnvironmentResponse,
+	online bool,
+) Status {
+	status := Status{
+		CurrentEnvironment: currentEnvironment,
+		Online:             online,
+		Environments:       make([]EnvironmentStatus, 0),
+		ModifiedFiles:      make([]string, 0),
+		WorldReadableFiles: make([]string, 0),
+		CiServices:         make([]string, 0),
+	}
+
+	if ctx.Err() != nil {
+		return status
+	}
+
+	ksfile := keystonefile.LoadKeystoneFile(ctx.Wd)
+	if err := ksfile.Err(); err != nil {
+		ctx.setError(kserrors.FailedToReadKeystoneFile(ksfile.Path, err))
+		return status
+	}
+
+	status.ProjectName = ksfile.ProjectName
+	status.ProjectID = ksfile.ProjectId
+
+	for _, service := range ksfile.CiServices {
+		status.CiServices = append(
+			status.CiServices,
+			service.Name+" ("+service.Type+")",
+		)
+	}
+
+	for _, environmentName := range ctx.ListEnvironments() {
+		environmentStatus := EnvironmentStatus{
+			Name:         environmentName,
+			LocalVersion: ctx.EnvironmentVersionByName(environmentName),
+		}
+
+		if response, ok := server.Environments[environmentName]; online && ok {
+			environmentStatus.ServerVersion = response.Environment.VersionID
+			environmentStatus.Outdated = response.Environment.VersionID != "" &&
+				response.Environment.VersionID != environmentStatus.LocalVersion
+			environmentStatus.PendingMessage = response.Message.ID > 0
+
+			if environmentStatus.PendingMessage {
+				status.PendingMessages++
+			}
+		}
+
+		environmentStatus.MissingSecrets, _ = ctx.
+			MissingSecretsForEnvironment(environmentName)
+		environmentStatus.MissingFiles, _ = ctx.
+			MissingFilesForEnvironment(environmentName)
+
+		status.Environments = append(status.Environments, environmentStatus)
+	}
+
+	for _, file := range ctx.LocallyModifiedFiles(currentEnvironment) {
+		status.ModifiedFiles = append(status.ModifiedFiles, file.Path)
+	}
+
+	status.WorldReadableFiles = ctx.WorldReadableFiles(currentEnvironment)
+
+	return status
+}
@@ -0,0 +1,116 @@
+package core
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/wearedevx/keystone/cli/internal/envfile"
+	kserrors "github.com/wearedevx/keystone/cli/internal/errors"
+	"github.com/wearedevx/keystone/cli/internal/keystonefile"
+)
+
+// ValidateSecret method checks `secretValue` against the rules declared
+// for `secretName` in keystone.yaml.
+// Secrets without rules, or unknown to keystone.yaml, are always valid.
+func (ctx *Context) ValidateSecret(
+	envName string,
+	secretName string,
+	secretValue string,
+) *Context {
+	if ctx.Err() != nil {
+		return ctx
+	}
+
+	ksfile := keystonefile.LoadKeystoneFile(ctx.Wd)
+	if err := ksfile.Err(); err != nil {
+		return ctx.setError(kserrors.FailedToReadKeystoneFile(ksfile.Path, err))
+	}
+
+	envKey, hasIt := ksfile.GetEnv(secretName)
+	if !hasIt {
+		return ctx
+	}
+
+	// Values referencing other secrets are checked once expanded,
+	// by `ValidateSecretsForEnvironment`
+	if envfile.HasReferences(secretValue) {
+		return ctx
+	}
+
+	if e := validateSecretValue(envKey, envName, secretValue); e != nil {
+		return ctx.setError(e)
+	}
+
+	return ctx
+}
+
+// ValidateSecrets method checks every value in `values` (secret name → value)
+// against the rules declared in keystone.yaml
+func (ctx *Context) ValidateSecrets(
+	envName string,
+	values map[string]string,
+) *Context {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+
+	// Always report the same error first
+	sort.Strings(names)
+
+	for _, name := range names {
+		if ctx.ValidateSecret(envName, name, values[name]).Err() != nil {
+			return ctx
+		}
+	}
+
+	return ctx
+}
+
+// ValidateSecretsForEnvironment method checks the expanded values of all the
+// secrets of `envName` against the rules declared in keystone.yaml
+func (ctx *Context) ValidateSecretsForEnvironment(envName string) *Context {
+	if ctx.Err() != nil {
+		return ctx
+	}
+
+	ksfile := keystonefile.LoadKeystoneFile(ctx.Wd)
+	if err := ksfile.Err(); err != nil {
+		return ctx.setError(kserrors.FailedToReadKeystoneFile(ksfile.Path, err))
+	}
+
+	secrets := ctx.ListExpandedSecrets(envName)
+	sort.Slice(secrets, func(i, j int) bool {
+		return secrets[i].Name < secrets[j].Name
+	})
+
+	for _, secret := range secrets {
+		envKey, _ := ksfile.GetEnv(secret.Name)
+		value := string(secret.Values[EnvironmentName(envName)])
+
+		if e := validateSecretValue(envKey, envName, value); e != nil {
+			return ctx.setError(e)
+		}
+	}
+
+	return ctx
+}
+
+// validateSecretValue turns validation errors into keystone errors
+func validateSecretValue(
+	envKey keystonefile.EnvKey,
+	envName string,
+	value string,
+) *kserrors.Error {
+	err := envKey.Validate(value)
+	if err == nil {
+		return nil
+	}
+
+	var ruleError *keystonefile.RuleError
+	if errors.As(err, &ruleError) {
+		return kserrors.InvalidValidationRule(envKey.Key, err)
+	}
+
+	return kserrors.InvalidSecretValue(envKey.Key, envName, err)
+}
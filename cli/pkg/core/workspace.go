@@ -0,0 +1,76 @@
+package core
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	kserrors "github.com/wearedevx/keystone/cli/internal/errors"
+	"github.com/wearedevx/keystone/cli/internal/workspacefile"
+)
+
+// resolveProjectDir returns the root directory of the project to work on.
+//
+// When `project` is empty, it is the first parent of `cwd` that has a
+// keystone.yaml. At the root of a workspace, the project must be chosen,
+// unless the workspace has a single one.
+//
+// Otherwise, `project` is a path relative to `cwd`, or to the root
+// of the workspace `cwd` belongs to.
+func resolveProjectDir(cwd, project string) (string, *kserrors.Error) {
+	if project == "" {
+		wd, err := resolveKeystoneRootDir(cwd)
+		if err == nil {
+			return wd, nil
+		}
+
+		if root, found := workspacefile.FindRoot(cwd); found {
+			workspace := new(workspacefile.WorkspaceFile).Load(root)
+			if e := workspace.Err(); e != nil {
+				return "", kserrors.FailedToReadWorkspaceFile(workspace.Path, e)
+			}
+
+			switch len(workspace.Projects) {
+			case 0:
+			case 1:
+				return workspace.Dir(workspace.Projects[0]), nil
+			default:
+				paths := make([]string, 0, len(workspace.Projects))
+				for _, p := range workspace.Projects {
+					paths = append(paths, p.Path)
+				}
+
+				return "", kserrors.MustChooseProject(
+					root,
+					strings.Join(paths, ", "),
+					nil,
+				)
+			}
+		}
+
+		return "", kserrors.NotAKeystoneProject(cwd, err)
+	}
+
+	candidates := make([]string, 0, 2)
+
+	if filepath.IsAbs(project) {
+		candidates = append(candidates, project)
+	} else {
+		candidates = append(candidates, filepath.Join(cwd, project))
+
+		if root, found := workspacefile.FindRoot(cwd); found {
+			candidates = append(candidates, filepath.Join(root, project))
+		}
+	}
+
+	for _, candidate := range candidates {
+		if isKeystoneRootDir(candidate) {
+			return candidate, nil
+		}
+	}
+
+	return "", kserrors.ProjectNotFound(
+		project,
+		fmt.Errorf("no keystone.yaml in %s", strings.Join(candidates, ", ")),
+	)
+}
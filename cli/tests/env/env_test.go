@@ -28,6 +28,7 @@ func TestCommands(t *testing.T) {
 	testscript.Run(t, testscript.Params{
 		Dir:                  "./",
 		Setup:                setupFunc,
+		Cmds:                 utils.Commands,
 		IgnoreMissedCoverage: true,
 	})
 }
@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"io/ioutil"
+	"path"
+	"regexp"
+
+	"github.com/rogpeppe/go-internal/testscript"
+	"github.com/wearedevx/keystone/cli/pkg/core"
+	"gopkg.in/yaml.v2"
+)
+
+// Commands are the custom commands available in test scripts
+var Commands = map[string]func(ts *testscript.TestScript, neg bool, args []string){
+	"cachegrep": CacheGrep,
+}
+
+// CacheGrep is like the grep command of testscript, for files
+// of the .keystone/cache directory, that are encrypted.
+// Usage: cachegrep [-F] pattern file
+func CacheGrep(ts *testscript.TestScript, neg bool, args []string) {
+	fixed := len(args) > 0 && args[0] == "-F"
+	if fixed {
+		args = args[1:]
+	}
+
+	if len(args) != 2 {
+		ts.Fatalf("usage: cachegrep [-F] pattern file")
+	}
+
+	pattern := args[0]
+	if fixed {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+
+	re, err := regexp.Compile(`(?m)` + pattern)
+	ts.Check(err)
+
+	content, err := readCachedFile(ts, ts.MkAbs(args[1]))
+	ts.Check(err)
+
+	if re.Match(content) != !neg {
+		if neg {
+			ts.Fatalf("unexpected match for %#q found in %s", pattern, args[1])
+		}
+		ts.Fatalf("no match for %#q found in %s", pattern, args[1])
+	}
+}
+
+// readCachedFile decrypts a file from the cache with the private key
+// of the user currently logged in
+func readCachedFile(ts *testscript.TestScript, filePath string) ([]byte, error) {
+	configPath := path.Join(ts.Getenv("HOME"), ".config", "keystone", "keystone.yaml")
+
+	/* #nosec */
+	configContent, err := ioutil.ReadFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var config struct {
+		PrivateKey string `yaml:"private_key"`
+	}
+
+	if err = yaml.Unmarshal(configContent, &config); err != nil {
+		return nil, err
+	}
+
+	return core.NewCacheCodec([]byte(config.PrivateKey)).ReadFile(filePath)
+}
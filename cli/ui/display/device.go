@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/wearedevx/keystone/api/pkg/models"
 	"github.com/wearedevx/keystone/cli/ui"
@@ -61,3 +62,35 @@ func formatDevice(device models.Device) string {
 		device.CreatedAt.Format("2006/01/02"),
 	)
 }
+
+// PrivateKeyProtected function Message when the private key of the device
+// has been sealed with a passphrase
+func PrivateKeyProtected(cacheDuration time.Duration) {
+	ui.PrintSuccess("Your private key is now protected by a passphrase.")
+
+	if cacheDuration > 0 {
+		ui.Print(ui.RenderTemplate("key protected", `Once unlocked, it is kept for {{ . }} in a keyring of your session.
+In scripts, set the passphrase in the KS_KEY_PASSPHRASE environment variable.`, cacheDuration.String()))
+	} else {
+		ui.Print("The passphrase will be asked for every time it is needed.")
+	}
+}
+
+// PrivateKeyAlreadyProtected function Message when the private key of the
+// device is protected already
+func PrivateKeyAlreadyProtected(cacheDuration time.Duration) {
+	ui.Print(ui.RenderTemplate("key already protected", `Your private key is already protected by a passphrase.
+Once unlocked, it is kept for {{ . }}.`, cacheDuration.String()))
+}
+
+// DeviceKeyRotated function Message when the keys of the device
+// have been replaced
+func DeviceKeyRotated(gracePeriod time.Duration) {
+	ui.PrintSuccess("The keys of this device have been replaced.")
+	ui.Print(ui.RenderTemplate("key rotated", `Members of your projects have been notified, and will encrypt secrets
+with the new key from now on.
+
+Messages sent with the old key can still be read for {{ . }}.
+Ask members to send you the environments again with:
+  $ ks env send`, gracePeriod.String()))
+}
@@ -1,9 +1,14 @@
 package display
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
 
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/wearedevx/keystone/api/pkg/models"
 	"github.com/wearedevx/keystone/cli/internal/keystonefile"
+	"github.com/wearedevx/keystone/cli/pkg/core"
 	"github.com/wearedevx/keystone/cli/ui"
 )
 
@@ -42,6 +47,31 @@ func EnvironmentList(environments []string, currentEnvironment string) {
 	}))
 }
 
+// EnvironmentAdded function Message after an environment is created
+func EnvironmentAdded(environment models.Environment) {
+	ui.Print(ui.RenderTemplate("env added", `
+{{ OK }} {{ .Message | bright_green }}
+Members get the same access to it as to the {{ .Type }} environment.
+
+To use it:
+  $ ks env switch {{ .Name }}
+`, map[string]string{
+		"Message": fmt.Sprintf("Environment '%s' created", environment.Name),
+		"Name":    environment.Name,
+		"Type":    environment.EnvironmentType.Name,
+	}))
+}
+
+// EnvironmentRenamed function Message after an environment is renamed
+func EnvironmentRenamed(name string, newName string) {
+	ui.PrintSuccess("Environment '%s' renamed to '%s'", name, newName)
+}
+
+// EnvironmentRemoved function Message after an environment is removed
+func EnvironmentRemoved(name string) {
+	ui.PrintSuccess("Environment '%s' removed", name)
+}
+
 // EnvironmentSendSuccess function Message when sharing envirionments is
 // successfull
 func EnvironmentSendSuccess() {
@@ -54,8 +84,110 @@ func EnvironmentSendSuccess() {
 	)
 }
 
+// EnvironmentsDiff function displays the secrets and files that differ
+// between two environments.
+// Secret values are masked unless `showValues` is true.
+func EnvironmentsDiff(diff core.EnvironmentsDiff, showValues bool) {
+	if !diff.HasDrift() {
+		ui.PrintSuccess(
+			"Environments '%s' and '%s' have the same secrets and files",
+			diff.EnvironmentA,
+			diff.EnvironmentB,
+		)
+		return
+	}
+
+	if len(diff.Secrets) > 0 {
+		t := table.NewWriter()
+		t.SetOutputMirror(os.Stdout)
+		t.SetStyle(table.StyleRounded)
+		t.AppendHeader(table.Row{
+			"Secret name", diff.EnvironmentA, diff.EnvironmentB, "",
+		})
+
+		for _, secretDiff := range diff.Secrets {
+			t.AppendRow(table.Row{
+				secretDiff.Name,
+				diffValue(secretDiff.ValueA, secretDiff.InA, showValues),
+				diffValue(secretDiff.ValueB, secretDiff.InB, showValues),
+				secretDiff.Kind,
+			})
+		}
+
+		t.Render()
+	}
+
+	if len(diff.Files) > 0 {
+		t := table.NewWriter()
+		t.SetOutputMirror(os.Stdout)
+		t.SetStyle(table.StyleRounded)
+		t.AppendHeader(table.Row{
+			"File", diff.EnvironmentA, diff.EnvironmentB, "",
+		})
+
+		for _, fileDiff := range diff.Files {
+			t.AppendRow(table.Row{
+				fileDiff.Path,
+				diffHash(fileDiff.HashA),
+				diffHash(fileDiff.HashB),
+				fileDiff.Kind,
+			})
+		}
+
+		t.Render()
+	}
+}
+
+// EnvironmentsDiffJSON function prints the differences between two
+// environments as JSON, for scripts.
+// Secret values are left out unless `showValues` is true.
+func EnvironmentsDiffJSON(diff core.EnvironmentsDiff, showValues bool) {
+	if !showValues {
+		secrets := make([]core.SecretDiff, len(diff.Secrets))
+
+		for index, secretDiff := range diff.Secrets {
+			secretDiff.ValueA = ""
+			secretDiff.ValueB = ""
+			secrets[index] = secretDiff
+		}
+
+		diff.Secrets = secrets
+	}
+
+	out, err := json.MarshalIndent(diff, "", "  ")
+	if err != nil {
+		ui.PrintError(err.Error())
+		return
+	}
+
+	fmt.Println(string(out))
+}
+
 // ———— PRIVATE UTILITIES ———— //
 
+// diffValue returns what to display for a secret value in a diff
+func diffValue(value string, present bool, showValues bool) string {
+	switch {
+	case !present:
+		return "(missing)"
+	case value == "":
+		return "(empty)"
+	case !showValues:
+		return "********"
+	default:
+		return displayValue(value)
+	}
+}
+
+// diffHash returns what to display for a file hash in a diff
+func diffHash(hash string) string {
+	if hash == "" {
+		return "(missing)"
+	}
+
+	return hash[:12]
+}
+
 func pathList(files []keystonefile.FileKey) []string {
 	r := make([]string, len(files))
 
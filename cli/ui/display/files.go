@@ -85,6 +85,22 @@ It has also been gitignored.`, map[string]string{
 	}))
 }
 
+// DirectoryAddSuccess function Message when adding a directory is successfull
+func DirectoryAddSuccess(
+	directoryPath string,
+	numberOfFiles int,
+	numberOfEnvironments int,
+) {
+	ui.Print(ui.RenderTemplate("directory add success", `
+{{ OK }} {{ .Title | green }}
+{{ .NumberFiles }} file(s) have been added to {{ .NumberEnvironments }} environment(s).
+The directory has also been gitignored.`, map[string]string{
+		"Title":              fmt.Sprintf("Added '%s'", directoryPath),
+		"NumberFiles":        fmt.Sprintf("%d", numberOfFiles),
+		"NumberEnvironments": fmt.Sprintf("%d", numberOfEnvironments),
+	}))
+}
+
 // FileAskForFileContentForEnvironment function Ask file content
 func FileAskForFileContentForEnvironment(filePath, environmentName string) {
 	ui.Print(
@@ -128,6 +144,24 @@ func FileIsNowRequired(filePath string) {
 	))
 }
 
+// FileIsNowIn function Message when changing the required status of a file
+// for a single environment
+func FileIsNowIn(filePath, prop, environmentName string) {
+	ui.Print(ui.RenderTemplate(
+		"set file optional in environment",
+		`File {{ .FilePath }} is now {{ .Prop }} in {{ .Environment }}.`,
+		struct {
+			FilePath    string
+			Prop        string
+			Environment string
+		}{
+			FilePath:    filePath,
+			Prop:        prop,
+			Environment: environmentName,
+		},
+	))
+}
+
 // FileNotManaged function Message when the file is not managed by Keystone
 func FileNotManaged(filePath string) {
 	ui.Print("File '" + filePath + "' is not managed by Keystone, ignoring")
@@ -147,6 +181,51 @@ func FileRemovedSuccess(filePath string) {
 	ui.PrintSuccess("%s has been removed from the secret files.", filePath)
 }
 
+// WorldReadableFiles function warns about secret files anyone can read
+func WorldReadableFiles(files []string) {
+	ui.PrintStdErr(ui.RenderTemplate("world readable files", `
+{{- "Warning!" | yellow }} These files can be read by anyone on this computer:
+{{ range . }}  {{ . }}
+{{ end }}Restrict them with: chmod o-r <file>`, files))
+}
+
+// LocalFileDiffs function prints the local changes to files,
+// as unified diffs
+func LocalFileDiffs(diffs []core.LocalFileDiff) {
+	for _, diff := range diffs {
+		if diff.Binary {
+			ui.Print(fmt.Sprintf(
+				"Binary files a/%s and b/%s differ",
+				diff.Path,
+				diff.Path,
+			))
+			continue
+		}
+
+		if diff.TooDifferent {
+			ui.Print(fmt.Sprintf(
+				"Files a/%s and b/%s differ by too many lines to be shown",
+				diff.Path,
+				diff.Path,
+			))
+			continue
+		}
+
+		for _, line := range strings.Split(strings.TrimSuffix(diff.Diff, "\n"), "\n") {
+			ui.Print(diffLine(line))
+		}
+	}
+}
+
+// NoLocalFileChanges function Message when the local files are the same
+// as the cached ones
+func NoLocalFileChanges(environmentName string) {
+	ui.PrintSuccess(
+		"The local files are the same as in the '%s' environment",
+		environmentName,
+	)
+}
+
 // FileSetSuccess function Message when file content updated successfully
 func FileSetSuccess(filePath string) {
 	ui.Print(ui.RenderTemplate("file set success", `
@@ -158,6 +237,28 @@ func FileSetSuccess(filePath string) {
 
 // ———— PRIVATE Utilities ———— //
 
+// diffLine colors a line of a unified diff
+func diffLine(line string) string {
+	color := ""
+
+	switch {
+	case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+		// Headers are left as is
+	case strings.HasPrefix(line, "@@"):
+		color = "cyan"
+	case strings.HasPrefix(line, "+"):
+		color = "green"
+	case strings.HasPrefix(line, "-"):
+		color = "red"
+	}
+
+	if color == "" {
+		return line
+	}
+
+	return ui.RenderTemplate("diff line", "{{ . | "+color+" }}", line)
+}
+
 // File list when no quiet
 func fileList(lines []line) {
 	ui.Print(ui.RenderTemplate("files list", `Files tracked as secret files:
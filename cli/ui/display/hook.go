@@ -23,3 +23,14 @@ func ThereIsNoHookYet() {
 func ExecutingHook(hook string) {
 	ui.PrintDim("Executing hook '%s'", hook)
 }
+
+// GitHookKept function Message when a git hook that was not installed by
+// Keystone was moved, to be run by the Keystone one
+func GitHookKept(hookPath, localHookPath string) {
+	ui.Print("%s was moved to %s, it runs before the scan", hookPath, localHookPath)
+}
+
+// GitHookInstalled function Message when a git hook was installed
+func GitHookInstalled(hookPath string) {
+	ui.PrintSuccess("Git hook installed: %s", hookPath)
+}
@@ -1,6 +1,8 @@
 package display
 
 import (
+	"sort"
+
 	"github.com/wearedevx/keystone/cli/pkg/constants"
 	"github.com/wearedevx/keystone/cli/pkg/core"
 	"github.com/wearedevx/keystone/cli/ui"
@@ -11,9 +13,7 @@ import (
 func Changes(
 	changes core.ChangesByEnvironment,
 ) {
-	for _, envName := range constants.EnvList {
-		environmentName := string(envName)
-
+	for _, environmentName := range sortedEnvironmentNames(changes) {
 		changesList, ok := changes.Environments[environmentName]
 		if !ok { // means there are no changes, and versions are equal
 			continue
@@ -29,6 +29,34 @@ func Changes(
 			printEnvironmentUpToDate(environmentName)
 		}
 	}
+
+	for _, rejected := range changes.Rejected {
+		printRejectedSecret(rejected)
+	}
+}
+
+// sortedEnvironmentNames returns the names of the environments with
+// changes: the default ones first, then the user defined ones in
+// alphabetical order
+func sortedEnvironmentNames(changes core.ChangesByEnvironment) []string {
+	names := make([]string, 0, len(changes.Environments))
+	others := make([]string, 0)
+
+	for _, envName := range constants.EnvList {
+		if _, ok := changes.Environments[string(envName)]; ok {
+			names = append(names, string(envName))
+		}
+	}
+
+	for environmentName := range changes.Environments {
+		if !core.Contains(names, environmentName) {
+			others = append(others, environmentName)
+		}
+	}
+
+	sort.Strings(others)
+
+	return append(names, others...)
 }
 
 func printChangesButNoMessage(environmentName string) {
@@ -38,6 +66,15 @@ func printChangesButNoMessage(environmentName string) {
 	)
 }
 
+func printRejectedSecret(rejected core.RejectedSecret) {
+	ui.PrintStdErr(
+		"The value received for %s in %s was not saved ⨯",
+		rejected.Name,
+		rejected.Environment,
+	)
+	ui.PrintStdErr(rejected.Err.Error())
+}
+
 func printEnvironmentUpToDate(environmentName string) {
 	ui.PrintStdErr("Environment " + environmentName + " up to date ✔")
 }
@@ -0,0 +1,23 @@
+package display
+
+import "github.com/wearedevx/keystone/cli/ui"
+
+// TemplateRendered function Message when a template is written to `output`
+func TemplateRendered(output string) {
+	ui.PrintSuccess("Rendered %s", output)
+}
+
+// TemplatesRendered function Message when the templates listed in
+// keystone.yaml are rendered for `environmentName`
+func TemplatesRendered(count int, environmentName string) {
+	if count == 0 {
+		ui.Print("No templates in keystone.yaml")
+		return
+	}
+
+	ui.PrintSuccess(
+		"%d template(s) rendered for the '%s' environment",
+		count,
+		environmentName,
+	)
+}
@@ -0,0 +1,37 @@
+package display
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/wearedevx/keystone/cli/internal/scanner"
+	"github.com/wearedevx/keystone/cli/ui"
+)
+
+// Leaks function displays where secret values were found by `ks scan`.
+// Values are never displayed.
+func Leaks(leaks []scanner.Leak) {
+	if len(leaks) == 0 {
+		ui.PrintSuccess("No secret value found")
+		return
+	}
+
+	ui.Print(ui.RenderTemplate("leaks", `
+{{ ERROR }} {{ "Secret values found" | red }}
+{{ range . }}  {{ .Path }}:{{ .Line }}: {{ .Name | yellow }}
+{{ end }}
+Remove them from these files, and get them from Keystone instead:
+  $ ks source
+  $ ks render <template>`, leaks))
+}
+
+// LeaksJSON function prints the result of `ks scan` as JSON
+func LeaksJSON(leaks []scanner.Leak) {
+	out, err := json.MarshalIndent(leaks, "", "  ")
+	if err != nil {
+		ui.PrintError(err.Error())
+		return
+	}
+
+	fmt.Println(string(out))
+}
@@ -3,8 +3,11 @@ package display
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/wearedevx/keystone/cli/internal/history"
+	"github.com/wearedevx/keystone/cli/internal/utils"
 	"github.com/wearedevx/keystone/cli/pkg/core"
 	"github.com/wearedevx/keystone/cli/ui"
 )
@@ -33,14 +36,37 @@ func SecretTable(secrets []core.Secret, environments []string) {
 		envHeader = append(envHeader, environment)
 	}
 
+	// Only take room for documentation if there is some
+	withDocumentation := false
+	for _, secret := range secrets {
+		if secret.Documentation.Description != "" ||
+			secret.Documentation.Owner != "" {
+			withDocumentation = true
+			break
+		}
+	}
+
+	if withDocumentation {
+		topHeader = append(topHeader, "Description", "Owner")
+		envHeader = append(envHeader, "", "")
+	}
+
 	t.AppendHeader(topHeader, table.RowConfig{AutoMerge: true})
 	t.AppendHeader(envHeader)
 
+	withInheritance := false
+
 	for _, secret := range secrets {
 		name := secret.Name
 
 		if secret.Required {
 			name = name + " *"
+		} else if len(secret.RequiredIn) > 0 {
+			name = fmt.Sprintf(
+				"%s * (%s)",
+				name,
+				strings.Join(secret.RequiredIn, ", "),
+			)
 		}
 		if secret.FromCache {
 			name = name + " A"
@@ -51,12 +77,21 @@ func SecretTable(secrets []core.Secret, environments []string) {
 		for _, environment := range environments {
 			value := secret.Values[core.EnvironmentName(environment)]
 
-			if len(value) > 40 {
-				value = value[:40]
-				value += "..."
+			cell := displayValue(string(value))
+			if parent, ok := secret.InheritedFrom[core.EnvironmentName(environment)]; ok {
+				cell = fmt.Sprintf("%s ↑ %s", cell, parent)
+				withInheritance = true
 			}
 
-			row = append(row, value)
+			row = append(row, cell)
+		}
+
+		if withDocumentation {
+			row = append(
+				row,
+				truncateValue(secret.Documentation.Description),
+				secret.Documentation.Owner,
+			)
 		}
 
 		t.AppendRow(row)
@@ -64,13 +99,17 @@ func SecretTable(secrets []core.Secret, environments []string) {
 
 	t.Render()
 	fmt.Println(" * Required secrets; A Available secrets")
+
+	if withInheritance {
+		fmt.Println(" ↑ <environment> Value inherited from <environment>")
+	}
 }
 
 // SecretAlreadyExitsts function Messsage when secret already exists
 func SecretAlreadyExitsts(values map[core.EnvironmentName]core.SecretValue) {
 	ui.Print(`The secret already exist. Values are:`)
 	for env, value := range values {
-		ui.Print(`%s: %s`, env, value)
+		ui.Print(`%s: %s`, env, displayValue(string(value)))
 	}
 }
 
@@ -128,3 +167,253 @@ func SecretIsNow(secretName, prop string) {
 `)
 	}
 }
+
+// SecretIsNowIn function Message when changing the required status of a
+// secret for a single environment
+func SecretIsNowIn(secretName, prop, environmentName string) {
+	template := `Secret {{ .SecretName }} is now {{ .Prop }} in {{ .Environment }}.`
+
+	ui.Print(
+		ui.RenderTemplate(
+			"set secret optional in environment",
+			template,
+			struct {
+				SecretName  string
+				Prop        string
+				Environment string
+			}{
+				SecretName:  secretName,
+				Prop:        prop,
+				Environment: environmentName,
+			},
+		),
+	)
+
+	if prop == REQUIRED {
+		ui.Print(`If you have setup a CI service, don’t forget to run:
+  $ ks ci send --env ` + environmentName + `
+`)
+	}
+}
+
+// SecretHistory function displays the recorded values of a secret,
+// numbered so they can be used with `ks secret rollback`
+func SecretHistory(
+	secretName, environmentName string,
+	entries []history.Entry,
+) {
+	if len(entries) == 0 {
+		ui.Print(
+			"No history for secret '%s' in the '%s' environment",
+			secretName,
+			environmentName,
+		)
+		return
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(table.StyleRounded)
+	t.AppendHeader(table.Row{"#", "Date", "Sender", "Change", "Value"})
+
+	for i, entry := range entries {
+		value := displayValue(entry.Value)
+		if entry.Type == history.EntryDelete {
+			value = "(removed)"
+		}
+
+		t.AppendRow(table.Row{
+			i + 1,
+			entry.Date.Local().Format("2006-01-02 15:04:05"),
+			entry.Sender,
+			string(entry.Type),
+			value,
+		})
+	}
+
+	t.Render()
+}
+
+// SecretRolledBack function Message when a secret was set back to
+// a past value
+func SecretRolledBack(secretName, environmentName string, entry int) {
+	ui.PrintSuccess(
+		"Secret '%s' rolled back to entry %d for the '%s' environment",
+		secretName,
+		entry,
+		environmentName,
+	)
+}
+
+// SecretDescribed function Message when secret describe is successfull
+func SecretDescribed(secretName string) {
+	ui.PrintSuccess("Documentation of secret '%s' updated", secretName)
+}
+
+// SecretInfo function displays what is known about a secret,
+// and whether it has a value in each environment, without showing values
+func SecretInfo(secret core.Secret, environments []string) {
+	required := "optional"
+	if secret.Required {
+		required = "required"
+	} else if len(secret.RequiredIn) > 0 {
+		required = "required in " + strings.Join(secret.RequiredIn, ", ")
+	}
+
+	documentation := secret.Documentation
+
+	ui.Print("%s (%s)", secret.Name, required)
+
+	if documentation.Description != "" {
+		ui.Print("\n" + documentation.Description)
+	}
+
+	if documentation.Owner != "" || documentation.DocsURL != "" {
+		fmt.Println()
+	}
+
+	if documentation.Owner != "" {
+		ui.Print("Owner: " + documentation.Owner)
+	}
+
+	if documentation.DocsURL != "" {
+		ui.Print("Documentation: " + documentation.DocsURL)
+	}
+
+	fmt.Println()
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(table.StyleRounded)
+	t.AppendHeader(table.Row{"Environment", "Value"})
+
+	for _, environment := range environments {
+		value, ok := secret.Values[core.EnvironmentName(environment)]
+		parent, inherited := secret.InheritedFrom[core.EnvironmentName(environment)]
+		status := "set"
+
+		switch {
+		case !ok:
+			status = "missing"
+		case value == "":
+			status = "empty"
+		case inherited:
+			status = fmt.Sprintf("inherited from %s", parent)
+		}
+
+		t.AppendRow(table.Row{environment, status})
+	}
+
+	t.Render()
+}
+
+// SecretsInventory function prints a markdown document listing the
+// secrets of the project and their documentation
+func SecretsInventory(projectName string, secrets []core.Secret) {
+	fmt.Printf("# %s secrets\n\n", projectName)
+	fmt.Println("| Name | Required | Description | Owner | Documentation |")
+	fmt.Println("|------|----------|-------------|-------|---------------|")
+
+	for _, secret := range secrets {
+		required := "no"
+		if secret.Required {
+			required = "yes"
+		} else if len(secret.RequiredIn) > 0 {
+			required = strings.Join(secret.RequiredIn, ", ")
+		}
+
+		docsURL := secret.Documentation.DocsURL
+		if docsURL != "" {
+			docsURL = fmt.Sprintf("[link](%s)", docsURL)
+		}
+
+		fmt.Printf(
+			"| `%s` | %s | %s | %s | %s |\n",
+			secret.Name,
+			required,
+			markdownCell(secret.Documentation.Description),
+			markdownCell(secret.Documentation.Owner),
+			docsURL,
+		)
+	}
+}
+
+// SecretImportTable function displays the differences between the secrets
+// about to be imported and the ones in cache for `environmentName`
+func SecretImportTable(imports []core.SecretImport, environmentName string) {
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(table.StyleRounded)
+
+	t.AppendHeader(table.Row{
+		"Secret name",
+		"Current (" + environmentName + ")",
+		"Imported",
+		"",
+	})
+
+	for _, secretImport := range imports {
+		status := "unchanged"
+
+		switch {
+		case secretImport.IsNew():
+			status = "new"
+		case secretImport.IsConflict():
+			status = "changed"
+		}
+
+		t.AppendRow(table.Row{
+			secretImport.Name,
+			displayValue(secretImport.Current),
+			displayValue(secretImport.Imported),
+			status,
+		})
+	}
+
+	t.Render()
+}
+
+// SecretsImported function Message when secret import is successfull
+func SecretsImported(count int, environmentName string) {
+	ui.PrintSuccess(
+		"%d secret(s) imported in the '%s' environment",
+		count,
+		environmentName,
+	)
+}
+
+func truncateValue(value string) string {
+	// Cut between characters, not in the middle of one
+	if runes := []rune(value); len(runes) > 40 {
+		value = string(runes[:40])
+		value += "..."
+	}
+
+	return value
+}
+
+// displayValue returns a secret value as it should appear on a single
+// line: binary values are replaced by their size, and only the first line
+// of multi-line ones is shown
+func displayValue(value string) string {
+	if utils.IsBinary(value) {
+		return fmt.Sprintf("(binary, %d bytes)", len(value))
+	}
+
+	value = strings.ReplaceAll(value, "\r\n", "\n")
+	lines := strings.Split(strings.TrimSuffix(value, "\n"), "\n")
+
+	if len(lines) == 1 {
+		return truncateValue(lines[0])
+	}
+
+	return fmt.Sprintf("%s … (+%d lines)", truncateValue(lines[0]), len(lines)-1)
+}
+
+// markdownCell makes `value` safe to use in a markdown table cell
+func markdownCell(value string) string {
+	value = strings.ReplaceAll(value, "|", "\\|")
+	value = strings.ReplaceAll(value, "\n", " ")
+
+	return value
+}
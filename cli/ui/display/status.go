@@ -0,0 +1,97 @@
+package display
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/wearedevx/keystone/cli/pkg/core"
+	"github.com/wearedevx/keystone/cli/ui"
+)
+
+// Status function displays the overview of a project given by `ks status`
+func Status(status core.Status) {
+	ui.Print(ui.RenderTemplate("status header", `
+Project: {{ .ProjectName | yellow }}
+Environment: {{ .CurrentEnvironment | blue }}
+`, status))
+
+	if !status.Online {
+		ui.Print("Server: unreachable, versions and messages are unknown")
+	} else if status.PendingMessages > 0 {
+		ui.Print(
+			"Server: %d environment(s) with pending messages, run `ks secret` to fetch them",
+			status.PendingMessages,
+		)
+	} else {
+		ui.Print("Server: no pending messages")
+	}
+
+	fmt.Println()
+
+	for _, environment := range status.Environments {
+		problems := make([]string, 0)
+
+		if environment.Outdated {
+			problems = append(problems, "behind the server")
+		}
+		if environment.PendingMessage {
+			problems = append(problems, "pending message")
+		}
+		if len(environment.MissingSecrets) > 0 {
+			problems = append(
+				problems,
+				"missing secrets: "+strings.Join(environment.MissingSecrets, ", "),
+			)
+		}
+		if len(environment.MissingFiles) > 0 {
+			problems = append(
+				problems,
+				"missing files: "+strings.Join(environment.MissingFiles, ", "),
+			)
+		}
+
+		ui.Print(ui.RenderTemplate("status environment", `
+{{- if .Problems }} {{ "✘" | red }} {{ .Name }}: {{ .Problems }}
+{{- else }} {{ "✔" | green }} {{ .Name }}: up to date
+{{- end }}`, map[string]string{
+			"Name":     environment.Name,
+			"Problems": strings.Join(problems, "; "),
+		}))
+	}
+
+	if len(status.ModifiedFiles) > 0 {
+		ui.Print("\nFiles modified locally, send them with `ks file set`:")
+		for _, file := range status.ModifiedFiles {
+			ui.Print("  " + file)
+		}
+	}
+
+	if len(status.WorldReadableFiles) > 0 {
+		fmt.Println()
+		WorldReadableFiles(status.WorldReadableFiles)
+	}
+
+	if len(status.CiServices) > 0 {
+		ui.Print("\nCI services: " + strings.Join(status.CiServices, ", "))
+	}
+
+	if !status.NeedsAction() {
+		fmt.Println()
+		ui.PrintSuccess("Nothing to do")
+	}
+}
+
+// StatusJSON function prints the overview of a project as JSON, for scripts
+func StatusJSON(status core.Status) {
+	out, err := json.MarshalIndent(struct {
+		core.Status
+		NeedsAction bool `json:"needs_action"`
+	}{status, status.NeedsAction()}, "", "  ")
+	if err != nil {
+		ui.PrintError(err.Error())
+		return
+	}
+
+	fmt.Println(string(out))
+}
@@ -0,0 +1,52 @@
+package display
+
+import (
+	"os"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/wearedevx/keystone/cli/internal/workspacefile"
+	"github.com/wearedevx/keystone/cli/ui"
+)
+
+// WorkspaceProjects function displays the projects of a workspace.
+// `names` maps each project path to its Keystone project name.
+func WorkspaceProjects(
+	workspace *workspacefile.WorkspaceFile,
+	names map[string]string,
+) {
+	if len(workspace.Projects) == 0 {
+		ui.Print("No project in the workspace at %s", workspace.Root())
+		return
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(table.StyleRounded)
+	t.AppendHeader(table.Row{"Path", "Project", "Prefix"})
+
+	for _, project := range workspace.Projects {
+		t.AppendRow(table.Row{
+			project.Path,
+			names[project.Path],
+			workspace.SecretPrefix(project),
+		})
+	}
+
+	t.Render()
+}
+
+// WorkspaceProjectAdded function Message after a project is added
+// to the workspace
+func WorkspaceProjectAdded(projectPath string, prefix string) {
+	ui.PrintSuccess(
+		"Project '%s' added to the workspace, its secrets are prefixed with %s",
+		projectPath,
+		prefix,
+	)
+}
+
+// WorkspaceProjectRemoved function Message after a project is removed
+// from the workspace
+func WorkspaceProjectRemoved(projectPath string) {
+	ui.PrintSuccess("Project '%s' removed from the workspace", projectPath)
+}
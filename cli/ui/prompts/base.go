@@ -1,6 +1,7 @@
 package prompts
 
 import (
+	"errors"
 	"os"
 	"strings"
 
@@ -100,3 +101,32 @@ func Select(message string, items []string) (index int, selected string) {
 
 	return index, selected
 }
+
+// Ask the user to enter a secret input, masking what they type.
+// An empty answer is refused.
+func PasswordInput(message string) string {
+	p := promptui.Prompt{
+		Label: message,
+		Mask:  '*',
+		// Keep stdout clean, for `eval $(ks source)`
+		Stdout: os.Stderr,
+		Validate: func(input string) error {
+			if input == "" {
+				return errors.New("cannot be empty")
+			}
+			return nil
+		},
+	}
+
+	answer, err := p.Run()
+
+	if err != nil {
+		if err.Error() != "^C" && err.Error() != "" {
+			ui.PrintError(err.Error())
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	return answer
+}
@@ -272,6 +272,25 @@ This is permanent, and cannot be undone.
 	return projectName == result
 }
 
+// ConfirmEnvironmentRemoval asks the user to confirm the removal
+// of an environment
+func ConfirmEnvironmentRemoval(environmentName string, skipPrompts bool) bool {
+	if skipPrompts {
+		return true
+	}
+
+	ui.Print(ui.RenderTemplate("confirm env rm",
+		`{{ CAREFUL }} You are about to remove the {{ .Environment }} environment.
+Its secrets and files WILL BE LOST for all members of the project.
+
+This is permanent, and cannot be undone.
+`, map[string]string{
+			"Environment": environmentName,
+		}))
+
+	return Confirm("Continue")
+}
+
 // ———— ORGANIZATION PROMPTS ————— //
 
 // Asks the usre to select from a list of organizations
@@ -331,6 +350,26 @@ func PasswordToDecrypt() string {
 	return StringInput("Password to decrypt backup", "")
 }
 
+// KeyPassphrase function prompts the user the passphrase protecting
+// their private key
+func KeyPassphrase() string {
+	return PasswordInput("Passphrase to unlock your private key")
+}
+
+// NewKeyPassphrase function prompts the user a passphrase to protect
+// their private key, twice
+func NewKeyPassphrase() string {
+	for {
+		passphrase := PasswordInput("Passphrase to protect your private key")
+
+		if PasswordInput("Repeat the passphrase") == passphrase {
+			return passphrase
+		}
+
+		ui.PrintError("The passphrases do not match")
+	}
+}
+
 // ConfirmDotKeystonDirRemoval function aks confirmation for complete .keystone
 // removal
 func ConfirmDotKeystonDirRemoval() bool {
@@ -390,6 +429,15 @@ This is permanent, and cannot be undone.`,
 	return Confirm("Continue")
 }
 
+// ConfirmFileChangesPublication function asks confirmation to share the
+// local changes to files, as `ks file set` does
+func ConfirmFileChangesPublication(environmentName string) bool {
+	return Confirm(fmt.Sprintf(
+		"Set these files for the '%s' environment, and share them with its members",
+		environmentName,
+	))
+}
+
 // ——— SECRETS PROMPTS ——— //
 
 // ConfirmOverrideSecretValue function asks confirmation to override existing
@@ -415,6 +463,23 @@ func ValueForEnvironment(
 	return StringInput(secretName, defaultValue)
 }
 
+// ImportStrategyForSecret function asks the user what to do with a secret
+// whose imported value differs from the current one
+func ImportStrategyForSecret(secretName string) core.ImportStrategy {
+	items := make([]string, len(core.ImportStrategies))
+
+	for index, strategy := range core.ImportStrategies {
+		items[index] = string(strategy)
+	}
+
+	_, selected := Select(
+		fmt.Sprintf("'%s' has a different value, what should be done", secretName),
+		items,
+	)
+
+	return core.ImportStrategy(selected)
+}
+
 // ——— LOGIN PROMPTS ———— //
 
 // SelectAuthService function asks the user which third party to use